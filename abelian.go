@@ -0,0 +1,55 @@
+package treepair
+
+import (
+	"unicode/utf8"
+
+	"github.com/loeksnokes/prefcode"
+)
+
+// Abelianize returns the image of the element tp of F in F/[F,F], which is free abelian of
+// rank two.  Viewing tp as a piecewise-linear map of [0,1], the two coordinates are the
+// exponents of its slopes at 0 and at 1, as powers of the alphabet size.  Over "01" the
+// generator x0 goes to (-1, 1) and every x_n with n > 0 to (0, 1).  Elements outside F give
+// (0, 0).  tp itself is not modified.
+func Abelianize(tp TreePair) (int, int) {
+	min := clone(tp)
+	min.Minimise()
+	if !min.InF() {
+		return 0, 0
+	}
+	return SlopeAtZero(min), SlopeAtOne(min)
+}
+
+// SlopeAtZero returns the exponent, as a power of the alphabet size, of the slope of tp just
+// to the right of 0: the first leaf d of the minimised domain tree goes to a range leaf r, so
+// the cone at d is scaled by |alphabet|^(|d|-|r|).  tp itself is not modified.
+func SlopeAtZero(tp TreePair) int {
+	return endSlope(tp, 0)
+}
+
+// SlopeAtOne returns the exponent, as a power of the alphabet size, of the slope of tp just to
+// the left of 1, read off the last leaf of the minimised domain tree as in SlopeAtZero.  tp
+// itself is not modified.
+func SlopeAtOne(tp TreePair) int {
+	return endSlope(tp, -1)
+}
+
+// endSlope returns |d| - |r| for the domain leaf d of the minimised tp at position k in
+// dictionary order (counting from the end if k is negative) and the range leaf r it goes to.
+func endSlope(tp TreePair, k int) int {
+	min := clone(tp)
+	min.Minimise()
+	pairs := leafPairs(min)
+	if k < 0 {
+		k += len(pairs)
+	}
+	return wordLength(pairs[k][0]) - wordLength(pairs[k][1])
+}
+
+// wordLength returns the number of letters of the word w, taking the empty word as 0.
+func wordLength(w string) int {
+	if prefcode.EmptyString == w {
+		return 0
+	}
+	return utf8.RuneCountInString(w)
+}
@@ -0,0 +1,52 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAbelian(t *testing.T) {
+
+	abelianize := func(t *testing.T, word string) [2]int {
+		t.Helper()
+		tp, err := EvaluateWord("01", word)
+		assert.Nil(t, err)
+		at0, at1 := Abelianize(tp)
+		return [2]int{at0, at1}
+	}
+
+	t.Run("Abelianize test", func(t *testing.T) {
+		assert.Equal(t, [2]int{0, 0}, abelianize(t, ""))
+		assert.Equal(t, [2]int{-1, 1}, abelianize(t, "x0"))
+		assert.Equal(t, [2]int{0, 1}, abelianize(t, "x1"))
+		assert.Equal(t, [2]int{0, 1}, abelianize(t, "x4"))
+		assert.Equal(t, [2]int{1, -1}, abelianize(t, "x0^-1"))
+		assert.Equal(t, [2]int{-2, 6}, abelianize(t, "x0^2 x1 x3^2 x0^-1 x2 x0"))
+	})
+
+	t.Run("Abelianize kills commutators test", func(t *testing.T) {
+		assert.Equal(t, [2]int{0, 0}, abelianize(t, "x0 x1 x0^-1 x1^-1"))
+		assert.Equal(t, [2]int{0, 0}, abelianize(t, "x2^3 x0^-1 x2^-3 x0"))
+	})
+
+	t.Run("Abelianize outside F test", func(t *testing.T) {
+		assert.Equal(t, [2]int{0, 0}, abelianize(t, "C"))
+	})
+
+	t.Run("SlopeAtZero and SlopeAtOne test", func(t *testing.T) {
+		for word, want := range map[string][2]int{
+			"":          {0, 0},
+			"x0":        {-1, 1},
+			"x0^-3":     {3, -3},
+			"x2":        {0, 1},
+			"C":         {-1, 0},
+			"pi0":       {0, 0},
+			"pi0 x0^-1": {1, -1},
+		} {
+			tp, err := EvaluateWord("01", word)
+			assert.Nil(t, err)
+			assert.Equal(t, want, [2]int{SlopeAtZero(tp), SlopeAtOne(tp)}, word)
+		}
+	})
+}
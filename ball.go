@@ -0,0 +1,87 @@
+package treepair
+
+import (
+	"errors"
+	"strconv"
+)
+
+// BallF returns the elements of R. Thompson's group F of word length at most radius in the
+// generators x0 and x1, in order of word length, each once as a minimised tree pair.
+func BallF(radius int) []TreePair {
+	x0, _ := GeneratorX(0)
+	x1, _ := GeneratorX(1)
+	var ball []TreePair
+	forEachInBall([]TreePair{x0, x1}, radius, func(tp TreePair, length int) bool {
+		ball = append(ball, tp)
+		return true
+	})
+	return ball
+}
+
+// SphereSizes returns the number of elements of each word length from 0 to maxRadius in the
+// generators gens (and their inverses), which must all be over the same alphabet.
+func SphereSizes(gens []TreePair, maxRadius int) ([]int, error) {
+	if err := checkGenerators(gens); nil != err {
+		return nil, errors.New("SphereSizes(): " + err.Error())
+	}
+	if maxRadius < 0 {
+		return nil, errors.New("SphereSizes(): negative radius " + strconv.Itoa(maxRadius))
+	}
+	sizes := make([]int, maxRadius+1)
+	forEachInBall(gens, maxRadius, func(tp TreePair, length int) bool {
+		sizes[length]++
+		return true
+	})
+	return sizes, nil
+}
+
+// checkGenerators reports an error unless gens is a nonempty list over one alphabet.
+func checkGenerators(gens []TreePair) error {
+	if 0 == len(gens) {
+		return errors.New("no generators")
+	}
+	for _, g := range gens[1:] {
+		if string(g.Alphabet()) != string(gens[0].Alphabet()) {
+			return errors.New("generators are over different alphabets")
+		}
+	}
+	return nil
+}
+
+// forEachInBall walks the Cayley graph with generators gens and their inverses breadth first
+// from the identity, calling visit on each element of word length at most radius, with that
+// length, until visit returns false.  gens must not be empty.  Returns false if visit stopped early.
+// The elements of the next sphere are kept as clones, which hold their codes as hash-consed
+// trees sharing most of their nodes.
+func forEachInBall(gens []TreePair, radius int, visit func(tp TreePair, length int) bool) bool {
+	steps := make([]TreePair, 0, 2*len(gens))
+	for _, g := range gens {
+		inverse := clone(g)
+		inverse.Invert()
+		steps = append(steps, clone(g), inverse)
+	}
+
+	seen := NewTreePairSet()
+	sphere := []TreePair{identityOver(gens[0].Alphabet())}
+	seen.Add(sphere[0])
+	for length := 0; length <= radius && len(sphere) > 0; length++ {
+		var next []TreePair
+		for _, tp := range sphere {
+			if !visit(tp, length) {
+				return false
+			}
+			if length == radius {
+				continue
+			}
+			for _, s := range steps {
+				product := Multiply(clone(tp), clone(s))
+				if seen.Add(product) {
+					product.Minimise()
+					next = append(next, clone(product))
+				}
+			}
+		}
+		sphere = next
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBall(t *testing.T) {
+
+	// the spheres of F in x0 and x1 have 1, 4, 12 and 36 elements.
+	t.Run("BallF test", func(t *testing.T) {
+		for radius, want := range []int{1, 5, 17, 53} {
+			assert.Equal(t, want, len(BallF(radius)))
+		}
+		ball := BallF(2)
+		assert.True(t, ball[0].IsIdentity())
+		for _, word := range []string{"x0", "x1^-1", "x0 x1", "x1^-2", "x0 x1^-1"} {
+			tp, _ := EvaluateWord("01", word)
+			found := false
+			for _, v := range ball {
+				found = found || EqualsAsElements(v, tp)
+			}
+			assert.True(t, found, word)
+		}
+	})
+
+	t.Run("SphereSizes test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		x1, _ := GeneratorX(1)
+		sizes, err := SphereSizes([]TreePair{x0, x1}, 4)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 4, 12, 36, 108}, sizes)
+
+		// C has order 3, and the swap pi0 has order 2.
+		c, _ := GeneratorC()
+		pi0, _ := GeneratorPi0()
+		sizes, err = SphereSizes([]TreePair{c}, 3)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 2, 0, 0}, sizes)
+		sizes, err = SphereSizes([]TreePair{pi0}, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 1, 0}, sizes)
+
+		_, err = SphereSizes(nil, 2)
+		assert.NotNil(t, err)
+		_, err = SphereSizes([]TreePair{c}, -1)
+		assert.NotNil(t, err)
+		ternary, _ := NewTreePairAlpha("012")
+		_, err = SphereSizes([]TreePair{c, ternary}, 2)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("forEachInBall test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		lengths := map[int]int{}
+		assert.True(t, forEachInBall([]TreePair{c}, 5, func(tp TreePair, length int) bool {
+			lengths[length]++
+			return true
+		}))
+		assert.Equal(t, map[int]int{0: 1, 1: 2}, lengths)
+	})
+}
@@ -0,0 +1,61 @@
+package treepair
+
+import (
+	"errors"
+	"math/big"
+)
+
+// BumpFactorsF returns the one-bump factors of the element tp of F, in order from left to
+// right.  Each factor agrees with tp on one component of its support and is the identity
+// elsewhere, so the factors commute and multiply to tp.  An element of F cannot break at a
+// non-dyadic point, so components meeting at a non-dyadic fixed point stay together in one
+// factor.  The identity has no factors.  tp itself is not modified.
+func BumpFactorsF(tp TreePair) ([]TreePair, error) {
+	breaks, err := ToPL(tp)
+	if nil != err {
+		return nil, errors.New("BumpFactorsF(): element is not in F")
+	}
+	fixed, err := FixedPoints(tp)
+	if nil != err {
+		return nil, err
+	}
+
+	// the cuts between factors are the fixed intervals and the dyadic fixed points.
+	var cuts []FixedInterval
+	for _, v := range fixed {
+		if !v.IsPoint() || isDyadic(v.Left) {
+			cuts = append(cuts, v)
+		}
+	}
+
+	var factors []TreePair
+	for k := 0; k+1 < len(cuts); k++ {
+		a, b := cuts[k].Right, cuts[k+1].Left
+		factor, err := FromPL(bumpBreakpoints(breaks, a, b))
+		if nil != err {
+			return nil, err
+		}
+		factors = append(factors, factor)
+	}
+	return factors, nil
+}
+
+// bumpBreakpoints returns the breakpoints of the map agreeing with breaks on [a,b] and with
+// the identity elsewhere.
+func bumpBreakpoints(breaks []Breakpoint, a, b *big.Rat) []Breakpoint {
+	one := big.NewRat(1, 1)
+	var bump []Breakpoint
+	if 0 != a.Sign() {
+		bump = append(bump, Breakpoint{X: new(big.Rat), Y: new(big.Rat), Slope: one})
+	}
+	bump = append(bump, Breakpoint{X: a, Y: a, Slope: breaks[pieceAt(breaks, a)].Slope})
+	for _, v := range breaks {
+		if v.X.Cmp(a) > 0 && v.X.Cmp(b) < 0 {
+			bump = append(bump, v)
+		}
+	}
+	if b.Cmp(one) < 0 {
+		bump = append(bump, Breakpoint{X: b, Y: b, Slope: one})
+	}
+	return bump
+}
@@ -0,0 +1,60 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBumps(t *testing.T) {
+
+	// productOf multiplies out factors, which commute.
+	productOf := func(factors []TreePair) TreePair {
+		product := TreePair(identityOver([]rune("01")))
+		for _, v := range factors {
+			product = Multiply(clone(product), clone(v))
+		}
+		return product
+	}
+
+	t.Run("BumpFactorsF test", func(t *testing.T) {
+		// x0 squeezed into [0,1/2], then x2 on [3/4,1] and x1 on [1/2,1].
+		left, _ := binaryFromDFS("{1101000,1110000,0 1 2 3}")
+		x1, _ := GeneratorX(1)
+		x2, _ := GeneratorX(2)
+
+		for _, c := range []struct {
+			tp    TreePair
+			count int
+		}{
+			{identityOver([]rune("01")), 0},
+			{x1, 1},
+			{left, 1},
+			{Multiply(clone(left), clone(x2)), 2},
+			{Multiply(clone(left), clone(x1)), 2},
+		} {
+			factors, err := BumpFactorsF(c.tp)
+			assert.Nil(t, err)
+			assert.Equal(t, c.count, len(factors), c.tp.FullString())
+			assert.True(t, EqualsAsElements(productOf(factors), c.tp), c.tp.FullString())
+			for _, v := range factors {
+				assert.Equal(t, 1, len(Support(v)), v.FullString())
+			}
+		}
+	})
+
+	// x0^-1 x1^2 fixes only 0, 7/12 and 1, and cannot be cut at 7/12.
+	t.Run("BumpFactorsF non-dyadic test", func(t *testing.T) {
+		tp, _ := EvaluateWord("01", "x0^-1 x1^2")
+		factors, err := BumpFactorsF(tp)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(factors))
+		assert.True(t, EqualsAsElements(factors[0], tp))
+	})
+
+	t.Run("BumpFactorsF error test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		_, err := BumpFactorsF(c)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,68 @@
+// Package calc is a thin façade over treepair for calculators, such as one compiled to
+// WebAssembly (see cmd/treepairwasm).  Every function takes the alphabet and elements as
+// strings, in DFS notation like "{11000,10100,1 2 0}" or in the Full notation of FullString,
+// and answers with a string in DFS notation or an error.  Nothing here does I/O or panics.
+package calc
+
+import (
+	"errors"
+
+	"github.com/loeksnokes/treepair"
+)
+
+// Multiply returns the product of the elements, applying the first one first.
+func Multiply(alphabet string, elements ...string) (string, error) {
+	if 0 == len(elements) {
+		return "", errors.New("Multiply(): no elements given")
+	}
+	product, err := treepair.ParseElement(alphabet, elements[0])
+	if nil != err {
+		return "", err
+	}
+	product.Minimise()
+	for _, v := range elements[1:] {
+		tp, err := treepair.ParseElement(alphabet, v)
+		if nil != err {
+			return "", err
+		}
+		product = treepair.Multiply(product, tp)
+	}
+	return product.DFSString(), nil
+}
+
+// Invert returns the minimised inverse of the element.
+func Invert(alphabet, element string) (string, error) {
+	return Power(alphabet, element, -1)
+}
+
+// Minimise returns the minimal tree pair of the element.
+func Minimise(alphabet, element string) (string, error) {
+	return Power(alphabet, element, 1)
+}
+
+// Power returns the n-th power of the element, for any integer n.
+func Power(alphabet, element string, n int) (string, error) {
+	tp, err := treepair.ParseElement(alphabet, element)
+	if nil != err {
+		return "", err
+	}
+	return treepair.Power(tp, n).DFSString(), nil
+}
+
+// Classify returns the classification of the element, as written by Classification.String.
+func Classify(alphabet, element string) (string, error) {
+	tp, err := treepair.ParseElement(alphabet, element)
+	if nil != err {
+		return "", err
+	}
+	return treepair.Classify(tp).String(), nil
+}
+
+// Render returns a picture of the minimised element in one of treepair.RenderFormats.
+func Render(alphabet, element, format string) (string, error) {
+	tp, err := treepair.ParseElement(alphabet, element)
+	if nil != err {
+		return "", err
+	}
+	return treepair.Render(tp, format)
+}
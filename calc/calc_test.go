@@ -0,0 +1,62 @@
+package calc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalc(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	// C = {10100,10100,1 2 0} has order 3.
+	c := "{10100,10100,1 2 0}"
+
+	t.Run("arithmetic test", func(t *testing.T) {
+		got, err := Multiply("01", c, c)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got, "{10100,10100,2 0 1}")
+		got, err = Multiply("01", c, c, c)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got, "{0,0,0}")
+
+		got, err = Invert("01", c)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got, "{10100,10100,2 0 1}")
+		got, err = Power("01", c, 4)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got, c)
+		got, err = Minimise("01", "{1100100,1110000,0 1 2 3}")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got, "{10100,11000,0 1 2}")
+	})
+
+	t.Run("Classify and Render test", func(t *testing.T) {
+		got, err := Classify("01", c)
+		assert.Nil(t, err)
+		assert.True(t, strings.Contains(got, "rotation number: 2/3"), got)
+		got, err = Render("01", c, "svg")
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(got, "<svg "), got)
+	})
+
+	t.Run("errors test", func(t *testing.T) {
+		_, err := Multiply("01")
+		assertCorrectMessage(t, err.Error(), "Multiply(): no elements given")
+		_, err = Multiply("01", c, "{10100,10100,1 2}")
+		assert.NotNil(t, err)
+		_, err = Invert("", c)
+		assert.NotNil(t, err)
+		_, err = Classify("01", "10100,10100,1 2 0")
+		assert.NotNil(t, err)
+		_, err = Render("01", c, "png")
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,65 @@
+package treepair
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// CayleyEdge joins the vertex From to the vertex To = From * gens[Generator] in a CayleyGraph.
+type CayleyEdge struct {
+	From, To, Generator int
+}
+
+// CayleyGraph is the ball of radius Radius about the identity in the Cayley graph of the group
+// generated by some elements.  Vertices are listed in order of word length, starting from the
+// identity, with Lengths[k] the word length of Vertices[k].  Edges join elements of the ball
+// differing by a generator on the right, including those between two vertices on the sphere.
+type CayleyGraph struct {
+	Radius   int
+	Vertices []TreePair
+	Lengths  []int
+	Edges    []CayleyEdge
+}
+
+// BuildCayleyGraph returns the ball of radius radius in the Cayley graph with generators gens,
+// which must all be over the same alphabet.  Vertices are minimised tree pairs.
+func BuildCayleyGraph(gens []TreePair, radius int) (CayleyGraph, error) {
+	if err := checkGenerators(gens); nil != err {
+		return CayleyGraph{}, errors.New("BuildCayleyGraph(): " + err.Error())
+	}
+	if radius < 0 {
+		return CayleyGraph{}, errors.New("BuildCayleyGraph(): negative radius " + strconv.Itoa(radius))
+	}
+	g := CayleyGraph{Radius: radius}
+	index := map[string]int{}
+	forEachInBall(gens, radius, func(tp TreePair, length int) bool {
+		index[tp.CanonicalString()] = len(g.Vertices)
+		g.Vertices = append(g.Vertices, tp)
+		g.Lengths = append(g.Lengths, length)
+		return true
+	})
+	for k, v := range g.Vertices {
+		for j, s := range gens {
+			if to, ok := index[Multiply(clone(v), clone(s)).CanonicalString()]; ok {
+				g.Edges = append(g.Edges, CayleyEdge{From: k, To: to, Generator: j})
+			}
+		}
+	}
+	return g, nil
+}
+
+// DOT writes the graph in the Graphviz DOT language.  Vertices are labelled by their index and
+// word length, and edges by the index of their generator.
+func (g CayleyGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph cayley {\n")
+	for k, v := range g.Lengths {
+		b.WriteString("\tv" + strconv.Itoa(k) + " [label=" + strconv.Quote(strconv.Itoa(k)+" ("+strconv.Itoa(v)+")") + "];\n")
+	}
+	for _, e := range g.Edges {
+		b.WriteString("\tv" + strconv.Itoa(e.From) + " -> v" + strconv.Itoa(e.To) + " [label=" + strconv.Quote("g"+strconv.Itoa(e.Generator)) + "];\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
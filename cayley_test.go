@@ -0,0 +1,47 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCayleyGraph(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	t.Run("BuildCayleyGraph test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		x1, _ := GeneratorX(1)
+		g, err := BuildCayleyGraph([]TreePair{x0, x1}, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 17, len(g.Vertices))
+		assert.Equal(t, []int{0, 1, 1, 1, 1}, g.Lengths[:5])
+		// F has no relations of length at most 4, so the ball of radius 2 is a tree.
+		assert.Equal(t, 16, len(g.Edges))
+		for _, e := range g.Edges {
+			want := Multiply(clone(g.Vertices[e.From]), clone([]TreePair{x0, x1}[e.Generator]))
+			assert.True(t, EqualsAsElements(g.Vertices[e.To], want))
+		}
+
+		_, err = BuildCayleyGraph(nil, 1)
+		assert.NotNil(t, err)
+		_, err = BuildCayleyGraph([]TreePair{x0}, -1)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("CayleyGraph DOT test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		g, err := BuildCayleyGraph([]TreePair{c}, 1)
+		assert.Nil(t, err)
+		want := "digraph cayley {\n" +
+			"\tv0 [label=\"0 (0)\"];\n\tv1 [label=\"1 (1)\"];\n\tv2 [label=\"2 (1)\"];\n" +
+			"\tv0 -> v1 [label=\"g0\"];\n\tv1 -> v2 [label=\"g0\"];\n\tv2 -> v0 [label=\"g0\"];\n}\n"
+		assertCorrectMessage(t, g.DOT(), want)
+	})
+}
@@ -0,0 +1,281 @@
+package treepair
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Centralizer describes the centralizer of an element of F, which is isomorphic to F^m x Z^n.
+type Centralizer struct {
+	// FixedIntervals lists the intervals fixed pointwise by the element, and CopiesOfF the two
+	// generators of the copy of F supported on each of them.
+	FixedIntervals []FixedInterval
+	CopiesOfF      [][]TreePair
+	// Cyclic lists a generator of each infinite cyclic factor, one for each one-bump factor of
+	// the element (see BumpFactorsF).  It is the least root of the bump in F.
+	Cyclic []TreePair
+}
+
+// Generators returns all the generators of the centralizer.
+func (c Centralizer) Generators() []TreePair {
+	var gens []TreePair
+	for _, v := range c.CopiesOfF {
+		gens = append(gens, v...)
+	}
+	return append(gens, c.Cyclic...)
+}
+
+// IsomorphismType writes the centralizer as a product of copies of F and Z, e.g. "F^2 x Z".
+func (c Centralizer) IsomorphismType() string {
+	var factors []string
+	power := func(name string, n int) {
+		switch {
+		case 1 == n:
+			factors = append(factors, name)
+		case n > 1:
+			factors = append(factors, name+"^"+strconv.Itoa(n))
+		}
+	}
+	power("F", len(c.CopiesOfF))
+	power("Z", len(c.Cyclic))
+	if 0 == len(factors) {
+		return "1"
+	}
+	return strings.Join(factors, " x ")
+}
+
+// CentralizerF computes the centralizer of the element tp of F.  An element commuting with tp
+// fixes each component of its support and of its fixed set.  On each interval fixed pointwise
+// it can be any element of F supported there.  On a component of the support it agrees with a
+// power of the least root of tp there, which is determined by its slope at the left end.  The
+// roots on components meeting at a non-dyadic fixed point must be combined with powers that
+// leave no break at that point.  tp itself is not modified.
+func CentralizerF(tp TreePair) (Centralizer, error) {
+	breaks, err := ToPL(tp)
+	if nil != err {
+		return Centralizer{}, errors.New("CentralizerF(): element is not in F")
+	}
+	fixed, err := FixedPoints(tp)
+	if nil != err {
+		return Centralizer{}, err
+	}
+	g := newPLFunc(breaks)
+
+	var c Centralizer
+	for _, v := range fixed {
+		if v.IsPoint() {
+			continue
+		}
+		gens, err := copyOfF(v.Left, v.Right)
+		if nil != err {
+			return Centralizer{}, err
+		}
+		c.FixedIntervals = append(c.FixedIntervals, v)
+		c.CopiesOfF = append(c.CopiesOfF, gens)
+	}
+
+	// bumps meeting at non-dyadic fixed points are chained together.
+	var chain []plFunc
+	for k := 0; k+1 < len(fixed); k++ {
+		root, err := bumpRoot(g, fixed[k].Right, fixed[k+1].Left)
+		if nil != err {
+			return Centralizer{}, err
+		}
+		chain = append(chain, root)
+		if next := fixed[k+1]; next.IsPoint() && !isDyadic(next.Left) {
+			continue
+		}
+		gen, err := chainGenerator(chain)
+		if nil != err {
+			return Centralizer{}, err
+		}
+		c.Cyclic = append(c.Cyclic, gen)
+		chain = nil
+	}
+	return c, nil
+}
+
+// bumpRoot returns the least root of the map g on the component [a, b] of its support, as a
+// map of [a, b].  Writing f for whichever of g and its inverse moves points towards a, a map
+// commuting with f is determined by its slope 2^s at a, through h = f^-k h f^k, and a root
+// of f of order n has s = e/n where f has slope 2^e at a.  Candidate orders are tried from the
+// largest down, extending h across fundamental domains of f until it reaches the linear
+// piece of f at b, where it must be linear too.
+func bumpRoot(g plFunc, a, b *big.Rat) (plFunc, error) {
+	mid := new(big.Rat).Add(a, b)
+	mid.Quo(mid, big.NewRat(2, 1))
+	f, fInv := g, g.inverse()
+	if f.eval(mid).Cmp(mid) > 0 {
+		f, fInv = fInv, f
+	}
+	first := f[sort.Search(len(f), func(i int) bool { return f[i].x1.Cmp(a) > 0 })]
+	last := f[f.pieceAt(b)]
+	ea, eb := log2Rat(first.slope), log2Rat(last.slope)
+	p := first.x1
+
+	for n := gcd(-ea, eb); n >= 1; n-- {
+		if 0 != ea%n || 0 != eb%n {
+			continue
+		}
+		slopeA := pow2Rat(ea / n)
+		slopeB := pow2Rat(eb / n)
+		h := plFunc{{x0: a, x1: p, y0: a, slope: slopeA}}
+		hi := p
+		for found := false; !found; {
+			next := fInv.eval(hi)
+			segment := f.then(h, hi, next).then(fInv, hi, next)
+			if hi.Cmp(last.x0) >= 0 && segment[0].y0.Cmp(last.x0) >= 0 {
+				// within the linear piece of f at b: h is linear there or has infinitely
+				// many breaks accumulating at b.
+				for _, piece := range segment {
+					want := new(big.Rat).Sub(piece.x0, b)
+					want.Mul(want, slopeB)
+					want.Add(want, b)
+					if piece.slope.Cmp(slopeB) != 0 || piece.y0.Cmp(want) != 0 {
+						h = nil
+						break
+					}
+				}
+				if nil != h {
+					h = append(h, plPiece{x0: hi, x1: b, y0: segment[0].y0, slope: slopeB})
+				}
+				found = true
+				continue
+			}
+			h = append(h, segment...)
+			hi = next
+		}
+		if nil != h {
+			return h.simplify(), nil
+		}
+	}
+	return nil, errors.New("bumpRoot(): no root found, not even the bump itself")
+}
+
+// chainGenerator returns the element of F agreeing on each bump of chain with the power of its
+// root that makes the slopes match across the non-dyadic points between them, taking the
+// least positive power on the first bump.
+func chainGenerator(chain []plFunc) (TreePair, error) {
+	// the power on bump k is K q_k, with q_0 = 1, for the least K making them all integers.
+	q := []*big.Rat{big.NewRat(1, 1)}
+	for k := 0; k+1 < len(chain); k++ {
+		right := big.NewRat(int64(log2Rat(chain[k][len(chain[k])-1].slope)), 1)
+		left := big.NewRat(int64(log2Rat(chain[k+1][0].slope)), 1)
+		next := new(big.Rat).Mul(q[k], right)
+		q = append(q, next.Quo(next, left))
+	}
+	K := big.NewInt(1)
+	for _, v := range q {
+		K.Mul(K, new(big.Int).Quo(v.Denom(), new(big.Int).GCD(nil, nil, K, v.Denom())))
+	}
+
+	one := big.NewRat(1, 1)
+	var pieces plFunc
+	if a := chain[0][0].x0; 0 != a.Sign() {
+		pieces = append(pieces, plPiece{x0: new(big.Rat), x1: a, y0: new(big.Rat), slope: one})
+	}
+	for k, root := range chain {
+		power := new(big.Rat).Mul(q[k], new(big.Rat).SetInt(K))
+		pieces = append(pieces, plPower(root, int(power.Num().Int64()))...)
+	}
+	if b := chain[len(chain)-1][len(chain[len(chain)-1])-1].x1; b.Cmp(one) < 0 {
+		pieces = append(pieces, plPiece{x0: b, x1: one, y0: b, slope: one})
+	}
+	return FromPL(pieces.simplify().breakpoints())
+}
+
+// plPower returns the pow-th power of the map h of an interval onto itself.
+func plPower(h plFunc, pow int) plFunc {
+	lo, hi := h[0].x0, h[len(h)-1].x1
+	if pow < 0 {
+		h = h.inverse()
+		pow = -pow
+	}
+	power := plFunc{{x0: lo, x1: hi, y0: lo, slope: big.NewRat(1, 1)}}
+	for k := 0; k < pow; k++ {
+		power = power.then(h, lo, hi)
+	}
+	return power
+}
+
+// copyOfF returns two generators of the copy of F supported on the dyadic interval [c, d]:
+// x0 and x1 carried over by a map of [0,1] onto [c, d] sending the leaves of the right vine
+// with k leaves onto the k largest standard dyadic intervals making up [c, d).
+func copyOfF(c, d *big.Rat) ([]TreePair, error) {
+	// split [c,d) into the largest standard dyadic intervals, from the left.
+	var lefts, widths []*big.Rat
+	for x := new(big.Rat).Set(c); x.Cmp(d) < 0; {
+		width := big.NewRat(1, 1)
+		for new(big.Rat).Add(x, width).Cmp(d) > 0 || !new(big.Rat).Quo(x, width).IsInt() {
+			width.Quo(width, big.NewRat(2, 1))
+		}
+		lefts = append(lefts, new(big.Rat).Set(x))
+		widths = append(widths, width)
+		x.Add(x, width)
+	}
+
+	// phi carries the k-th leaf of the right vine onto the k-th of these.
+	phi := make(plFunc, len(lefts))
+	vineLeft, vineWidth := new(big.Rat), big.NewRat(1, 2)
+	for k := range phi {
+		if k+1 == len(phi) {
+			vineWidth = new(big.Rat).Sub(big.NewRat(1, 1), vineLeft)
+		}
+		vineRight := new(big.Rat).Add(vineLeft, vineWidth)
+		phi[k] = plPiece{x0: vineLeft, x1: vineRight, y0: lefts[k], slope: new(big.Rat).Quo(widths[k], vineWidth)}
+		vineLeft, vineWidth = vineRight, new(big.Rat).Quo(vineWidth, big.NewRat(2, 1))
+	}
+	phiInv := phi.inverse()
+
+	one := big.NewRat(1, 1)
+	var gens []TreePair
+	for n := 0; n < 2; n++ {
+		x, err := GeneratorX(n)
+		if nil != err {
+			return nil, err
+		}
+		xBreaks, err := ToPL(x)
+		if nil != err {
+			return nil, err
+		}
+		moved := phiInv.then(newPLFunc(xBreaks), c, d).then(phi, c, d)
+		var pieces plFunc
+		if 0 != c.Sign() {
+			pieces = append(pieces, plPiece{x0: new(big.Rat), x1: c, y0: new(big.Rat), slope: one})
+		}
+		pieces = append(pieces, moved...)
+		if d.Cmp(one) < 0 {
+			pieces = append(pieces, plPiece{x0: d, x1: one, y0: d, slope: one})
+		}
+		gen, err := FromPL(pieces.simplify().breakpoints())
+		if nil != err {
+			return nil, err
+		}
+		gens = append(gens, gen)
+	}
+	return gens, nil
+}
+
+// log2Rat returns the exponent of a power of 2.
+func log2Rat(x *big.Rat) int {
+	return x.Num().BitLen() - x.Denom().BitLen()
+}
+
+// pow2Rat returns 2^e.
+func pow2Rat(e int) *big.Rat {
+	if e >= 0 {
+		return new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), uint(e)))
+	}
+	return new(big.Rat).SetFrac(big.NewInt(1), new(big.Int).Lsh(big.NewInt(1), uint(-e)))
+}
+
+// gcd returns the greatest common divisor of two positive integers.
+func gcd(a, b int) int {
+	for 0 != b {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,63 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCentralizer(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	commutes := func(a, b TreePair) bool {
+		return EqualsAsElements(Multiply(clone(a), clone(b)), Multiply(clone(b), clone(a)))
+	}
+
+	t.Run("CentralizerF test", func(t *testing.T) {
+		for word, want := range map[string]string{
+			"":           "F",
+			"x0":         "Z",
+			"x1":         "F x Z",
+			"x1 x3^-1":   "F^2 x Z",
+			"x0^-1 x1^2": "Z",
+			"x2^3 x0^6":  "Z",
+			"x1^2 x0 x3": "",
+		} {
+			tp, err := EvaluateWord("01", word)
+			assert.Nil(t, err)
+			c, err := CentralizerF(tp)
+			assert.Nil(t, err)
+			if "" != want {
+				assertCorrectMessage(t, c.IsomorphismType(), want)
+			}
+			for _, g := range c.Generators() {
+				assert.True(t, commutes(g, tp), word+" and "+g.FullString()+" should commute.")
+			}
+		}
+	})
+
+	// the cyclic factor is generated by the least root of the element.
+	t.Run("CentralizerF root test", func(t *testing.T) {
+		for _, word := range []string{"x0^2", "x1^4", "x0^-3"} {
+			tp, _ := EvaluateWord("01", word)
+			c, err := CentralizerF(tp)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(c.Cyclic))
+			root := c.Cyclic[0]
+			order := map[string]int{"x0^2": 2, "x1^4": 4, "x0^-3": 3}[word]
+			assert.True(t, EqualsAsElements(Power(root, order), tp) || EqualsAsElements(Power(root, -order), tp), word)
+		}
+	})
+
+	t.Run("CentralizerF error test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		_, err := CentralizerF(c)
+		assert.NotNil(t, err)
+	})
+}
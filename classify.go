@@ -0,0 +1,82 @@
+package treepair
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Classification collects the basic invariants of an element.
+type Classification struct {
+	InF, InT, InV bool
+	// Size is the number of leaves of the minimised tree pair.
+	Size int
+	// WordLength is the word length in x0 and x1, for elements of F over "01", and -1 otherwise.
+	WordLength int
+	// Order is the order of a torsion element, and 0 for elements of infinite order.
+	Order int
+	// RotationNumber is the rotation number of an element of T, and nil otherwise.
+	RotationNumber *big.Rat
+	// SlopeAtZero and SlopeAtOne are the exponents of the slopes at 0 and 1, as powers of the
+	// alphabet size, for elements of F.
+	SlopeAtZero, SlopeAtOne int
+}
+
+// Classify computes the classification of tp.  tp itself is not modified.
+func Classify(tp TreePair) Classification {
+	c, _ := ClassifyContext(context.Background(), tp)
+	return c
+}
+
+// ClassifyContext is Classify, giving up with ctx.Err() once ctx is done.  Finding the order
+// runs through the powers of tp, which may take long for large elements, so servers
+// classifying elements sent to them should pass a context with a deadline.
+func ClassifyContext(ctx context.Context, tp TreePair) (Classification, error) {
+	min := clone(tp)
+	min.Minimise()
+	c := Classification{InF: min.InF(), InT: min.InT(), InV: min.InV(), Size: min.Size(), WordLength: -1}
+	order, _, err := orderContext(ctx, min)
+	if nil != err {
+		return Classification{}, err
+	}
+	c.Order = order
+	if c.InF {
+		if length, err := WordLengthF(min); nil == err {
+			c.WordLength = length
+		}
+		c.SlopeAtZero, c.SlopeAtOne = SlopeAtZero(min), SlopeAtOne(min)
+	}
+	if c.InT {
+		c.RotationNumber, _ = RotationNumber(min)
+	}
+	return c, nil
+}
+
+// String writes the classification one invariant per line, leaving out those which do not
+// apply.
+func (c Classification) String() string {
+	group := "V"
+	switch {
+	case c.InF:
+		group = "F"
+	case c.InT:
+		group = "T"
+	}
+	lines := []string{"group: " + group, "size: " + strconv.Itoa(c.Size)}
+	if c.WordLength >= 0 {
+		lines = append(lines, "word length: "+strconv.Itoa(c.WordLength))
+	}
+	if 0 == c.Order {
+		lines = append(lines, "order: infinite")
+	} else {
+		lines = append(lines, "order: "+strconv.Itoa(c.Order))
+	}
+	if nil != c.RotationNumber {
+		lines = append(lines, "rotation number: "+c.RotationNumber.RatString())
+	}
+	if c.InF {
+		lines = append(lines, "slope at 0: "+strconv.Itoa(c.SlopeAtZero), "slope at 1: "+strconv.Itoa(c.SlopeAtOne))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,60 @@
+package treepair
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	t.Run("Classify test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		c := Classify(x0)
+		assertCorrectMessage(t, c.RotationNumber.RatString(), "0")
+		c.RotationNumber = nil
+		assert.Equal(t, Classification{InF: true, InT: true, InV: true, Size: 3, WordLength: 1,
+			SlopeAtZero: -1, SlopeAtOne: 1}, c)
+
+		gen, _ := GeneratorC()
+		c = Classify(gen)
+		assert.False(t, c.InF)
+		assert.True(t, c.InT)
+		assert.Equal(t, 3, c.Order)
+		assert.Equal(t, -1, c.WordLength)
+		assertCorrectMessage(t, c.RotationNumber.RatString(), "2/3")
+	})
+
+	t.Run("ClassifyContext test", func(t *testing.T) {
+		gen, _ := GeneratorC()
+		c, err := ClassifyContext(context.Background(), gen)
+		assert.Nil(t, err)
+		assert.Equal(t, Classify(gen), c)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = ClassifyContext(ctx, gen)
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("Classification String test", func(t *testing.T) {
+		for word, want := range map[string]string{
+			"":         "group: F\nsize: 1\nword length: 0\norder: 1\nrotation number: 0\nslope at 0: 0\nslope at 1: 0",
+			"x0^-1 x1": "group: F\nsize: 4\nword length: 2\norder: infinite\nrotation number: 0\nslope at 0: 1\nslope at 1: 0",
+			"C":        "group: T\nsize: 3\norder: 3\nrotation number: 2/3",
+			"pi0 x0":   "group: V\nsize: 3\norder: infinite",
+		} {
+			tp, err := EvaluateWord("01", word)
+			assert.Nil(t, err)
+			assertCorrectMessage(t, Classify(tp).String(), want)
+		}
+	})
+}
@@ -0,0 +1,699 @@
+package treepair
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// strandVertexKind says whether a vertex of a strand diagram splits one strand into
+// len(alphabet) strands or merges them into one, or is the source or sink of an open diagram.
+type strandVertexKind int
+
+const (
+	splitVertex strandVertexKind = iota
+	mergeVertex
+	sourceVertex
+	sinkVertex
+)
+
+// strandVertex is a vertex of a strand diagram, with the edges at its input and output ports
+// in order: a split has one input and len(alphabet) outputs, and a merge the other way round.
+// A source has just one output and a sink just one input.
+type strandVertex struct {
+	kind    strandVertexKind
+	in, out []int
+}
+
+// strandEdge is an edge of a strand diagram from the output port tailPort of the vertex tail
+// to the input port headPort of the vertex head.  crossings labels the points, in order along
+// the strand, at which it crosses the cut closing up the diagram, and its weight is their
+// number.
+type strandEdge struct {
+	tail, tailPort int
+	head, headPort int
+	crossings      []int
+}
+
+// weight returns the number of times e crosses the cut.
+func (e *strandEdge) weight() int {
+	return len(e.crossings)
+}
+
+// strandGraph is an abstract strand diagram: vertices and edges by number, together with the
+// crossings of the cut by the free loops, strands meeting no vertex.
+type strandGraph struct {
+	arity    int
+	vertices map[int]*strandVertex
+	edges    map[int]*strandEdge
+	loops    [][]int
+	next     int
+}
+
+func newStrandGraph(arity int) *strandGraph {
+	return &strandGraph{arity: arity, vertices: make(map[int]*strandVertex), edges: make(map[int]*strandEdge)}
+}
+
+// addVertex adds a vertex of the given kind with its ports not yet joined.
+func (g *strandGraph) addVertex(kind strandVertexKind) int {
+	v := &strandVertex{kind: kind, in: make([]int, 1), out: make([]int, 1)}
+	switch kind {
+	case splitVertex:
+		v.out = make([]int, g.arity)
+	case mergeVertex:
+		v.in = make([]int, g.arity)
+	case sourceVertex:
+		v.in = nil
+	case sinkVertex:
+		v.out = nil
+	}
+	g.next++
+	g.vertices[g.next] = v
+	return g.next
+}
+
+// addEdge joins the output port tailPort of tail to the input port headPort of head, by a
+// strand with the given crossings of the cut.
+func (g *strandGraph) addEdge(tail, tailPort, head, headPort int, crossings []int) {
+	g.next++
+	g.edges[g.next] = &strandEdge{tail: tail, tailPort: tailPort, head: head, headPort: headPort, crossings: crossings}
+	g.vertices[tail].out[tailPort] = g.next
+	g.vertices[head].in[headPort] = g.next
+}
+
+// copyFrom adds a copy of the vertices, edges and free loops of other to g, and returns the
+// numbers in g of the vertices of other.
+func (g *strandGraph) copyFrom(other *strandGraph) map[int]int {
+	number := make(map[int]int, len(other.vertices))
+	for _, id := range other.sortedVertices() {
+		number[id] = g.addVertex(other.vertices[id].kind)
+	}
+	for _, id := range other.sortedVertices() {
+		for _, e := range other.vertices[id].out {
+			edge := other.edges[e]
+			g.addEdge(number[edge.tail], edge.tailPort, number[edge.head], edge.headPort, append([]int{}, edge.crossings...))
+		}
+	}
+	for _, loop := range other.loops {
+		g.loops = append(g.loops, append([]int{}, loop...))
+	}
+	return number
+}
+
+// sortedVertices returns the numbers of the vertices of g in increasing order.
+func (g *strandGraph) sortedVertices() []int {
+	ids := make([]int, 0, len(g.vertices))
+	for k := range g.vertices {
+		ids = append(ids, k)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// sortedEdges returns the numbers of the edges of g in increasing order.
+func (g *strandGraph) sortedEdges() []int {
+	ids := make([]int, 0, len(g.edges))
+	for k := range g.edges {
+		ids = append(ids, k)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// removePair deletes the adjacent vertices first and second and splices the strands through
+// them: joins[k] is an edge arriving at the pair and the edge leaving the pair that continues
+// its strand, and extra[k] the crossings of the edges between them.  An edge spliced to itself
+// closes up into a free loop.
+func (g *strandGraph) removePair(first, second int, joins [][2]int, extra [][]int) {
+	alias := make(map[int]int)
+	find := func(e int) int {
+		for {
+			a, ok := alias[e]
+			if !ok {
+				return e
+			}
+			e = a
+		}
+	}
+	for k, j := range joins {
+		arriving, leaving := find(j[0]), find(j[1])
+		if arriving == leaving {
+			g.loops = append(g.loops, concatCrossings(g.edges[arriving].crossings, extra[k]))
+			delete(g.edges, arriving)
+			continue
+		}
+		a, l := g.edges[arriving], g.edges[leaving]
+		a.head, a.headPort = l.head, l.headPort
+		a.crossings = concatCrossings(a.crossings, extra[k], l.crossings)
+		if v, ok := g.vertices[a.head]; ok && a.head != first && a.head != second {
+			v.in[a.headPort] = arriving
+		}
+		delete(g.edges, leaving)
+		alias[leaving] = arriving
+	}
+	delete(g.vertices, first)
+	delete(g.vertices, second)
+}
+
+// concatCrossings returns the crossings of the strands in parts, joined end to end.
+func concatCrossings(parts ...[]int) []int {
+	var crossings []int
+	for _, p := range parts {
+		crossings = append(crossings, p...)
+	}
+	return crossings
+}
+
+// reduceOnce applies one reduction move to g, if there is one, and reports whether it did.  A
+// split whose outputs run in order into the inputs of a merge, with equal weights, is replaced
+// by a single strand.  A merge whose output runs into a split is replaced by parallel strands,
+// joining its k-th input to the k-th output of the split.  If move is not nil it is first
+// called to move the cut, as moveCut does, so that the strands between the pair do not cross
+// it: then no crossing is lost or repeated.
+func (g *strandGraph) reduceOnce(move func(q map[int]int)) bool {
+	for _, id := range g.sortedVertices() {
+		v := g.vertices[id]
+		if splitVertex == v.kind {
+			first := g.edges[v.out[0]]
+			merge, ok := g.vertices[first.head]
+			if !ok || mergeVertex != merge.kind {
+				continue
+			}
+			reducible := true
+			for k, e := range v.out {
+				edge := g.edges[e]
+				reducible = reducible && edge.head == first.head && edge.headPort == k && edge.weight() == first.weight()
+			}
+			if !reducible {
+				continue
+			}
+			if nil != move && first.weight() > 0 {
+				move(map[int]int{first.head: first.weight()})
+			}
+			for _, e := range v.out {
+				delete(g.edges, e)
+			}
+			g.removePair(id, first.head, [][2]int{{v.in[0], merge.out[0]}}, [][]int{first.crossings})
+			return true
+		}
+		if mergeVertex != v.kind {
+			continue
+		}
+
+		out := g.edges[v.out[0]]
+		split, ok := g.vertices[out.head]
+		if !ok || splitVertex != split.kind {
+			continue
+		}
+		if nil != move && out.weight() > 0 {
+			move(map[int]int{out.head: out.weight()})
+		}
+		joins := make([][2]int, g.arity)
+		extra := make([][]int, g.arity)
+		for k := range joins {
+			joins[k] = [2]int{v.in[k], split.out[k]}
+			extra[k] = out.crossings
+		}
+		delete(g.edges, v.out[0])
+		g.removePair(id, out.head, joins, extra)
+		return true
+	}
+	return false
+}
+
+// canonicalComponent writes the connected component of g containing start, numbering its
+// vertices in the order a breadth first search from start meets them through their ports.
+// Weights are shifted along the search tree, by adding p(head) - p(tail) to each edge for
+// potentials p of the vertices, so that the edges of the tree have weight 0: the shift does
+// not change the total weight of any cycle.  It also returns the vertices in the order met
+// and their potentials.
+func (g *strandGraph) canonicalComponent(start int) (string, []int, map[int]int) {
+	index := map[int]int{start: 0}
+	potential := map[int]int{start: 0}
+	order := []int{start}
+	visit := func(other, p int) {
+		if _, seen := index[other]; !seen {
+			index[other] = len(order)
+			potential[other] = p
+			order = append(order, other)
+		}
+	}
+	for k := 0; k < len(order); k++ {
+		id := order[k]
+		for _, e := range g.vertices[id].in {
+			edge := g.edges[e]
+			visit(edge.tail, potential[id]+edge.weight())
+		}
+		for _, e := range g.vertices[id].out {
+			edge := g.edges[e]
+			visit(edge.head, potential[id]-edge.weight())
+		}
+	}
+
+	var b strings.Builder
+	for _, id := range order {
+		v := g.vertices[id]
+		if splitVertex == v.kind {
+			b.WriteString("s")
+		} else {
+			b.WriteString("m")
+		}
+		for _, e := range v.out {
+			edge := g.edges[e]
+			b.WriteString(" " + strconv.Itoa(index[edge.head]) + "." + strconv.Itoa(edge.headPort) + ":" +
+				strconv.Itoa(edge.weight()+potential[edge.head]-potential[edge.tail]))
+		}
+		b.WriteString(";")
+	}
+	return b.String(), order, potential
+}
+
+// closeUp removes the source and sink of an open diagram, joining the strand into the sink
+// round to the strand out of the source across the cut, at the crossing labelled 0.
+func (g *strandGraph) closeUp(source, sink int) {
+	top, bottom := g.vertices[source].out[0], g.vertices[sink].in[0]
+	delete(g.vertices, source)
+	delete(g.vertices, sink)
+	if top == bottom {
+		g.loops = append(g.loops, concatCrossings(g.edges[top].crossings, []int{0}))
+		delete(g.edges, top)
+		return
+	}
+	t, b := g.edges[top], g.edges[bottom]
+	b.head, b.headPort = t.head, t.headPort
+	b.crossings = concatCrossings(b.crossings, []int{0}, t.crossings)
+	g.vertices[b.head].in[b.headPort] = bottom
+	delete(g.edges, top)
+}
+
+// ClosedStrandDiagram is the closed strand diagram of an element of V, following Belk and
+// Matucci: the domain tree drawn downwards as splits and the range tree upwards as merges,
+// with the strand at each domain leaf running into the range leaf it is carried to, and the
+// root of the range tree joined back round to the root of the domain tree across a cut.  The
+// diagram is kept as an abstract graph, with the order of the strands at each vertex, and
+// each strand records the number of times it crosses the cut.
+type ClosedStrandDiagram struct {
+	g *strandGraph
+}
+
+// NewClosedStrandDiagram returns the closed strand diagram of the tree pair tp, unreduced.
+// tp itself is not modified.
+func NewClosedStrandDiagram(tp TreePair) *ClosedStrandDiagram {
+	open := NewStrandDiagram(tp)
+	open.g.closeUp(open.source, open.sink)
+	return &ClosedStrandDiagram{g: open.g}
+}
+
+// Reduce applies reduction moves to d until none applies, and returns the number of moves
+// made.  Each move removes a split and a merge: a split whose strands all run straight into a
+// merge, crossing the cut equally often, collapses to one strand, and a merge running into a
+// split is replaced by parallel strands.  Strands closing up on themselves become free loops.
+func (d *ClosedStrandDiagram) Reduce() int {
+	moves := 0
+	for d.g.reduceOnce(nil) {
+		moves++
+	}
+	return moves
+}
+
+// Vertices returns the number of splits and merges left in d.
+func (d *ClosedStrandDiagram) Vertices() int {
+	return len(d.g.vertices)
+}
+
+// FreeLoops returns, in increasing order, the number of times each free loop of d crosses the
+// cut.  A cycle of k cones carried round by the element closes up into a loop crossing k
+// times.
+func (d *ClosedStrandDiagram) FreeLoops() []int {
+	loops := make([]int, len(d.g.loops))
+	for k, loop := range d.g.loops {
+		loops[k] = len(loop)
+	}
+	sort.Ints(loops)
+	return loops
+}
+
+// Invariant reduces d and returns a canonical description of the reduced diagram, which
+// agrees for two diagrams exactly when their reduced diagrams are isomorphic by a map
+// respecting the order of the strands at each vertex and the crossings of the cut up to
+// coboundary, i.e. the number of crossings round every cycle.  A free loop may be traded for
+// len(alphabet) copies of itself, by a split and a merge on it, so only the loops present,
+// and their numbers modulo len(alphabet)-1, are recorded.  The invariant is shared by
+// conjugate elements: conjugating the element changes its closed strand diagram by
+// reduction moves and their inverses.
+func (d *ClosedStrandDiagram) Invariant() string {
+	d.Reduce()
+	return d.g.invariant()
+}
+
+// invariant returns the description of g of ClosedStrandDiagram.Invariant, without reducing
+// g first.
+func (g *strandGraph) invariant() string {
+	var components []string
+	for _, c := range g.components() {
+		components = append(components, "("+c.least+")")
+	}
+	sort.Strings(components)
+
+	counts := make(map[int]int)
+	lengths := make([]int, len(g.loops))
+	for k, loop := range g.loops {
+		counts[len(loop)]++
+		lengths[k] = len(loop)
+	}
+	sort.Ints(lengths)
+	modulus := g.arity - 1
+	for _, w := range lengths {
+		if count := counts[w]; count > 0 {
+			components = append(components, "loop "+strconv.Itoa(w)+"x"+strconv.Itoa((count-1)%modulus+1))
+			counts[w] = 0
+		}
+	}
+	return strings.Join(components, " ")
+}
+
+// strandComponent is a connected component of a strand graph, with the least description
+// canonicalComponent gives it and a vertex to start from to get that description.
+type strandComponent struct {
+	least string
+	start int
+}
+
+// components returns the connected components of g, in the order of their least vertices.
+func (g *strandGraph) components() []strandComponent {
+	var components []strandComponent
+	seen := make(map[int]bool, len(g.vertices))
+	for _, id := range g.sortedVertices() {
+		if seen[id] {
+			continue
+		}
+		c := strandComponent{start: id}
+		var order []int
+		c.least, order, _ = g.canonicalComponent(id)
+		for _, other := range order {
+			seen[other] = true
+			if s, _, _ := g.canonicalComponent(other); s < c.least {
+				c.least, c.start = s, other
+			}
+		}
+		components = append(components, c)
+	}
+	return components
+}
+
+// moveCut moves the cut of g down through the diagram, past each vertex v q[v] >= 0 times,
+// and returns the map carried by the strands between the old cut and the new.  The
+// diagram is unrolled along the cut into an infinite diagram with a copy (v, t) of each
+// vertex v at each level t, and an edge of weight w from u to v lifts to edges from (u, t)
+// to (v, t+w), whose k-th crossing, counting down from the vertex u, is the crossing of
+// the cut between levels t+k and t+k+1.  The old cut runs above level 0 and the new one
+// above the levels q[v] for each v: an edge then crosses it w + q[u] - q[v] times, which
+// must not be negative, and crossings[e] gives the labels of these crossings of each edge
+// e in order, and loops the labels of the crossings of the free loops, which do not move.
+// The strands between the cuts are read, as StrandDiagram.TreePair reads a diagram, as a
+// map from the crossings of the old cut to those of the new, each a root of a forest
+// labelled by the crossing.  g takes on the new labels.
+func (g *strandGraph) moveCut(alpha []rune, q map[int]int, crossings map[int][]int, loops [][]int) forestMap {
+	// lift is the lift of an edge starting at level, and copyOf a copy of a vertex.
+	type lift struct{ edge, level int }
+	type copyOf struct{ vertex, level int }
+	type regionFlow struct {
+		domain address
+		suffix string
+	}
+	letter := make(map[rune]int, len(alpha))
+	for k, c := range alpha {
+		letter[c] = k
+	}
+
+	h := make(forestMap)
+	flows := make(map[lift][]regionFlow)
+	pending := make(map[copyOf]int)
+	var ready []copyOf
+	deliver := func(l lift) {
+		e := g.edges[l.edge]
+		if end := l.level + e.weight(); end < q[e.head] {
+			c := copyOf{e.head, end}
+			if _, ok := pending[c]; !ok {
+				pending[c] = len(g.vertices[e.head].in)
+			}
+			if pending[c]--; 0 == pending[c] {
+				ready = append(ready, c)
+			}
+			return
+		}
+		label := crossings[l.edge][q[e.tail]-l.level-1]
+		for _, f := range flows[l] {
+			h[f.domain] = address{label, f.suffix}
+		}
+		delete(flows, l)
+	}
+
+	// the lifts crossing the old cut start the flow.
+	for _, id := range g.sortedEdges() {
+		e := g.edges[id]
+		for t := -e.weight(); t < 0; t++ {
+			l := lift{id, t}
+			flows[l] = []regionFlow{{domain: address{root: e.crossings[-t-1]}}}
+			deliver(l)
+		}
+	}
+	for 0 != len(ready) {
+		c := ready[0]
+		ready = ready[1:]
+		v := g.vertices[c.vertex]
+		in := func(port int) lift {
+			return lift{v.in[port], c.level - g.edges[v.in[port]].weight()}
+		}
+		switch v.kind {
+		case splitVertex:
+			for _, f := range flows[in(0)] {
+				if "" == f.suffix {
+					for k, r := range alpha {
+						out := lift{v.out[k], c.level}
+						flows[out] = append(flows[out], regionFlow{address{f.domain.root, f.domain.word + string(r)}, ""})
+					}
+					continue
+				}
+				r, size := utf8.DecodeRuneInString(f.suffix)
+				out := lift{v.out[letter[r]], c.level}
+				flows[out] = append(flows[out], regionFlow{f.domain, f.suffix[size:]})
+			}
+			delete(flows, in(0))
+		case mergeVertex:
+			out := lift{v.out[0], c.level}
+			for k := range v.in {
+				for _, f := range flows[in(k)] {
+					flows[out] = append(flows[out], regionFlow{f.domain, string(alpha[k]) + f.suffix})
+				}
+				delete(flows, in(k))
+			}
+		}
+		for _, e := range v.out {
+			deliver(lift{e, c.level})
+		}
+	}
+
+	for k, loop := range g.loops {
+		for i, label := range loop {
+			h[address{root: label}] = address{root: loops[k][i]}
+		}
+	}
+	for id, e := range g.edges {
+		e.crossings = crossings[id]
+	}
+	g.loops = loops
+	return h
+}
+
+// freshCrossings numbers the crossings of the cut moved by q, as moveCut takes them, from 0
+// in order of the edges and then of the free loops.
+func (g *strandGraph) freshCrossings(q map[int]int) (map[int][]int, [][]int) {
+	label := 0
+	fresh := func(n int) []int {
+		labels := make([]int, n)
+		for k := range labels {
+			labels[k] = label
+			label++
+		}
+		return labels
+	}
+	crossings := make(map[int][]int, len(g.edges))
+	for _, id := range g.sortedEdges() {
+		e := g.edges[id]
+		crossings[id] = fresh(e.weight() + q[e.tail] - q[e.head])
+	}
+	loops := make([][]int, len(g.loops))
+	for k, loop := range g.loops {
+		loops[k] = fresh(len(loop))
+	}
+	return crossings, loops
+}
+
+// cutDiagram is the closed strand diagram of an element a, kept track of as its cut is moved.
+// conj carries the single strand crossing the cut of NewClosedStrandDiagram(a), labelled 0,
+// onto the strands crossing the cut now, so that the element the diagram carries from the
+// cut round to itself, with the strands labelled by their crossings, is conj^-1 a conj.
+type cutDiagram struct {
+	alphabet []rune
+	g        *strandGraph
+	conj     forestMap
+}
+
+// newCutDiagram returns the closed strand diagram of tp with its cut where closing up puts it.
+func newCutDiagram(tp TreePair) *cutDiagram {
+	open := NewStrandDiagram(tp)
+	open.g.closeUp(open.source, open.sink)
+	return &cutDiagram{alphabet: open.Alphabet(), g: open.g, conj: forestMap{{}: {}}}
+}
+
+// moveCut moves the cut of d down by q, as strandGraph.moveCut does with fresh labels.
+func (d *cutDiagram) moveCut(q map[int]int) {
+	crossings, loops := d.g.freshCrossings(q)
+	d.conj = d.conj.compose(d.g.moveCut(d.alphabet, q, crossings, loops))
+	d.conj.minimise(d.alphabet)
+}
+
+// reduce applies reduction moves to d until none applies, moving the cut out of the way of
+// each, so that conj stays correct.
+func (d *cutDiagram) reduce() {
+	for d.g.reduceOnce(d.moveCut) {
+	}
+}
+
+// splitLoop replaces the first free loop of d crossing the cut w times by len(alphabet)
+// loops crossing it w times, one for each letter: a split and a merge put on the loop and
+// then reduced away.  The cone below each crossing of the loop is carried onto the
+// crossings of the new loops, by the first letter of its words.
+func (d *cutDiagram) splitLoop(w int) {
+	next := 0
+	h := make(forestMap)
+	keep := func(labels []int) {
+		for _, label := range labels {
+			h[address{root: label}] = address{root: label}
+			if label >= next {
+				next = label + 1
+			}
+		}
+	}
+	for _, e := range d.g.edges {
+		keep(e.crossings)
+	}
+	split := -1
+	for k, loop := range d.g.loops {
+		if -1 == split && w == len(loop) {
+			split = k
+			continue
+		}
+		keep(loop)
+	}
+	loop := d.g.loops[split]
+	for _, label := range loop {
+		if label >= next {
+			next = label + 1
+		}
+	}
+
+	loops := append(d.g.loops[:split:split], d.g.loops[split+1:]...)
+	for j, c := range d.alphabet {
+		labels := loop
+		if j > 0 {
+			labels = make([]int, w)
+			for i := range labels {
+				labels[i] = next
+				next++
+			}
+		}
+		for i, label := range loop {
+			h[address{label, string(c)}] = address{root: labels[i]}
+		}
+		loops = append(loops, labels)
+	}
+	d.g.loops = loops
+	d.conj = d.conj.compose(h)
+	d.conj.minimise(d.alphabet)
+}
+
+// loopCounts returns the number of free loops of d crossing the cut w times, for each w.
+func (d *cutDiagram) loopCounts() map[int]int {
+	counts := make(map[int]int)
+	for _, loop := range d.g.loops {
+		counts[len(loop)]++
+	}
+	return counts
+}
+
+// closedConjugator returns an element h of V with h^-1 a h = b if the elements a and b over
+// the same alphabet are conjugate, and nil otherwise.  By Belk and Matucci a and b are
+// conjugate exactly when their reduced closed strand diagrams agree, as Invariant tells.  The
+// diagrams are reduced keeping track of their cuts, and free loops are split until the two
+// have as many loops of each weight.  An isomorphism from the diagram of a to that of b then
+// carries the cut of b to a cut of the diagram of a, and the strands between the two cuts
+// carry the element a reads off at its cut to the element b reads off at its own.  h is the
+// conjugator of a found while reducing, followed by this map and by the inverse of the
+// conjugator of b.  Neither a nor b is modified.
+func closedConjugator(a, b TreePair) *treePair {
+	da, db := newCutDiagram(a), newCutDiagram(b)
+	da.reduce()
+	db.reduce()
+	if da.g.invariant() != db.g.invariant() {
+		return nil
+	}
+	for split := true; split; {
+		split = false
+		countsA, countsB := da.loopCounts(), db.loopCounts()
+		for w, count := range countsA {
+			if count < countsB[w] {
+				da.splitLoop(w)
+				split = true
+			} else if count > countsB[w] {
+				db.splitLoop(w)
+				split = true
+			}
+		}
+	}
+
+	// move the cut of a by q to the image of the cut of b, taking the labels of b.
+	q := make(map[int]int, len(da.g.vertices))
+	crossings := make(map[int][]int, len(da.g.edges))
+	startsB := make(map[string][]int)
+	for _, c := range db.g.components() {
+		startsB[c.least] = append(startsB[c.least], c.start)
+	}
+	for _, c := range da.g.components() {
+		start := startsB[c.least][0]
+		startsB[c.least] = startsB[c.least][1:]
+		_, orderA, potentialA := da.g.canonicalComponent(c.start)
+		_, orderB, potentialB := db.g.canonicalComponent(start)
+		// an edge of weight w in a has weight w + p(head) - p(tail) in b for p below, so
+		// moving the cut of a by max(p) - p gives it the weights of b.
+		p := make(map[int]int, len(orderA))
+		most := 0
+		for i, v := range orderA {
+			p[v] = potentialA[v] - potentialB[orderB[i]]
+			if 0 == i || p[v] > most {
+				most = p[v]
+			}
+		}
+		for i, v := range orderA {
+			q[v] = most - p[v]
+			for k, e := range da.g.vertices[v].out {
+				crossings[e] = db.g.edges[db.g.vertices[orderB[i]].out[k]].crossings
+			}
+		}
+	}
+	loopsB := make(map[int][][]int)
+	for _, loop := range db.g.loops {
+		loopsB[len(loop)] = append(loopsB[len(loop)], loop)
+	}
+	loops := make([][]int, len(da.g.loops))
+	for k, loop := range da.g.loops {
+		loops[k] = loopsB[len(loop)][0]
+		loopsB[len(loop)] = loopsB[len(loop)][1:]
+	}
+
+	h := da.conj.compose(da.g.moveCut(da.alphabet, q, crossings, loops)).compose(db.conj.inverse())
+	h.minimise(da.alphabet)
+	return h.treePair(da.alphabet)
+}
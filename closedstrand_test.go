@@ -0,0 +1,99 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClosedStrandDiagram(t *testing.T) {
+
+	invariant := func(t *testing.T, word string) string {
+		t.Helper()
+		tp, err := EvaluateWord("01", word)
+		assert.Nil(t, err)
+		return NewClosedStrandDiagram(tp).Invariant()
+	}
+
+	t.Run("ClosedStrandDiagram reduction test", func(t *testing.T) {
+		a, _ := GeneratorA()
+		d := NewClosedStrandDiagram(a)
+		assert.Equal(t, 4, d.Vertices())
+		assert.Equal(t, 1, d.Reduce())
+		assert.Equal(t, 2, d.Vertices())
+		assert.Empty(t, d.FreeLoops())
+
+		// C carries the cones 0, 11, 10 round, closing up into one loop crossing the cut 3 times.
+		c, _ := GeneratorC()
+		d = NewClosedStrandDiagram(c)
+		d.Reduce()
+		assert.Equal(t, 0, d.Vertices())
+		assert.Equal(t, []int{3}, d.FreeLoops())
+
+		p, _ := GeneratorPi0()
+		d = NewClosedStrandDiagram(p)
+		d.Reduce()
+		assert.Equal(t, []int{1, 2}, d.FreeLoops())
+
+		d = NewClosedStrandDiagram(identityOver([]rune("01")))
+		assert.Equal(t, "loop 1x1", d.Invariant())
+	})
+
+	t.Run("ClosedStrandDiagram Invariant test", func(t *testing.T) {
+		for _, pair := range [][2]string{
+			{"A", "C^-1 A C"},
+			{"B", "A^-1 B A"},
+			{"A B", "B A"},
+			{"pi0", "A^-1 pi0 A"},
+			{"C", "C^2"},
+		} {
+			assert.Equal(t, invariant(t, pair[0]), invariant(t, pair[1]), pair[0]+" is conjugate to "+pair[1])
+		}
+		for _, pair := range [][2]string{
+			{"A", "A^-1"},
+			{"A", "A^2"},
+			{"A", "B"},
+			{"C", "pi0"},
+		} {
+			assert.NotEqual(t, invariant(t, pair[0]), invariant(t, pair[1]), pair[0]+" is not conjugate to "+pair[1])
+		}
+
+		// conjugates over a ternary alphabet.
+		a, _ := ParseElement("012", "{1100000,1010000,0 1 2 3 4}")
+		h, _ := ParseElement("012", "{1000,1000,2 0 1}")
+		hInv := clone(h)
+		hInv.Invert()
+		b := Multiply(Multiply(hInv, clone(a)), clone(h))
+		assert.Equal(t, NewClosedStrandDiagram(a).Invariant(), NewClosedStrandDiagram(b).Invariant())
+	})
+
+	// after reducing, the element carried round from the cut to itself is conj^-1 a conj: the
+	// cut moves once round past every vertex, and each free loop carries a crossing to the
+	// next.
+	t.Run("cutDiagram reduce test", func(t *testing.T) {
+		for _, word := range []string{"", "A", "B A^-1", "C", "pi0 A", "A^-2 C B pi0 B^-1 C^-1 A^2"} {
+			tp, err := EvaluateWord("01", word)
+			assert.Nil(t, err)
+			d := newCutDiagram(tp)
+			d.reduce()
+			assert.Equal(t, NewClosedStrandDiagram(tp).Invariant(), d.g.invariant())
+
+			q := make(map[int]int, len(d.g.vertices))
+			for id := range d.g.vertices {
+				q[id] = 1
+			}
+			crossings := make(map[int][]int, len(d.g.edges))
+			for id, e := range d.g.edges {
+				crossings[id] = e.crossings
+			}
+			loops := make([][]int, len(d.g.loops))
+			for k, loop := range d.g.loops {
+				loops[k] = append(append([]int{}, loop[1:]...), loop[0])
+			}
+			round := d.g.moveCut(d.alphabet, q, crossings, loops)
+			got := d.conj.compose(round).compose(d.conj.inverse())
+			got.minimise(d.alphabet)
+			assert.True(t, EqualsAsElements(got.treePair(d.alphabet), tp), word)
+		}
+	})
+}
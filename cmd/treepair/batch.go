@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/loeksnokes/treepair"
+)
+
+// batchSteps lists the steps of a batch pipeline which transform each element.
+var batchSteps = map[string]func(tp treepair.TreePair) treepair.TreePair{
+	"minimise": func(tp treepair.TreePair) treepair.TreePair {
+		tp.Minimise()
+		return tp
+	},
+	"invert": func(tp treepair.TreePair) treepair.TreePair {
+		tp.Invert()
+		return tp
+	},
+}
+
+// batch reads one element per line, from the file given by -in or else from opts.in, and
+// passes each through the comma-separated steps of the pipeline in args[0], writing the
+// results line by line.  The steps minimise and invert transform each element.  The pipeline
+// may end in classify, writing each classification on a line, or in multiply, writing only
+// the product of all the elements.
+func batch(opts options, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("batch", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	file := flags.String("in", "", "file to read, instead of standard input")
+	if err := flags.Parse(args); nil != err {
+		return err
+	}
+	if 1 != flags.NArg() {
+		return errors.New("batch needs a pipeline, like minimise,classify")
+	}
+	steps := strings.Split(flags.Arg(0), ",")
+	last := steps[len(steps)-1]
+	if "classify" == last || "multiply" == last {
+		steps = steps[:len(steps)-1]
+	}
+	for _, v := range steps {
+		if _, ok := batchSteps[v]; !ok {
+			return errors.New("unknown batch step " + v)
+		}
+	}
+
+	in := opts.in
+	if "" != *file {
+		f, err := os.Open(*file)
+		if nil != err {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var product treepair.TreePair
+	var err error
+	scanErr := treepair.ScanElements(opts.alphabet, in, func(tp treepair.TreePair) bool {
+		for _, v := range steps {
+			tp = batchSteps[v](tp)
+		}
+		switch {
+		case "multiply" == last && nil == product:
+			product = tp
+			product.Minimise()
+		case "multiply" == last:
+			product = treepair.Multiply(product, tp)
+		case "classify" == last:
+			_, err = fmt.Fprintln(out, strings.ReplaceAll(treepair.Classify(tp).String(), "\n", "; "))
+		default:
+			err = write(opts, tp, out)
+		}
+		return nil == err
+	})
+	if nil != scanErr {
+		return scanErr
+	}
+	if nil != err {
+		return err
+	}
+	if "multiply" == last {
+		if nil == product {
+			return errors.New("no elements to multiply")
+		}
+		return write(opts, product, out)
+	}
+	return nil
+}
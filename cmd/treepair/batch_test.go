@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatch(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	// C = {10100,10100,1 2 0} has order 3, the second line is x0 with an extra caret, and
+	// C x0 C is the inverse of x0.
+	input := "{10100,10100,1 2 0}\n{1100100,1110000,0 1 2 3}\n\n{10100,10100,1 2 0}\n"
+
+	output := func(input string, args ...string) (string, error) {
+		var out bytes.Buffer
+		err := run(args, strings.NewReader(input), &out)
+		return strings.TrimSpace(out.String()), err
+	}
+
+	t.Run("pipelines test", func(t *testing.T) {
+		for _, v := range []struct {
+			pipeline, want string
+		}{
+			{"minimise", "{10100,10100,1 2 0}\n{10100,11000,0 1 2}\n{10100,10100,1 2 0}"},
+			{"invert,minimise", "{10100,10100,2 0 1}\n{11000,10100,0 1 2}\n{10100,10100,2 0 1}"},
+			{"multiply", "{11000,10100,0 1 2}"},
+			{"invert,multiply", "{10100,11000,0 1 2}"},
+			{"classify", "group: T; size: 3; order: 3; rotation number: 2/3\n" +
+				"group: F; size: 3; word length: 1; order: infinite; rotation number: 0; slope at 0: -1; slope at 1: 1\n" +
+				"group: T; size: 3; order: 3; rotation number: 2/3"},
+		} {
+			got, err := output(input, "batch", v.pipeline)
+			assert.Nil(t, err, v.pipeline)
+			assertCorrectMessage(t, got, v.want)
+		}
+	})
+
+	t.Run("file test", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "elements.txt")
+		assert.Nil(t, os.WriteFile(file, []byte(input), 0644))
+		got, err := output("", "-format", "full", "batch", "-in", file, "multiply")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got, "{D: [00 0], [01 1], [1 2] || R: [0 0], [10 1], [11 2]}")
+	})
+
+	t.Run("errors test", func(t *testing.T) {
+		for _, v := range []struct {
+			input string
+			args  []string
+		}{
+			{input, []string{"batch"}},
+			{input, []string{"batch", "minimise,frobnicate"}},
+			{input, []string{"batch", "classify,minimise"}},
+			{"", []string{"batch", "multiply"}},
+			{"{10100,10100,1 2}", []string{"batch", "minimise"}},
+			{"", []string{"batch", "-in", filepath.Join(t.TempDir(), "missing"), "minimise"}},
+		} {
+			_, err := output(v.input, v.args...)
+			assert.NotNil(t, err, v.args)
+		}
+	})
+}
@@ -0,0 +1,203 @@
+// Command treepair does arithmetic with elements of R. Thompson's groups F, T and V written in
+// DFS notation, like "{11000,10100,1 2 0}", or in the Full notation printed by FullString.
+//
+// Usage:
+//
+//	treepair [-alphabet 01] [-format dfs|full] <command> <arguments>
+//
+// The commands are:
+//
+//	multiply a b ...   the product of the elements, applying a first
+//	invert a           the inverse of a
+//	minimise a         the minimal tree pair of a
+//	power a n          the n-th power of a, for any integer n
+//	render [-format f] a
+//	                   a picture of the minimised a, in one of the formats ascii (the
+//	                   default), dot, svg or tikz
+//	classify a         the group of a, its size, word length (in F), order, rotation
+//	                   number (in T) and slopes at the ends (in F)
+//	repl               an interactive session reading lines from standard input; type
+//	                   :help for its syntax
+//	batch [-in file] pipeline
+//	                   read one element per line, from standard input or the file, and
+//	                   apply the comma-separated steps of the pipeline to each: minimise
+//	                   and invert, optionally followed by classify (each element) or
+//	                   multiply (all the elements together)
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/loeksnokes/treepair"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); nil != err {
+		fmt.Fprintln(os.Stderr, "treepair: "+err.Error())
+		os.Exit(1)
+	}
+}
+
+// options are the settings shared by all commands.
+type options struct {
+	alphabet string
+	format   string
+	in       io.Reader
+}
+
+// command runs one subcommand on its arguments, writing to out.
+type command func(opts options, args []string, out io.Writer) error
+
+// commands lists the subcommands by name.
+var commands = map[string]command{
+	"multiply": multiply,
+	"invert":   invert,
+	"minimise": minimise,
+	"power":    power,
+	"render":   render,
+	"classify": classify,
+	"repl":     repl,
+	"batch":    batch,
+}
+
+// run parses the flags and runs the command named by the first remaining argument, which may
+// read further input from in.
+func run(args []string, in io.Reader, out io.Writer) error {
+	opts := options{in: in}
+	flags := flag.NewFlagSet("treepair", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&opts.alphabet, "alphabet", "01", "alphabet of the trees")
+	flags.StringVar(&opts.format, "format", "dfs", "output format: dfs or full")
+	if err := flags.Parse(args); nil != err {
+		return err
+	}
+	if "dfs" != opts.format && "full" != opts.format {
+		return errors.New("unknown format " + opts.format)
+	}
+	if 0 == flags.NArg() {
+		return errors.New("no command given")
+	}
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		return errors.New("unknown command " + flags.Arg(0))
+	}
+	return cmd(opts, flags.Args()[1:], out)
+}
+
+// parse reads the elements written in args.
+func parse(opts options, args []string) ([]treepair.TreePair, error) {
+	elements := make([]treepair.TreePair, len(args))
+	for k, v := range args {
+		tp, err := treepair.ParseElement(opts.alphabet, v)
+		if nil != err {
+			return nil, err
+		}
+		elements[k] = tp
+	}
+	return elements, nil
+}
+
+// write prints tp in the chosen format.
+func write(opts options, tp treepair.TreePair, out io.Writer) error {
+	s := tp.DFSString()
+	if "full" == opts.format {
+		s = tp.FullString()
+	}
+	_, err := fmt.Fprintln(out, s)
+	return err
+}
+
+// single reads the one element of args.
+func single(opts options, args []string) (treepair.TreePair, error) {
+	if 1 != len(args) {
+		return nil, errors.New("expected one element, got " + strconv.Itoa(len(args)))
+	}
+	elements, err := parse(opts, args)
+	if nil != err {
+		return nil, err
+	}
+	return elements[0], nil
+}
+
+func multiply(opts options, args []string, out io.Writer) error {
+	if 0 == len(args) {
+		return errors.New("multiply needs at least one element")
+	}
+	elements, err := parse(opts, args)
+	if nil != err {
+		return err
+	}
+	product := elements[0]
+	product.Minimise()
+	for _, v := range elements[1:] {
+		product = treepair.Multiply(product, v)
+	}
+	return write(opts, product, out)
+}
+
+func invert(opts options, args []string, out io.Writer) error {
+	tp, err := single(opts, args)
+	if nil != err {
+		return err
+	}
+	tp.Invert()
+	return write(opts, tp, out)
+}
+
+func minimise(opts options, args []string, out io.Writer) error {
+	tp, err := single(opts, args)
+	if nil != err {
+		return err
+	}
+	tp.Minimise()
+	return write(opts, tp, out)
+}
+
+func power(opts options, args []string, out io.Writer) error {
+	if 2 != len(args) {
+		return errors.New("power needs an element and an exponent")
+	}
+	n, err := strconv.Atoi(args[1])
+	if nil != err {
+		return errors.New("bad exponent " + args[1])
+	}
+	tp, err := single(opts, args[:1])
+	if nil != err {
+		return err
+	}
+	return write(opts, treepair.Power(tp, n), out)
+}
+
+func render(opts options, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	format := flags.String("format", "ascii", "picture format: "+strings.Join(treepair.RenderFormats, ", "))
+	if err := flags.Parse(args); nil != err {
+		return err
+	}
+	tp, err := single(opts, flags.Args())
+	if nil != err {
+		return err
+	}
+	picture, err := treepair.Render(tp, *format)
+	if nil != err {
+		return err
+	}
+	_, err = io.WriteString(out, picture)
+	return err
+}
+
+func classify(opts options, args []string, out io.Writer) error {
+	tp, err := single(opts, args)
+	if nil != err {
+		return err
+	}
+	_, err = fmt.Fprintln(out, treepair.Classify(tp).String())
+	return err
+}
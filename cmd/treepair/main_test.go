@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	output := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		err := run(args, strings.NewReader(""), &out)
+		return strings.TrimSpace(out.String()), err
+	}
+
+	// C = {10100,10100,1 2 0} has order 3, and x0 = {11000,10100,0 1 2}.
+	t.Run("commands test", func(t *testing.T) {
+		for _, v := range []struct {
+			args []string
+			want string
+		}{
+			{[]string{"multiply", "{10100,10100,1 2 0}", "{10100,10100,1 2 0}", "{10100,10100,1 2 0}"}, "{0,0,0}"},
+			{[]string{"power", "{10100,10100,1 2 0}", "-3"}, "{0,0,0}"},
+			{[]string{"power", "{10100,10100,1 2 0}", "2"}, "{10100,10100,2 0 1}"},
+			{[]string{"invert", "{10100,10100,1 2 0}"}, "{10100,10100,2 0 1}"},
+			{[]string{"multiply", "{11000,10100,0 1 2}", "{10100,11000,0 1 2}"}, "{0,0,0}"},
+			{[]string{"minimise", "{1100100,1110000,0 1 2 3}"}, "{10100,11000,0 1 2}"},
+			{[]string{"-format", "full", "minimise", "{1100100,1110000,0 1 2 3}"}, "{D: [0 0], [10 1], [11 2] || R: [00 0], [01 1], [1 2]}"},
+			{[]string{"invert", "{D: [00 0], [01 1], [1 2] || R: [0 0], [10 1], [11 2]}"}, "{10100,11000,0 1 2}"},
+		} {
+			got, err := output(v.args...)
+			assert.Nil(t, err, v.args)
+			assertCorrectMessage(t, got, v.want)
+		}
+	})
+
+	t.Run("render test", func(t *testing.T) {
+		got, err := output("render", "{1100100,1110000,0 1 2 3}")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got, "D:\n+-- 𝛆\n|  +-- 0 [0]\n|  +-- 1\n|  |  +-- 10 [1]\n|  |  +-- 11 [2]\n"+
+			"R:\n+-- 𝛆\n|  +-- 0\n|  |  +-- 00 [0]\n|  |  +-- 01 [1]\n|  +-- 1 [2]")
+		got, err = output("render", "--format", "svg", "{11000,10100,0 1 2}")
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(got, "<svg "))
+		got, err = output("-format", "full", "render", "-format", "tikz", "{11000,10100,0 1 2}")
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(got, "\\begin{tikzpicture}"))
+
+		_, err = output("render", "--format", "png", "{11000,10100,0 1 2}")
+		assert.NotNil(t, err)
+		_, err = output("render", "--size", "3", "{11000,10100,0 1 2}")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("classify test", func(t *testing.T) {
+		got, err := output("classify", "{10100,10100,1 2 0}")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got, "group: T\nsize: 3\norder: 3\nrotation number: 2/3")
+		got, err = output("classify", "{1100100,1110000,0 1 2 3}")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got, "group: F\nsize: 3\nword length: 1\norder: infinite\nrotation number: 0\nslope at 0: -1\nslope at 1: 1")
+		_, err = output("classify")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("errors test", func(t *testing.T) {
+		for _, args := range [][]string{
+			{},
+			{"frobnicate"},
+			{"-format", "svg", "invert", "{11000,10100,1 2 0}"},
+			{"invert"},
+			{"invert", "{11000,10100,1 2 0}", "{11000,10100,1 2 0}"},
+			{"power", "{11000,10100,1 2 0}", "x"},
+			{"multiply", "{110,10100,1 2 0}"},
+			{"-bogus", "invert", "{11000,10100,1 2 0}"},
+		} {
+			_, err := output(args...)
+			assert.NotNil(t, err, args)
+		}
+	})
+}
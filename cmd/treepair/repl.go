@@ -0,0 +1,287 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/loeksnokes/treepair"
+)
+
+// replHelp describes the syntax of the repl.
+const replHelp = `Lines are expressions, or assignments name = expression.
+Expressions multiply with *, raise to integer powers with ^, and group with ( ).
+Elements are written in DFS or Full notation in braces, or by the name of a variable.
+Over the alphabet 01 the variables x0, x1, A, B, C and pi0 start out as the generators.
+inv(e) is the inverse of e and min(e) its minimal tree pair; products are minimised.
+Commands:
+  :format dfs|full|ascii|dot|svg|tikz   how values are printed
+  :vars                                 list the variables
+  :classify e                           classify the value of e
+  :help                                 this text
+  :quit                                 leave`
+
+// session holds the variables and output format of a repl.
+type session struct {
+	opts   options
+	format string
+	vars   map[string]treepair.TreePair
+}
+
+// repl runs an interactive session, reading lines from opts.in until it ends or :quit.
+func repl(opts options, args []string, out io.Writer) error {
+	if 0 != len(args) {
+		return errors.New("repl takes no arguments")
+	}
+	s := &session{opts: opts, format: opts.format, vars: map[string]treepair.TreePair{}}
+	if "01" == opts.alphabet {
+		for _, name := range []string{"x0", "x1", "A", "B", "C", "pi0"} {
+			tp, err := treepair.EvaluateWord("01", name)
+			if nil != err {
+				return err
+			}
+			s.vars[name] = tp
+		}
+	}
+
+	lines := bufio.NewScanner(opts.in)
+	for fmt.Fprint(out, "> "); lines.Scan(); fmt.Fprint(out, "> ") {
+		reply, quit, err := s.handle(strings.TrimSpace(lines.Text()))
+		if nil != err {
+			reply = "error: " + err.Error()
+		}
+		if "" != reply {
+			fmt.Fprintln(out, strings.TrimSuffix(reply, "\n"))
+		}
+		if quit {
+			break
+		}
+	}
+	fmt.Fprintln(out)
+	return lines.Err()
+}
+
+// handle runs one line, returning the text to print and whether the session is over.
+func (s *session) handle(line string) (string, bool, error) {
+	switch {
+	case "" == line:
+		return "", false, nil
+	case ":quit" == line:
+		return "", true, nil
+	case ":help" == line:
+		return replHelp, false, nil
+	case ":vars" == line:
+		names := make([]string, 0, len(s.vars))
+		for k := range s.vars {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		parts := make([]string, len(names))
+		for k, v := range names {
+			parts[k] = v + " = " + s.vars[v].DFSString()
+		}
+		return strings.Join(parts, "\n"), false, nil
+	case strings.HasPrefix(line, ":format"):
+		format := strings.TrimSpace(strings.TrimPrefix(line, ":format"))
+		if _, err := s.show(s.identity(), format); nil != err {
+			return "", false, err
+		}
+		s.format = format
+		return "", false, nil
+	case strings.HasPrefix(line, ":classify"):
+		tp, err := s.evaluate(strings.TrimPrefix(line, ":classify"))
+		if nil != err {
+			return "", false, err
+		}
+		return treepair.Classify(tp).String(), false, nil
+	case strings.HasPrefix(line, ":"):
+		return "", false, errors.New("unknown command " + line + ", try :help")
+	}
+
+	name, expr := "", line
+	if k := strings.Index(line, "="); k >= 0 {
+		name, expr = strings.TrimSpace(line[:k]), line[k+1:]
+		if !isName(name) {
+			return "", false, errors.New("cannot assign to " + name)
+		}
+	}
+	tp, err := s.evaluate(expr)
+	if nil != err {
+		return "", false, err
+	}
+	shown, err := s.show(tp, s.format)
+	if nil != err {
+		return "", false, err
+	}
+	if "" != name {
+		s.vars[name] = tp
+		return name + " = " + shown, false, nil
+	}
+	return shown, false, nil
+}
+
+// identity returns the trivial element over the alphabet of the session.
+func (s *session) identity() treepair.TreePair {
+	tp, _ := treepair.ParseElement(s.opts.alphabet, "{0,0,0}")
+	return tp
+}
+
+// show writes tp in the given format.
+func (s *session) show(tp treepair.TreePair, format string) (string, error) {
+	switch format {
+	case "dfs":
+		return tp.DFSString(), nil
+	case "full":
+		return tp.FullString(), nil
+	}
+	return treepair.Render(tp, format)
+}
+
+// isName reports whether w can name a variable: a letter or _ followed by letters, digits and _.
+func isName(w string) bool {
+	for k, r := range w {
+		if !unicode.IsLetter(r) && '_' != r && (0 == k || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return "" != w
+}
+
+// evaluate computes the value of the expression expr.
+func (s *session) evaluate(expr string) (treepair.TreePair, error) {
+	p := &parser{session: s, input: expr}
+	tp, err := p.product()
+	if nil != err {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(p.input) {
+		return nil, errors.New("unexpected " + p.input[p.pos:])
+	}
+	return tp, nil
+}
+
+// parser reads an expression by recursive descent:
+//
+//	product = power { "*" power }
+//	power   = atom { "^" integer }
+//	atom    = element | name | name "(" product ")" | "(" product ")"
+type parser struct {
+	session *session
+	input   string
+	pos     int
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.input) && ' ' == p.input[p.pos] {
+		p.pos++
+	}
+}
+
+// peek returns the next byte after spaces, or 0 at the end.
+func (p *parser) peek() byte {
+	if p.skipSpace(); p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+func (p *parser) product() (treepair.TreePair, error) {
+	tp, err := p.power()
+	for nil == err && '*' == p.peek() {
+		p.pos++
+		var next treepair.TreePair
+		if next, err = p.power(); nil == err {
+			tp = treepair.Multiply(tp, next)
+		}
+	}
+	return tp, err
+}
+
+func (p *parser) power() (treepair.TreePair, error) {
+	tp, err := p.atom()
+	for nil == err && '^' == p.peek() {
+		p.pos++
+		p.skipSpace()
+		start := p.pos
+		if p.pos < len(p.input) && '-' == p.input[p.pos] {
+			p.pos++
+		}
+		for p.pos < len(p.input) && unicode.IsDigit(rune(p.input[p.pos])) {
+			p.pos++
+		}
+		n, convErr := strconv.Atoi(p.input[start:p.pos])
+		if nil != convErr {
+			return nil, errors.New("bad exponent at " + p.input[start:])
+		}
+		tp = treepair.Power(tp, n)
+	}
+	return tp, err
+}
+
+func (p *parser) atom() (treepair.TreePair, error) {
+	switch c := p.peek(); {
+	case '{' == c:
+		end := strings.Index(p.input[p.pos:], "}")
+		if end < 0 {
+			return nil, errors.New("unclosed { in " + p.input[p.pos:])
+		}
+		literal := p.input[p.pos : p.pos+end+1]
+		p.pos += end + 1
+		return treepair.ParseElement(p.session.opts.alphabet, literal)
+	case '(' == c:
+		p.pos++
+		tp, err := p.product()
+		if nil != err {
+			return nil, err
+		}
+		if ')' != p.peek() {
+			return nil, errors.New("missing )")
+		}
+		p.pos++
+		return tp, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && isName(p.input[start:p.pos+1]) {
+		p.pos++
+	}
+	name := p.input[start:p.pos]
+	if "" == name {
+		if start == len(p.input) {
+			return nil, errors.New("expression ends too soon")
+		}
+		return nil, errors.New("unexpected " + p.input[start:])
+	}
+	if '(' != p.peek() {
+		tp, ok := p.session.vars[name]
+		if !ok {
+			return nil, errors.New("unknown variable " + name)
+		}
+		// copy, as Multiply rearranges its arguments.
+		return treepair.Power(tp, 1), nil
+	}
+
+	p.pos++
+	tp, err := p.product()
+	if nil != err {
+		return nil, err
+	}
+	if ')' != p.peek() {
+		return nil, errors.New("missing ) after " + name)
+	}
+	p.pos++
+	switch name {
+	case "inv":
+		tp.Invert()
+	case "min":
+		tp.Minimise()
+	default:
+		return nil, errors.New("unknown function " + name)
+	}
+	return tp, nil
+}
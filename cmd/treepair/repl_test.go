@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepl(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	// session returns the output of a repl reading lines, without the prompts.
+	session := func(args []string, lines ...string) string {
+		var out bytes.Buffer
+		err := run(append(args, "repl"), strings.NewReader(strings.Join(lines, "\n")), &out)
+		assert.Nil(t, err)
+		return strings.ReplaceAll(out.String(), "> ", "")
+	}
+
+	t.Run("repl test", func(t *testing.T) {
+		got := session(nil,
+			"g = C * C",
+			"g * C",
+			"h = inv(x0) * x1 ^ 2",
+			"(x0 * x1)^-1 * x0 * x1",
+			"min({1100100,1110000,0 1 2 3})",
+			":format full",
+			"x0",
+			":classify C^2",
+			"y",
+			"2 = x0",
+			"x0 ^ x",
+			":bogus",
+			"inv(x0",
+			":quit",
+			"x0",
+		)
+		want := strings.Join([]string{
+			"g = {10100,10100,2 0 1}",
+			"{0,0,0}",
+			"h = {110010100,101110000,0 1 2 3 4}",
+			"{0,0,0}",
+			"{10100,11000,0 1 2}",
+			"{D: [0 0], [10 1], [11 2] || R: [00 0], [01 1], [1 2]}",
+			"group: T\nsize: 3\norder: 3\nrotation number: 1/3",
+			"error: unknown variable y",
+			"error: cannot assign to 2",
+			"error: bad exponent at x",
+			"error: unknown command :bogus, try :help",
+			"error: missing ) after inv",
+			"",
+			"",
+		}, "\n")
+		assertCorrectMessage(t, got, want)
+	})
+
+	t.Run("repl alphabet test", func(t *testing.T) {
+		got := session([]string{"-alphabet", "012"}, "x0", "a = {1000,1000,2 0 1}", "a^3", ":vars", ":format png")
+		want := "error: unknown variable x0\na = {1000,1000,2 0 1}\n{0,0,0}\na = {1000,1000,2 0 1}\n" +
+			"error: Render(): unknown format png, not one of ascii dot svg tikz\n\n"
+		assertCorrectMessage(t, got, want)
+	})
+}
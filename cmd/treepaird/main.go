@@ -0,0 +1,204 @@
+// Command treepaird serves the arithmetic of R. Thompson's groups F, T and V over HTTP.
+// Elements are written in the JSON encoding of treepair.ElementJSON, as in
+// {"alphabet":"01","dfs":"{11000,10100,1 2 0}"}, and every endpoint takes a POST of a JSON
+// object:
+//
+//	/multiply  {"elements": [a, b, ...]}          -> {"result": the product, applying a first}
+//	/minimise  {"element": a}                     -> {"result": the minimal tree pair of a}
+//	/classify  {"element": a}                     -> {"classification": treepair.Classification}
+//	/render    {"element": a, "format": "svg"}    -> {"picture": the picture of a}
+//
+// Elements with more leaves than -max-leaves are refused, as are products growing past it and
+// bodies longer than bytesPerLeaf bytes for each leaf of the limit.  Requests still running
+// after -timeout are abandoned, and connections are given as long to send a request and to
+// take the answer.  Errors are answered with a status of 400, 405, 413 for bodies too long or,
+// for requests which ran out of time, 503, and {"error": message}.
+//
+// Usage:
+//
+//	treepaird [-addr :8080] [-max-leaves 1024] [-timeout 10s]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/loeksnokes/treepair"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	maxLeaves := flag.Int("max-leaves", 1024, "the most leaves an element or product may have")
+	timeout := flag.Duration("timeout", 10*time.Second, "the longest a request may run")
+	flag.Parse()
+	log.Fatal(newHTTPServer(*addr, *maxLeaves, *timeout).ListenAndServe())
+}
+
+// bytesPerLeaf is the length of body allowed for each leaf of the limit on elements, room for
+// a few elements of the largest size written in DFS notation.
+const bytesPerLeaf = 64
+
+// newHTTPServer returns the server listening on addr, with newServer's handler and with
+// timeouts on reading requests and writing answers so that slow connections are dropped.
+func newHTTPServer(addr string, maxLeaves int, timeout time.Duration) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           newServer(maxLeaves, timeout),
+		ReadHeaderTimeout: timeout,
+		ReadTimeout:       timeout,
+		WriteTimeout:      2 * timeout,
+	}
+}
+
+// server holds the limits put on the requests it answers.
+type server struct {
+	maxLeaves int
+	timeout   time.Duration
+}
+
+// request is the body of a request to any endpoint.
+type request struct {
+	Element  *treepair.ElementJSON  `json:"element"`
+	Elements []treepair.ElementJSON `json:"elements"`
+	Format   string                 `json:"format"`
+}
+
+// decode reads e, refusing elements with more than s.maxLeaves leaves.
+func (s *server) decode(e treepair.ElementJSON) (treepair.TreePair, error) {
+	tp, err := e.TreePair()
+	if nil != err {
+		return nil, err
+	}
+	if tp.Size() > s.maxLeaves {
+		return nil, errors.New("element has " + strconv.Itoa(tp.Size()) + " leaves, more than the limit of " +
+			strconv.Itoa(s.maxLeaves))
+	}
+	return tp, nil
+}
+
+// element decodes the single element of r.
+func (s *server) element(r request) (treepair.TreePair, error) {
+	if nil == r.Element {
+		return nil, errors.New("no element given")
+	}
+	return s.decode(*r.Element)
+}
+
+// endpoint computes the answer to a request, giving up once ctx is done.
+type endpoint func(ctx context.Context, r request) (interface{}, error)
+
+// newServer returns the handler serving all the endpoints, refusing elements with more than
+// maxLeaves leaves and abandoning requests after timeout.
+func newServer(maxLeaves int, timeout time.Duration) http.Handler {
+	s := &server{maxLeaves: maxLeaves, timeout: timeout}
+	mux := http.NewServeMux()
+	for path, e := range map[string]endpoint{
+		"/multiply": s.multiply,
+		"/minimise": s.minimise,
+		"/classify": s.classify,
+		"/render":   s.render,
+	} {
+		mux.Handle(path, s.handle(e))
+	}
+	return mux
+}
+
+// handle decodes POSTed requests for e and writes its answer, or the error, as JSON.
+func (s *server) handle(e endpoint) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if http.MethodPost != req.Method {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+			return
+		}
+		ctx, cancel := context.WithTimeout(req.Context(), s.timeout)
+		defer cancel()
+		var r request
+		body := http.MaxBytesReader(w, req.Body, int64(bytesPerLeaf)*int64(s.maxLeaves+1))
+		err := json.NewDecoder(body).Decode(&r)
+		var answer interface{}
+		if nil == err {
+			answer, err = e(ctx, r)
+		}
+		var tooLong *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLong):
+			writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": err.Error()})
+		case errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled):
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+		case nil != err:
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			writeJSON(w, http.StatusOK, answer)
+		}
+	})
+}
+
+// writeJSON writes the status and v as JSON, leaving pictures' < and > unescaped.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(v)
+}
+
+func (s *server) multiply(ctx context.Context, r request) (interface{}, error) {
+	if 0 == len(r.Elements) {
+		return nil, errors.New("no elements given")
+	}
+	factors := make([]treepair.TreePair, len(r.Elements))
+	for k, v := range r.Elements {
+		tp, err := s.decode(v)
+		if nil != err {
+			return nil, err
+		}
+		if k > 0 && string(factors[0].Alphabet()) != string(tp.Alphabet()) {
+			return nil, errors.New("elements are over different alphabets")
+		}
+		factors[k] = tp
+	}
+	product, err := treepair.MultiplyAllWithBudget(s.maxLeaves, factors...)
+	if nil != err {
+		return nil, err
+	}
+	return map[string]treepair.ElementJSON{"result": treepair.EncodeJSON(product)}, nil
+}
+
+func (s *server) minimise(ctx context.Context, r request) (interface{}, error) {
+	tp, err := s.element(r)
+	if nil != err {
+		return nil, err
+	}
+	tp.Minimise()
+	return map[string]treepair.ElementJSON{"result": treepair.EncodeJSON(tp)}, nil
+}
+
+func (s *server) classify(ctx context.Context, r request) (interface{}, error) {
+	tp, err := s.element(r)
+	if nil != err {
+		return nil, err
+	}
+	c, err := treepair.ClassifyContext(ctx, tp)
+	if nil != err {
+		return nil, err
+	}
+	return map[string]treepair.Classification{"classification": c}, nil
+}
+
+func (s *server) render(ctx context.Context, r request) (interface{}, error) {
+	tp, err := s.element(r)
+	if nil != err {
+		return nil, err
+	}
+	picture, err := treepair.Render(tp, r.Format)
+	if nil != err {
+		return nil, err
+	}
+	return map[string]string{"picture": picture}, nil
+}
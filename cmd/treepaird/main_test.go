@@ -0,0 +1,118 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	server := httptest.NewServer(newServer(16, 10*time.Second))
+	defer server.Close()
+
+	postTo := func(url, path, body string) (int, string) {
+		resp, err := http.Post(url+path, "application/json", strings.NewReader(body))
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+		answer, err := io.ReadAll(resp.Body)
+		assert.Nil(t, err)
+		return resp.StatusCode, strings.TrimSpace(string(answer))
+	}
+	post := func(path, body string) (int, string) {
+		return postTo(server.URL, path, body)
+	}
+
+	// C = {10100,10100,1 2 0} has order 3.
+	t.Run("endpoints test", func(t *testing.T) {
+		c := `{"alphabet":"01","dfs":"{10100,10100,1 2 0}"}`
+		for _, v := range []struct {
+			path, body, want string
+		}{
+			{"/multiply", `{"elements":[` + c + `,` + c + `]}`, `{"result":{"alphabet":"01","dfs":"{10100,10100,2 0 1}"}}`},
+			{"/multiply", `{"elements":[` + c + `,` + c + `,` + c + `]}`, `{"result":{"alphabet":"01","dfs":"{0,0,0}"}}`},
+			{"/minimise", `{"element":{"alphabet":"01","dfs":"{1100100,1110000,0 1 2 3}"}}`, `{"result":{"alphabet":"01","dfs":"{10100,11000,0 1 2}"}}`},
+			{"/classify", `{"element":` + c + `}`, `{"classification":{"InF":false,"InT":true,"InV":true,"Size":3,"WordLength":-1,"Order":3,"RotationNumber":"2/3","SlopeAtZero":0,"SlopeAtOne":0}}`},
+		} {
+			status, got := post(v.path, v.body)
+			assert.Equal(t, http.StatusOK, status, v.path)
+			assertCorrectMessage(t, got, v.want)
+		}
+
+		status, got := post("/render", `{"element":`+c+`,"format":"svg"}`)
+		assert.Equal(t, http.StatusOK, status)
+		assert.True(t, strings.HasPrefix(got, `{"picture":"<svg `), got)
+	})
+
+	t.Run("errors test", func(t *testing.T) {
+		for _, v := range []struct {
+			path, body, want string
+		}{
+			{"/multiply", `{"elements":[]}`, `{"error":"no elements given"}`},
+			{"/multiply", `{"elements":[{"alphabet":"01","dfs":"{0,0,0}"},{"alphabet":"012","dfs":"{0,0,0}"}]}`, `{"error":"elements are over different alphabets"}`},
+			{"/minimise", `{}`, `{"error":"no element given"}`},
+			{"/classify", `{"element":{"alphabet":"","dfs":"{0,0,0}"}}`, `{"error":"ElementJSON.TreePair(): no alphabet"}`},
+			{"/render", `{"element":{"alphabet":"01","dfs":"{0,0,0}"},"format":"png"}`, `{"error":"Render(): unknown format png, not one of ascii dot svg tikz"}`},
+			{"/classify", `{"element":`, `{"error":"unexpected EOF"}`},
+		} {
+			status, got := post(v.path, v.body)
+			assert.Equal(t, http.StatusBadRequest, status, v.path)
+			assertCorrectMessage(t, got, v.want)
+		}
+
+		resp, err := http.Get(server.URL + "/classify")
+		assert.Nil(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+
+	t.Run("limits test", func(t *testing.T) {
+		// the n-th power of x0 = {11000,10100,0 1 2} has n+2 leaves.
+		x0 := `{"alphabet":"01","dfs":"{11000,10100,0 1 2}"}`
+		small := httptest.NewServer(newServer(6, 10*time.Second))
+		defer small.Close()
+
+		status, got := postTo(small.URL, "/minimise", `{"element":{"alphabet":"01","dfs":"{1110000,1010100,0 1 2 3}"}}`)
+		assert.Equal(t, http.StatusOK, status, got)
+		status, got = postTo(small.URL, "/classify", `{"element":{"alphabet":"01","dfs":"{1111110000000,1010101010100,0 1 2 3 4 5 6}"}}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assertCorrectMessage(t, got, `{"error":"element has 7 leaves, more than the limit of 6"}`)
+
+		elements := strings.TrimSuffix(strings.Repeat(x0+",", 8), ",")
+		status, got = postTo(small.URL, "/multiply", `{"elements":[`+elements+`]}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assertCorrectMessage(t, got, `{"error":"product exceeds the leaf budget"}`)
+
+		hurried := httptest.NewServer(newServer(16, -time.Second))
+		defer hurried.Close()
+		status, got = postTo(hurried.URL, "/classify", `{"element":`+x0+`}`)
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+		assertCorrectMessage(t, got, `{"error":"context deadline exceeded"}`)
+
+		// the body is refused as it is read, before any element is parsed.
+		long := `{"element":{"alphabet":"01","dfs":"` + strings.Repeat("1", bytesPerLeaf*7) + `"}}`
+		status, got = postTo(small.URL, "/classify", long)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+		assertCorrectMessage(t, got, `{"error":"http: request body too large"}`)
+	})
+
+	t.Run("newHTTPServer test", func(t *testing.T) {
+		s := newHTTPServer(":8080", 16, 10*time.Second)
+		assert.Equal(t, ":8080", s.Addr)
+		assert.Equal(t, 10*time.Second, s.ReadHeaderTimeout)
+		assert.Equal(t, 10*time.Second, s.ReadTimeout)
+		assert.Equal(t, 20*time.Second, s.WriteTimeout)
+	})
+}
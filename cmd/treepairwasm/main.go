@@ -0,0 +1,70 @@
+//go:build js && wasm
+
+// Command treepairwasm exposes the calc package to JavaScript when compiled to WebAssembly:
+//
+//	GOOS=js GOARCH=wasm go build -o treepair.wasm ./cmd/treepairwasm
+//
+// Loaded with wasm_exec.js, it defines a global object treepair with the functions
+//
+//	treepair.multiply(alphabet, a, b, ...)
+//	treepair.invert(alphabet, a)
+//	treepair.minimise(alphabet, a)
+//	treepair.power(alphabet, a, n)
+//	treepair.classify(alphabet, a)
+//	treepair.render(alphabet, a, format)
+//
+// each returning an object {result: string} or {error: string}.
+package main
+
+import (
+	"errors"
+	"syscall/js"
+
+	"github.com/loeksnokes/treepair/calc"
+)
+
+func main() {
+	api := js.Global().Get("Object").New()
+	api.Set("multiply", wrap(2, func(args []js.Value) (string, error) {
+		elements := make([]string, len(args)-1)
+		for k, v := range args[1:] {
+			elements[k] = v.String()
+		}
+		return calc.Multiply(args[0].String(), elements...)
+	}))
+	api.Set("invert", wrap(2, func(args []js.Value) (string, error) {
+		return calc.Invert(args[0].String(), args[1].String())
+	}))
+	api.Set("minimise", wrap(2, func(args []js.Value) (string, error) {
+		return calc.Minimise(args[0].String(), args[1].String())
+	}))
+	api.Set("power", wrap(3, func(args []js.Value) (string, error) {
+		if js.TypeNumber != args[2].Type() {
+			return "", errors.New("power: the exponent is not a number")
+		}
+		return calc.Power(args[0].String(), args[1].String(), args[2].Int())
+	}))
+	api.Set("classify", wrap(2, func(args []js.Value) (string, error) {
+		return calc.Classify(args[0].String(), args[1].String())
+	}))
+	api.Set("render", wrap(3, func(args []js.Value) (string, error) {
+		return calc.Render(args[0].String(), args[1].String(), args[2].String())
+	}))
+	js.Global().Set("treepair", api)
+	select {}
+}
+
+// wrap turns f into a JavaScript function taking at least minArgs arguments and answering
+// {result: string} or {error: string}.
+func wrap(minArgs int, f func(args []js.Value) (string, error)) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) < minArgs {
+			return map[string]interface{}{"error": "too few arguments"}
+		}
+		result, err := f(args)
+		if nil != err {
+			return map[string]interface{}{"error": err.Error()}
+		}
+		return map[string]interface{}{"result": result}
+	})
+}
@@ -0,0 +1,95 @@
+package treepair
+
+import (
+	"errors"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/loeksnokes/prefcode"
+)
+
+// JoinDomains returns the common refinement of the domain codes of a and b: the least prefix
+// code whose tree contains both domain trees.  Elements over different alphabets give an
+// error.  Neither a nor b is modified.
+func JoinDomains(a, b TreePair) (prefcode.PrefCode, error) {
+	if string(a.Alphabet()) != string(b.Alphabet()) {
+		return nil, errors.New("JoinDomains(): elements are over different alphabets")
+	}
+	return joinCodes(a.CodeDomain(), b.CodeDomain()), nil
+}
+
+// JoinRanges returns the common refinement of the range codes of a and b, as JoinDomains does
+// for the domain codes.
+func JoinRanges(a, b TreePair) (prefcode.PrefCode, error) {
+	if string(a.Alphabet()) != string(b.Alphabet()) {
+		return nil, errors.New("JoinRanges(): elements are over different alphabets")
+	}
+	return joinCodes(a.CodeRange(), b.CodeRange()), nil
+}
+
+// MeetDomains returns the common coarsening of the domain codes of a and b: the greatest
+// prefix code whose tree lies in both domain trees, so that its carets are the carets common
+// to both.  Elements over different alphabets give an error.  Neither a nor b is modified.
+func MeetDomains(a, b TreePair) (prefcode.PrefCode, error) {
+	if string(a.Alphabet()) != string(b.Alphabet()) {
+		return nil, errors.New("MeetDomains(): elements are over different alphabets")
+	}
+	return meetCodes(a.CodeDomain(), b.CodeDomain()), nil
+}
+
+// MeetRanges returns the common coarsening of the range codes of a and b, as MeetDomains does
+// for the domain codes.
+func MeetRanges(a, b TreePair) (prefcode.PrefCode, error) {
+	if string(a.Alphabet()) != string(b.Alphabet()) {
+		return nil, errors.New("MeetRanges(): elements are over different alphabets")
+	}
+	return meetCodes(a.CodeRange(), b.CodeRange()), nil
+}
+
+// meetCodes returns the code over the alphabet of p whose carets are those common to p and q.
+func meetCodes(p, q prefcode.PrefCode) prefcode.PrefCode {
+	mpc, err := prefcode.NewPrefCodeAlphaRunes(p.Alphabet())
+	if nil != err {
+		panic("meetCodes(): could not build code over alphabet " + string(p.Alphabet()))
+	}
+	inP, inQ := caretsOf(p), caretsOf(q)
+	var common []string
+	for v := range inP {
+		if inQ[v] {
+			common = append(common, v)
+		}
+	}
+	for _, v := range shallowFirst(common) {
+		mpc.ExpandAt(v)
+	}
+	return mpc
+}
+
+// caretsOf returns the set of roots of the carets of the tree of pc, the proper prefixes of
+// its leaves, writing the root as "".
+func caretsOf(pc prefcode.PrefCode) map[string]bool {
+	carets := make(map[string]bool)
+	for leaf := range pc.Code() {
+		if prefcode.EmptyString == leaf {
+			continue
+		}
+		for w := leaf; "" != w; {
+			_, size := utf8.DecodeLastRuneInString(w)
+			w = w[:len(w)-size]
+			carets[w] = true
+		}
+	}
+	return carets
+}
+
+// shallowFirst sorts words by length and then in dictionary order, so that carets are
+// expanded after their parents, and returns them.
+func shallowFirst(words []string) []string {
+	sort.Slice(words, func(i, j int) bool {
+		if len(words[i]) != len(words[j]) {
+			return len(words[i]) < len(words[j])
+		}
+		return words[i] < words[j]
+	})
+	return words
+}
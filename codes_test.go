@@ -0,0 +1,55 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodes(t *testing.T) {
+
+	// x0 has domain 0 10 11 and range 00 01 1, and x1 domain 0 10 110 111 and range
+	// 0 100 101 11.
+	t.Run("Join test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		x1, _ := GeneratorX(1)
+		join, err := JoinDomains(x0, x1)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"0", "10", "110", "111"}, sortedLeaves(join))
+		join, err = JoinRanges(x0, x1)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"00", "01", "100", "101", "11"}, sortedLeaves(join))
+	})
+
+	t.Run("Meet test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		x1, _ := GeneratorX(1)
+		meet, err := MeetDomains(x0, x1)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"0", "10", "11"}, sortedLeaves(meet))
+		meet, err = MeetRanges(x0, x1)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"0", "1"}, sortedLeaves(meet))
+
+		// only the root caret is shared.
+		a, _ := binaryFromDFS("{11000,11000,0 1 2}")
+		b, _ := binaryFromDFS("{10100,10100,0 1 2}")
+		meet, _ = MeetDomains(a, b)
+		assert.Equal(t, []string{"0", "1"}, sortedLeaves(meet))
+		meet, _ = MeetDomains(a, identityOver([]rune("01")))
+		assert.Equal(t, 1, meet.Size())
+	})
+
+	t.Run("Join and Meet error test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		ternary, _ := NewTreePairAlpha("012")
+		_, err := JoinDomains(x0, ternary)
+		assert.NotNil(t, err)
+		_, err = JoinRanges(x0, ternary)
+		assert.NotNil(t, err)
+		_, err = MeetDomains(x0, ternary)
+		assert.NotNil(t, err)
+		_, err = MeetRanges(x0, ternary)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,95 @@
+package treepair
+
+import (
+	"sort"
+	"strings"
+)
+
+// InvariantComponent is one piece of the decomposition of an element by InvariantComponents.
+type InvariantComponent struct {
+	// Cones lists, in dictionary order, the cones making up the piece.
+	Cones []string
+	// Restriction acts as the element on the piece and as the identity elsewhere.
+	Restriction TreePair
+}
+
+// InvariantComponents returns a decomposition of the Cantor set into sets carried onto
+// themselves by tp, together with the restriction of tp to each set.  The restrictions
+// commute and multiply to tp.  The sets are unions of the cones at the leaves of the join of
+// the domain and range trees of the minimised pair, and are the finest such: a set containing
+// a cone c must contain every leaf of the join meeting the image of c, so the sets are the
+// classes of the leaves joined this way; each class is carried into itself, and so onto
+// itself, as tp is a bijection.  Finer invariant sets made of smaller cones may exist, and
+// there is no finest partition in general: a cycle of cones swapped by tp splits into ever
+// smaller cycles.  Pieces come in dictionary order of their first cones, and tp itself is not
+// modified.
+func InvariantComponents(tp TreePair) []InvariantComponent {
+	min := canonicalCopy(tp)
+	alpha := min.Alphabet()
+	m := newLeafMap(min)
+	join := sortedLeaves(joinCodes(min.CodeDomain(), min.CodeRange()))
+	for k, v := range join {
+		join[k] = rootAsEmpty(v)
+	}
+
+	// imageOf[c] is the image of the join leaf c, which lies in the cone of a domain leaf.
+	imageOf := make(map[string]string, len(join))
+	for _, c := range join {
+		for d, r := range m {
+			if strings.HasPrefix(c, d) {
+				imageOf[c] = r + c[len(d):]
+				break
+			}
+		}
+	}
+
+	parent := make(map[string]string, len(join))
+	var find func(c string) string
+	find = func(c string) string {
+		if parent[c] == c {
+			return c
+		}
+		parent[c] = find(parent[c])
+		return parent[c]
+	}
+	for _, c := range join {
+		parent[c] = c
+	}
+	for _, c := range join {
+		image := imageOf[c]
+		for _, l := range join {
+			if strings.HasPrefix(image, l) || strings.HasPrefix(l, image) {
+				parent[find(l)] = find(c)
+			}
+		}
+	}
+
+	classes := make(map[string][]string)
+	var roots []string
+	for _, c := range join {
+		root := find(c)
+		if _, ok := classes[root]; !ok {
+			roots = append(roots, root)
+		}
+		classes[root] = append(classes[root], c)
+	}
+
+	components := make([]InvariantComponent, 0, len(roots))
+	for _, root := range roots {
+		restriction := make(leafMap, len(join))
+		for _, c := range join {
+			restriction[c] = c
+		}
+		cones := make([]string, 0, len(classes[root]))
+		for _, c := range classes[root] {
+			restriction[c] = imageOf[c]
+			cones = append(cones, emptyAsRoot(c))
+		}
+		sort.Strings(cones)
+		element := identityOver(alpha)
+		element.setLeafMap(restriction)
+		element.Minimise()
+		components = append(components, InvariantComponent{Cones: cones, Restriction: element})
+	}
+	return components
+}
@@ -0,0 +1,44 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/loeksnokes/prefcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvariantComponents(t *testing.T) {
+
+	t.Run("InvariantComponents test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		c, _ := GeneratorC()
+		id := identityOver([]rune("01"))
+		sum, _ := DirectSum("0", c, "1", x0)
+		components := InvariantComponents(sum)
+		assert.Equal(t, 2, len(components))
+		assert.Equal(t, []string{"00", "010", "011"}, components[0].Cones)
+		assert.Equal(t, []string{"100", "101", "110", "111"}, components[1].Cones)
+		onLeft, _ := DirectSum("0", c, "1", id)
+		onRight, _ := DirectSum("0", id, "1", x0)
+		assert.True(t, EqualsAsElements(components[0].Restriction, onLeft))
+		assert.True(t, EqualsAsElements(components[1].Restriction, onRight))
+
+		components = InvariantComponents(id)
+		assert.Equal(t, 1, len(components))
+		assert.Equal(t, []string{prefcode.EmptyString}, components[0].Cones)
+		assert.Equal(t, 1, len(InvariantComponents(c)))
+	})
+
+	// the restrictions multiply back to the element.
+	t.Run("InvariantComponents product test", func(t *testing.T) {
+		e, _ := NewEnumerator("01", 4, GroupV)
+		e.Each(func(tp TreePair) bool {
+			product := identityOver([]rune("01"))
+			for _, v := range InvariantComponents(tp) {
+				product = Multiply(product, v.Restriction)
+			}
+			assert.True(t, EqualsAsElements(product, tp), tp.DFSString())
+			return true
+		})
+	})
+}
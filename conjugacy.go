@@ -0,0 +1,293 @@
+package treepair
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// ErrConjugacyUndecided is returned by the searches for conjugators bounded by a number of
+// leaves when the invariants of two elements agree but no conjugator was found within the
+// bound.
+var ErrConjugacyUndecided = errors.New("conjugacy undecided within the conjugator search bound")
+
+// SearchConjugacyInT reports whether the elements a and b of R. Thompson's group T (over the
+// same alphabet) are conjugate in T, as SearchConjugatorInT does.  It is only a semi-decision
+// procedure, bounded by maxLeaves: it may return ErrConjugacyUndecided.
+func SearchConjugacyInT(a, b TreePair, maxLeaves int) (bool, error) {
+	h, err := SearchConjugatorInT(a, b, maxLeaves)
+	return nil != h, err
+}
+
+// SearchConjugatorInT looks for an element h of T with h^-1 a h = b, that is with Multiply(a,
+// h) equal to Multiply(h, b), for elements a and b of R. Thompson's group T (over the same
+// alphabet).  It is a semi-decision procedure rather than a decision procedure: it returns h
+// when a and b are conjugate in T and h is found, nil when an invariant shows they are not,
+// and ErrConjugacyUndecided when neither settles the question.  Elements act on the circle,
+// and conjugate elements have the same rotation number p/q.  If a^q is the identity then a
+// is torsion: a and b then rotate the leaves of prefix codes they carry to themselves, and h
+// is built to carry the leaves of one code onto the other in order, expanding the codes
+// along whole orbits until they have the same size.  Otherwise conjugate elements have
+// matching fixed point data for a^q and b^q: the cyclic sequence of fixed points and fixed
+// intervals together with the slopes on either side.  When all of these agree, conjugators
+// are searched for among elements of T with up to maxLeaves leaves, and
+// ErrConjugacyUndecided is returned if none is found.  Neither a nor b is modified.
+func SearchConjugatorInT(a, b TreePair, maxLeaves int) (TreePair, error) {
+	if string(a.Alphabet()) != string(b.Alphabet()) {
+		return nil, errors.New("SearchConjugatorInT(): elements are over different alphabets")
+	}
+	minA, minB := clone(a), clone(b)
+	minA.Minimise()
+	minB.Minimise()
+	if !minA.InT() || !minB.InT() {
+		return nil, errors.New("SearchConjugatorInT(): elements are not both in T")
+	}
+	if EqualsAsElements(minA, minB) {
+		return identityOver(minA.Alphabet()), nil
+	}
+
+	qA, rotA, fixedA := periodicData(minA)
+	qB, rotB, fixedB := periodicData(minB)
+	if qA != qB || rotA.Cmp(rotB) != 0 {
+		return nil, nil
+	}
+	torsionA := isTorsionData(fixedA)
+	torsionB := isTorsionData(fixedB)
+	if torsionA != torsionB {
+		return nil, nil
+	}
+	if torsionA {
+		if h := torsionConjugator(minA, minB, qA); nil != h {
+			return h, nil
+		}
+	} else if !sameFixedData(fixedA, fixedB) {
+		return nil, nil
+	}
+
+	if h := findConjugator(minA, minB, GroupT, maxLeaves); nil != h {
+		return h, nil
+	}
+	return nil, ErrConjugacyUndecided
+}
+
+// isTorsionData reports whether the fixed components of g^q cover the whole circle, i.e.
+// whether g^q is the identity.
+func isTorsionData(fixed []fixedComponent) bool {
+	return 1 == len(fixed) && 0 == fixed[0].left.Sign() && fixed[0].right.Cmp(big.NewRat(1, 1)) == 0
+}
+
+// sameFixedData reports whether the cyclic sequences of fixed components agree up to
+// rotation, comparing the kind of each component and the slopes on either side of it.
+func sameFixedData(fixedA, fixedB []fixedComponent) bool {
+	if len(fixedA) != len(fixedB) {
+		return false
+	}
+	n := len(fixedA)
+	for shift := 0; shift < n; shift++ {
+		match := true
+		for k := 0; k < n && match; k++ {
+			u, v := fixedA[k], fixedB[(k+shift)%n]
+			match = u.isPoint() == v.isPoint() && u.slopeIn.Cmp(v.slopeIn) == 0 && u.slopeOut.Cmp(v.slopeOut) == 0
+		}
+		if match {
+			return true
+		}
+	}
+	return 0 == n
+}
+
+// findConjugator searches the elements h of the group g with at most maxLeaves leaves for one
+// with h^-1 a h = b, i.e. a h = h b, returning nil if there is none.
+func findConjugator(a, b TreePair, g GroupKind, maxLeaves int) *treePair {
+	return findSimultaneousConjugator([]TreePair{a}, []TreePair{b}, g, maxLeaves)
+}
+
+// findSimultaneousConjugator searches as findConjugator does for one h conjugating each
+// as[k] to bs[k].
+func findSimultaneousConjugator(as, bs []TreePair, g GroupKind, maxLeaves int) *treePair {
+	var found *treePair
+	for leaves := 1; leaves <= maxLeaves && nil == found; leaves++ {
+		forEachElement(as[0].Alphabet(), leaves, g, func(h *treePair) bool {
+			if !conjugatesAll(as, bs, h) {
+				return true
+			}
+			found = h
+			found.Minimise()
+			return false
+		})
+	}
+	return found
+}
+
+// conjugatesAll reports whether h^-1 as[k] h = bs[k] for every k.
+func conjugatesAll(as, bs []TreePair, h TreePair) bool {
+	for k := range as {
+		if !EqualsAsElements(Multiply(clone(as[k]), clone(h)), Multiply(clone(h), clone(bs[k]))) {
+			return false
+		}
+	}
+	return true
+}
+
+// AreSimultaneouslyConjugate decides whether a single element h of V conjugates each of the
+// elements as[k] to bs[k], with h^-1 as[k] h = bs[k], and returns such an h if so.  Each pair
+// must be conjugate on its own, as ConjugatorInV decides, and the conjugator found for the
+// first pair is tried on the others.  Conjugators are then searched for among elements of V
+// with up to maxLeaves leaves, and ErrConjugacyUndecided is returned if none is found.
+// Tuples of different lengths or over different alphabets give an error.  Empty tuples are
+// conjugate, with a nil h as they have no alphabet.  No element is modified.
+func AreSimultaneouslyConjugate(as, bs []TreePair, maxLeaves int) (TreePair, bool, error) {
+	if len(as) != len(bs) {
+		return nil, false, errors.New("AreSimultaneouslyConjugate(): tuples have different lengths")
+	}
+	if 0 == len(as) {
+		return nil, true, nil
+	}
+	alpha := string(as[0].Alphabet())
+	for k := range as {
+		if alpha != string(as[k].Alphabet()) || alpha != string(bs[k].Alphabet()) {
+			return nil, false, errors.New("AreSimultaneouslyConjugate(): elements are over different alphabets")
+		}
+	}
+
+	var first TreePair
+	for k := range as {
+		h, err := ConjugatorInV(as[k], bs[k])
+		if nil == h && nil == err {
+			return nil, false, nil
+		}
+		if 0 == k {
+			first = h
+		}
+	}
+	if nil != first && conjugatesAll(as, bs, first) {
+		return first, true, nil
+	}
+	if h := findSimultaneousConjugator(as, bs, GroupV, maxLeaves); nil != h {
+		return h, true, nil
+	}
+	return nil, false, ErrConjugacyUndecided
+}
+
+// AreConjugateInV decides whether the elements a and b of R. Thompson's group V (over the
+// same alphabet) are conjugate in V, as ConjugatorInV does.
+func AreConjugateInV(a, b TreePair) (bool, error) {
+	h, err := ConjugatorInV(a, b)
+	return nil != h, err
+}
+
+// ConjugatorInV returns an element h of V with h^-1 a h = b, that is with Multiply(a, h) equal
+// to Multiply(h, b), if the elements a and b of R. Thompson's group V (over the same
+// alphabet) are conjugate in V, and nil otherwise.  This is a decision procedure, following
+// Belk and Matucci: a and b are conjugate exactly when their reduced closed strand diagrams
+// agree, as ClosedStrandDiagram.Invariant tells, and h is read off an isomorphism between the
+// diagrams, keeping track of the cuts of the diagrams while they are reduced.  The time taken
+// may grow exponentially with the sizes of a and b.  Neither a nor b is modified.
+func ConjugatorInV(a, b TreePair) (TreePair, error) {
+	if string(a.Alphabet()) != string(b.Alphabet()) {
+		return nil, errors.New("ConjugatorInV(): elements are over different alphabets")
+	}
+	minA, minB := clone(a), clone(b)
+	minA.Minimise()
+	minB.Minimise()
+	if EqualsAsElements(minA, minB) {
+		return identityOver(minA.Alphabet()), nil
+	}
+	if h := closedConjugator(minA, minB); nil != h {
+		return h, nil
+	}
+	return nil, nil
+}
+
+// torsionConjugator builds a conjugator in T from the torsion element a of order order to b,
+// returning nil if the construction fails.  Each of a and b rotates the leaves of the prefix
+// code of invariantCycles in cyclic order, in cycles of length order, and h carries the k-th
+// leaf of the code for a onto the k-th leaf of the code for b.  Cycles are split into
+// len(alphabet) cycles of the same length, by expanding each of their cones, until the codes
+// have the same size.
+func torsionConjugator(a, b *treePair, order int) *treePair {
+	alpha := a.Alphabet()
+	cyclesA, cyclesB := invariantCycles(a, order), invariantCycles(b, order)
+	expand := func(cycles [][]string, k int) [][]string {
+		for _, c := range alpha {
+			expanded := make([]string, len(cycles[k]))
+			for i, v := range cycles[k] {
+				expanded[i] = v + string(c)
+			}
+			cycles = append(cycles, expanded)
+		}
+		return append(cycles[:k], cycles[k+1:]...)
+	}
+
+	for len(cyclesA) != len(cyclesB) {
+		if len(cyclesA) < len(cyclesB) {
+			cyclesA = expand(cyclesA, 0)
+			if len(cyclesA) > len(cyclesB) {
+				return nil
+			}
+		} else {
+			cyclesB = expand(cyclesB, 0)
+			if len(cyclesB) > len(cyclesA) {
+				return nil
+			}
+		}
+	}
+	var leavesA, leavesB []string
+	for k := range cyclesA {
+		leavesA = append(leavesA, cyclesA[k]...)
+		leavesB = append(leavesB, cyclesB[k]...)
+	}
+	sort.Strings(leavesA)
+	sort.Strings(leavesB)
+	h := make(leafMap)
+	for k, v := range leavesA {
+		h[v] = leavesB[k]
+	}
+
+	conjugator := h.treePair(alpha)
+	conjugator.Minimise()
+	if !EqualsAsElements(Multiply(clone(a), clone(conjugator)), Multiply(clone(conjugator), clone(b))) {
+		return nil
+	}
+	return conjugator
+}
+
+// invariantCycles returns the cycles in which the torsion element tp of order order carries
+// round the leaves of the prefix code joining the domain trees of its powers.  Each cycle
+// starts from its least leaf and follows tp, and the cycles come in dictionary order of their
+// first leaves.  Words are written with "" for the root.
+func invariantCycles(tp TreePair, order int) [][]string {
+	invariant := copyCode(tp.CodeDomain())
+	power := clone(tp)
+	for k := 2; k < order; k++ {
+		power = Multiply(power, clone(tp))
+		invariant = joinCodes(invariant, power.CodeDomain())
+	}
+
+	m := newLeafMap(tp)
+	image := func(p string) string {
+		for d, r := range m {
+			if strings.HasPrefix(p, d) {
+				return r + p[len(d):]
+			}
+		}
+		return p
+	}
+
+	var cycles [][]string
+	seen := map[string]bool{}
+	for _, leaf := range sortedLeaves(invariant) {
+		leaf = rootAsEmpty(leaf)
+		if seen[leaf] {
+			continue
+		}
+		var cycle []string
+		for p := leaf; !seen[p]; p = image(p) {
+			seen[p] = true
+			cycle = append(cycle, p)
+		}
+		cycles = append(cycles, cycle)
+	}
+	return cycles
+}
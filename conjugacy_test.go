@@ -0,0 +1,225 @@
+package treepair
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConjugacy(t *testing.T) {
+
+	conjugateInT := func(t *testing.T, wordA, wordB string) (bool, error) {
+		t.Helper()
+		a, err := EvaluateWord("01", wordA)
+		assert.Nil(t, err)
+		b, err := EvaluateWord("01", wordB)
+		assert.Nil(t, err)
+		return SearchConjugacyInT(a, b, 5)
+	}
+
+	t.Run("SearchConjugacyInT test", func(t *testing.T) {
+		for _, pair := range [][2]string{
+			{"C", "C"},
+			{"C", "A^-1 C A"},
+			{"A", "C^-1 A C"},
+			{"B", "C^-1 B C"},
+			{"C A", "A C"},
+			{"C^2", "B^-1 C^2 B"},
+		} {
+			got, err := conjugateInT(t, pair[0], pair[1])
+			assert.Nil(t, err)
+			assert.True(t, got, pair[0]+" should be conjugate to "+pair[1])
+		}
+
+		for _, pair := range [][2]string{
+			{"C", "C^2"},
+			{"A", "B"},
+			{"A", "A^2"},
+			{"A", "A^-1"},
+			{"C A", "C"},
+		} {
+			got, err := conjugateInT(t, pair[0], pair[1])
+			assert.Nil(t, err)
+			assert.False(t, got, pair[0]+" should not be conjugate to "+pair[1])
+		}
+	})
+
+	t.Run("SearchConjugacyInT error test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		p, _ := GeneratorPi0()
+		_, err := SearchConjugacyInT(c, p, 5)
+		assert.NotNil(t, err, "pi0 is not in T.")
+
+		ternary, _ := NewTreePairAlpha("012")
+		_, err = SearchConjugacyInT(c, ternary, 5)
+		assert.NotNil(t, err, "alphabets differ.")
+	})
+
+	conjugateInV := func(t *testing.T, wordA, wordB string) (bool, error) {
+		t.Helper()
+		a, err := EvaluateWord("01", wordA)
+		assert.Nil(t, err)
+		b, err := EvaluateWord("01", wordB)
+		assert.Nil(t, err)
+		return AreConjugateInV(a, b)
+	}
+
+	t.Run("AreConjugateInV test", func(t *testing.T) {
+		for _, pair := range [][2]string{
+			{"pi0", "A^-1 pi0 A"},
+			{"C", "C^2"},
+			{"A", "pi0 A pi0"},
+			{"A B", "B A"},
+		} {
+			got, err := conjugateInV(t, pair[0], pair[1])
+			assert.Nil(t, err)
+			assert.True(t, got, pair[0]+" should be conjugate to "+pair[1])
+		}
+
+		for _, pair := range [][2]string{
+			{"pi0", "C"},
+			{"A", "B"},
+			{"A", "A^2"},
+			{"A", "A^-1"},
+		} {
+			got, err := conjugateInV(t, pair[0], pair[1])
+			assert.Nil(t, err)
+			assert.False(t, got, pair[0]+" should not be conjugate to "+pair[1])
+		}
+
+		// swapping the halves fixes no cone, unlike pi0.
+		swap := identityOver([]rune("01"))
+		EncodeDFS(swap, "{100,100,1 0}")
+		p, _ := GeneratorPi0()
+		got, err := AreConjugateInV(swap, p)
+		assert.Nil(t, err)
+		assert.False(t, got, "{100,100,1 0} should not be conjugate to pi0.")
+	})
+
+	isConjugator := func(t *testing.T, a, b, h TreePair) {
+		t.Helper()
+		assert.NotNil(t, h)
+		assert.True(t, EqualsAsElements(Multiply(clone(a), clone(h)), Multiply(clone(h), clone(b))))
+	}
+
+	t.Run("SearchConjugatorInT test", func(t *testing.T) {
+		// torsion conjugates far beyond the search bound.
+		c, _ := EvaluateWord("01", "C")
+		b, _ := EvaluateWord("01", "A^-3 B^2 A C A^-1 B^-2 A^3")
+		h, err := SearchConjugatorInT(c, b, 5)
+		assert.Nil(t, err)
+		isConjugator(t, c, b, h)
+		assert.True(t, h.InT())
+
+		a, _ := EvaluateWord("01", "A")
+		b, _ = EvaluateWord("01", "C^-1 A C")
+		h, err = SearchConjugatorInT(a, b, 5)
+		assert.Nil(t, err)
+		isConjugator(t, a, b, h)
+
+		// no conjugator without leaves to search.
+		_, err = SearchConjugatorInT(a, b, 0)
+		assert.ErrorIs(t, err, ErrConjugacyUndecided)
+
+		h, err = SearchConjugatorInT(a, c, 5)
+		assert.Nil(t, err)
+		assert.Nil(t, h)
+	})
+
+	t.Run("ConjugatorInV test", func(t *testing.T) {
+		// one 2-cycle and two 2-cycles.
+		swap := identityOver([]rune("01"))
+		EncodeDFS(swap, "{100,100,1 0}")
+		swaps := identityOver([]rune("01"))
+		EncodeDFS(swaps, "{1100100,1100100,1 0 3 2}")
+		h, err := ConjugatorInV(swap, swaps)
+		assert.Nil(t, err)
+		isConjugator(t, swap, swaps, h)
+
+		p, _ := EvaluateWord("01", "pi0")
+		b, _ := EvaluateWord("01", "A^-2 C B pi0 B^-1 C^-1 A^2")
+		h, err = ConjugatorInV(p, b)
+		assert.Nil(t, err)
+		isConjugator(t, p, b, h)
+
+		h, err = ConjugatorInV(p, swap)
+		assert.Nil(t, err)
+		assert.Nil(t, h)
+	})
+
+	// random conjugates, over "01" and over "012", and random pairs of small elements, which
+	// must be conjugate whenever a conjugator with few leaves exists.
+	t.Run("ConjugatorInV random test", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1525))
+		randomV := func(leaves int) TreePair {
+			tp, err := randomPair(randomTreeShape(leaves, r), randomTreeShape(leaves, r), r.Perm(leaves))
+			assert.Nil(t, err)
+			return tp
+		}
+		for k := 0; k < 200; k++ {
+			a, h := randomV(1+r.Intn(10)), randomV(1+r.Intn(10))
+			hInv := clone(h)
+			hInv.Invert()
+			b := Multiply(Multiply(hInv, clone(a)), clone(h))
+			found, err := ConjugatorInV(a, b)
+			assert.Nil(t, err)
+			isConjugator(t, a, b, found)
+		}
+
+		var ternary []*treePair
+		forEachElement([]rune("012"), 3, GroupV, func(tp *treePair) bool {
+			ternary = append(ternary, tp)
+			return true
+		})
+		for k := 0; k < 50; k++ {
+			a, h := ternary[r.Intn(len(ternary))], ternary[r.Intn(len(ternary))]
+			hInv := clone(h)
+			hInv.Invert()
+			b := Multiply(Multiply(hInv, clone(a)), clone(h))
+			found, err := ConjugatorInV(a, b)
+			assert.Nil(t, err)
+			isConjugator(t, a, b, found)
+		}
+
+		for k := 0; k < 100; k++ {
+			a, b := randomV(1+r.Intn(3)), randomV(1+r.Intn(3))
+			found, err := ConjugatorInV(a, b)
+			assert.Nil(t, err)
+			if nil != found {
+				isConjugator(t, a, b, found)
+				continue
+			}
+			assert.Nil(t, findConjugator(a, b, GroupV, 3), a.DFSString()+" is conjugate to "+b.DFSString())
+		}
+	})
+
+	t.Run("AreSimultaneouslyConjugate test", func(t *testing.T) {
+		words := func(ws ...string) []TreePair {
+			tps := make([]TreePair, len(ws))
+			for k, w := range ws {
+				tps[k], _ = EvaluateWord("01", w)
+			}
+			return tps
+		}
+
+		as, bs := words("A", "B", "pi0"), words("C^-1 A C", "C^-1 B C", "C^-1 pi0 C")
+		h, ok, err := AreSimultaneouslyConjugate(as, bs, 5)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		for k := range as {
+			isConjugator(t, as[k], bs[k], h)
+		}
+
+		_, ok, err = AreSimultaneouslyConjugate(words("A", "pi0"), words("A", "C"), 5)
+		assert.Nil(t, err)
+		assert.False(t, ok, "pi0 is not conjugate to C.")
+
+		_, ok, err = AreSimultaneouslyConjugate(nil, nil, 5)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+
+		_, _, err = AreSimultaneouslyConjugate(words("A"), words("A", "B"), 5)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,27 @@
+package treepair
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// WriteCSV writes the prefix map of tp to w as CSV: a header row "domain,range,label" and
+// then a row for each domain leaf in dictionary order, with the range leaf it is carried to
+// and the label they share.
+func (tp *treePair) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"domain", "range", "label"}); nil != err {
+		return err
+	}
+	var err error
+	tp.Pairs(func(domainLeaf, rangeLeaf string) bool {
+		err = out.Write([]string{domainLeaf, rangeLeaf, strconv.Itoa(tp.CodeDomain().LabelAtLeaf(domainLeaf))})
+		return nil == err
+	})
+	if nil != err {
+		return err
+	}
+	out.Flush()
+	return out.Error()
+}
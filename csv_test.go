@@ -0,0 +1,31 @@
+package treepair
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/loeksnokes/prefcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSV(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	t.Run("WriteCSV test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		var out bytes.Buffer
+		assert.Nil(t, c.WriteCSV(&out))
+		assertCorrectMessage(t, out.String(), "domain,range,label\n0,11,0\n10,0,1\n11,10,2\n")
+
+		identity, _ := NewTreePairAlpha("01")
+		out.Reset()
+		assert.Nil(t, identity.WriteCSV(&out))
+		assertCorrectMessage(t, out.String(), "domain,range,label\n"+prefcode.EmptyString+","+prefcode.EmptyString+",0\n")
+	})
+}
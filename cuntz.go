@@ -0,0 +1,95 @@
+package treepair
+
+import (
+	"errors"
+	"strings"
+)
+
+// cuntzMonomial writes the generator s_w of the Cuntz algebra, or its adjoint if star, as
+// "s" followed by the letters of w, the empty word giving the unit "1".
+func cuntzMonomial(w string, star bool) string {
+	if w = rootAsEmpty(w); "" == w {
+		return "1"
+	}
+	if star {
+		return "s" + w + "*"
+	}
+	return "s" + w
+}
+
+// ToCuntz returns tp as the unitary of the Cuntz algebra O_n, n = len(alphabet), that
+// represents it: the sum over the pairs d -> r of the minimised tree pair, in dictionary order
+// of d, of the monomials s_r s_d*, each carrying the cone at d onto the cone at r.  So x0 over
+// "01", carrying 0, 10 and 11 to 00, 01 and 1, is "s00 s0* + s01 s10* + s1 s11*", and the
+// identity is "1".  tp itself is not modified.
+func ToCuntz(tp TreePair) string {
+	pairs := leafPairs(canonicalCopy(tp))
+	if 1 == len(pairs) {
+		return "1"
+	}
+	terms := make([]string, len(pairs))
+	for k, pair := range pairs {
+		terms[k] = cuntzMonomial(pair[1], false) + " " + cuntzMonomial(pair[0], true)
+	}
+	return strings.Join(terms, " + ")
+}
+
+// parseCuntzMonomial reads a generator of the Cuntz algebra written as by cuntzMonomial,
+// returning its word, with the unit standing for the empty word.  star says whether the
+// adjoint is expected.
+func parseCuntzMonomial(m string, star bool) (string, error) {
+	if "1" == m || (star && "1*" == m) {
+		return "", nil
+	}
+	if star {
+		if !strings.HasSuffix(m, "*") {
+			return "", errors.New("FromCuntz(): " + m + " is not the adjoint of a generator")
+		}
+		m = strings.TrimSuffix(m, "*")
+	}
+	if !strings.HasPrefix(m, "s") || 1 == len(m) {
+		return "", errors.New("FromCuntz(): " + m + " is not a generator s followed by a word")
+	}
+	return m[1:], nil
+}
+
+// FromCuntz reads the element of V over the alphabet alphaStr written as a unitary of the
+// Cuntz algebra, as ToCuntz writes it: a sum of monomials s_r s_d*, separated by "+", each
+// carrying the cone at d onto the cone at r, with "1" for the generator of the empty word and
+// a lone "1" for the identity.  The sum is a unitary exactly when the words d, and the words
+// r, are each a complete prefix code, and otherwise an error says which fails.  The tree pair
+// returned is minimised.
+func FromCuntz(alphaStr, expr string) (TreePair, error) {
+	if _, err := NewTreePairAlpha(alphaStr); nil != err {
+		return nil, err
+	}
+	var domain, ran []string
+	for _, term := range strings.Split(expr, "+") {
+		fields := strings.Fields(term)
+		if 1 == len(fields) && "1" == fields[0] {
+			fields = []string{"1", "1"}
+		}
+		if 2 != len(fields) {
+			return nil, errors.New("FromCuntz(): " + strings.TrimSpace(term) + " is not of the form s_r s_d*")
+		}
+		r, err := parseCuntzMonomial(fields[0], false)
+		if nil != err {
+			return nil, err
+		}
+		d, err := parseCuntzMonomial(fields[1], true)
+		if nil != err {
+			return nil, err
+		}
+		domain, ran = append(domain, d), append(ran, r)
+	}
+	p, err := NewPartialTreePair(alphaStr, domain, ran)
+	if nil != err {
+		return nil, errors.New("FromCuntz(): not a unitary: " + err.Error())
+	}
+	tp, err := p.ToTreePair()
+	if nil != err {
+		return nil, errors.New("FromCuntz(): not a unitary: " + err.Error())
+	}
+	tp.Minimise()
+	return tp, nil
+}
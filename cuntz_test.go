@@ -0,0 +1,48 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCuntz(t *testing.T) {
+
+	t.Run("ToCuntz test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		assert.Equal(t, "s00 s0* + s01 s10* + s1 s11*", ToCuntz(x0))
+		c, _ := GeneratorC()
+		assert.Equal(t, "s11 s0* + s0 s10* + s10 s11*", ToCuntz(c))
+		c.ExpandDomainAt("0")
+		assert.Equal(t, "s11 s0* + s0 s10* + s10 s11*", ToCuntz(c), "the element is minimised first.")
+		assert.Equal(t, "1", ToCuntz(identityOver([]rune("012"))))
+	})
+
+	t.Run("FromCuntz test", func(t *testing.T) {
+		for _, word := range []string{"", "C", "x0 x1^-1 x2", "pi0 C^2", "A B^-1"} {
+			tp, _ := EvaluateWord("01", word)
+			back, err := FromCuntz("01", ToCuntz(tp))
+			assert.Nil(t, err, word)
+			assert.True(t, EqualsAsElements(tp, back), word)
+		}
+		tp, err := FromCuntz("01", "s0 s1* + s1 s00* + s1 s01*")
+		assert.NotNil(t, err, "the range words repeat.")
+		assert.Nil(t, tp)
+
+		tp, err = FromCuntz("012", "s1 s0* +s2 s1*+ s0 s2*")
+		assert.Nil(t, err)
+		image, _ := ImageOfWord(tp, "0")
+		assert.Equal(t, "1", image)
+		tp, err = FromCuntz("01", "s00 s0* + s01 s10*")
+		assert.NotNil(t, err, "the words do not cover the Cantor set.")
+		tp, err = FromCuntz("01", "s00 s0 + s01 s10* + s1 s11*")
+		assert.NotNil(t, err, "s0 is not an adjoint.")
+		tp, err = FromCuntz("01", "s2 s0* + s1 s1*")
+		assert.NotNil(t, err, "2 is not a letter.")
+		tp, err = FromCuntz("01", "s1 s0* s1*")
+		assert.NotNil(t, err)
+		tp, err = FromCuntz("01", " 1 ")
+		assert.Nil(t, err)
+		assert.True(t, tp.IsIdentity())
+	})
+}
@@ -0,0 +1,136 @@
+package treepair
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/loeksnokes/prefcode"
+)
+
+// codeDepths returns the least and greatest depths of the leaves of pc, the root having
+// depth 0.
+func codeDepths(pc prefcode.PrefCode) (least, greatest int) {
+	for k, leaf := range sortedLeaves(pc) {
+		depth := wordLength(leaf)
+		if 0 == k || depth < least {
+			least = depth
+		}
+		if depth > greatest {
+			greatest = depth
+		}
+	}
+	return least, greatest
+}
+
+// MaxDepth returns the greatest depth of a leaf of the domain tree and of the range tree of
+// the minimal tree pair of tp, the root having depth 0.  tp itself is not modified.
+func (tp *treePair) MaxDepth() (dom, ran int) {
+	min := canonicalCopy(tp)
+	_, dom = codeDepths(min.CodeDomain())
+	_, ran = codeDepths(min.CodeRange())
+	return dom, ran
+}
+
+// MinDepth returns the least depth of a leaf of the domain tree and of the range tree of the
+// minimal tree pair of tp.  tp itself is not modified.
+func (tp *treePair) MinDepth() (dom, ran int) {
+	min := canonicalCopy(tp)
+	dom, _ = codeDepths(min.CodeDomain())
+	ran, _ = codeDepths(min.CodeRange())
+	return dom, ran
+}
+
+// DepthRange returns MaxDepth less MinDepth, the spread of the depths of the leaves of the
+// domain tree and of the range tree of the minimal tree pair of tp.  tp itself is not
+// modified.
+func (tp *treePair) DepthRange() (dom, ran int) {
+	min := canonicalCopy(tp)
+	leastDom, greatestDom := codeDepths(min.CodeDomain())
+	leastRan, greatestRan := codeDepths(min.CodeRange())
+	return greatestDom - leastDom, greatestRan - leastRan
+}
+
+// ShapeStats collects statistics of the shape of the minimal tree pair of an element.
+type ShapeStats struct {
+	// Leaves is the number of leaves of each tree.
+	Leaves int
+	// DomainDepths and RangeDepths count the leaves of the domain and range trees at each
+	// depth: DomainDepths[d] leaves of the domain tree have depth d.
+	DomainDepths, RangeDepths []int
+	// DepthDifferences counts the domain leaves by the depth of the leaf less the depth of the
+	// range leaf it is carried to, the exponent of the slope there as a power of the alphabet
+	// size.
+	DepthDifferences map[int]int
+}
+
+// depthHistogram returns the number of leaves of pc at each depth.
+func depthHistogram(pc prefcode.PrefCode) []int {
+	_, greatest := codeDepths(pc)
+	histogram := make([]int, greatest+1)
+	for _, leaf := range sortedLeaves(pc) {
+		histogram[wordLength(leaf)]++
+	}
+	return histogram
+}
+
+// Stats returns the statistics of the shape of the minimal tree pair of tp.  tp itself is not
+// modified.
+func (tp *treePair) Stats() ShapeStats {
+	min := canonicalCopy(tp)
+	s := ShapeStats{
+		Leaves:           min.Size(),
+		DomainDepths:     depthHistogram(min.CodeDomain()),
+		RangeDepths:      depthHistogram(min.CodeRange()),
+		DepthDifferences: make(map[int]int),
+	}
+	for _, pair := range leafPairs(min) {
+		s.DepthDifferences[wordLength(pair[0])-wordLength(pair[1])]++
+	}
+	return s
+}
+
+// ToLevelPermutation returns the permutation tp makes of the cones at depth k, numbered from 0
+// in dictionary order, the letters of the alphabet counting as base len(alphabet) digits in
+// their order: the cone numbered i is carried to the cone numbered perm[i].  Such a
+// permutation exists when, after ExpandToDepth(k), every domain leaf is carried to a range
+// leaf of the same depth, that is when the minimised tree pair has no domain leaf deeper than
+// k and carries each leaf to one of its own depth.  An error describes the first failure
+// otherwise.  tp itself is not modified.
+func ToLevelPermutation(tp TreePair, k int) ([]int, error) {
+	if k < 0 {
+		return nil, errors.New("ToLevelPermutation(): depth " + strconv.Itoa(k) + " is negative")
+	}
+	min := canonicalCopy(tp)
+	for _, pair := range leafPairs(min) {
+		d, r := wordLength(pair[0]), wordLength(pair[1])
+		if d > k {
+			return nil, errors.New("ToLevelPermutation(): domain leaf " + pair[0] + " is deeper than " + strconv.Itoa(k))
+		}
+		if d != r {
+			return nil, errors.New("ToLevelPermutation(): domain leaf " + pair[0] + " of depth " + strconv.Itoa(d) +
+				" is carried to " + pair[1] + " of depth " + strconv.Itoa(r))
+		}
+	}
+	if err := min.ExpandToDepth(k); nil != err {
+		return nil, err
+	}
+
+	alpha := min.Alphabet()
+	digit := make(map[rune]int, len(alpha))
+	for i, c := range alpha {
+		digit[c] = i
+	}
+	number := func(w string) int {
+		n := 0
+		for _, c := range rootAsEmpty(w) {
+			n = n*len(alpha) + digit[c]
+		}
+		return n
+	}
+	perm := make([]int, min.Size())
+	min.Pairs(func(domainLeaf, rangeLeaf string) bool {
+		perm[number(domainLeaf)] = number(rangeLeaf)
+		return true
+	})
+	return perm, nil
+}
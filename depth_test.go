@@ -0,0 +1,83 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDepth(t *testing.T) {
+
+	t.Run("Depth metrics test", func(t *testing.T) {
+		tp, _ := EvaluateWord("01", "x0 x1^-1 x2")
+		dom, ran := tp.MaxDepth()
+		assert.Equal(t, [2]int{4, 4}, [2]int{dom, ran})
+		dom, ran = tp.MinDepth()
+		assert.Equal(t, [2]int{1, 2}, [2]int{dom, ran})
+		dom, ran = tp.DepthRange()
+		assert.Equal(t, [2]int{3, 2}, [2]int{dom, ran})
+
+		id, _ := NewTreePairAlpha("01")
+		dom, ran = id.MaxDepth()
+		assert.Equal(t, [2]int{0, 0}, [2]int{dom, ran})
+		dom, ran = id.DepthRange()
+		assert.Equal(t, [2]int{0, 0}, [2]int{dom, ran})
+	})
+
+	t.Run("Depth metrics minimise test", func(t *testing.T) {
+		a, _ := GeneratorA()
+		a.ExpandDomainAt("111")
+		before := a.FullString()
+		dom, ran := a.MaxDepth()
+		assert.Equal(t, [2]int{2, 2}, [2]int{dom, ran})
+		dom, ran = a.MinDepth()
+		assert.Equal(t, [2]int{1, 1}, [2]int{dom, ran})
+		assert.Equal(t, before, a.FullString(), "the element is left alone.")
+	})
+
+	t.Run("Stats test", func(t *testing.T) {
+		tp, _ := EvaluateWord("01", "x0 x1^-1 x2")
+		tp.ExpandDomainAt("0")
+		assert.Equal(t, ShapeStats{
+			Leaves:           6,
+			DomainDepths:     []int{0, 1, 1, 0, 4},
+			RangeDepths:      []int{0, 0, 3, 1, 2},
+			DepthDifferences: map[int]int{-1: 1, 0: 3, 1: 1, 2: 1},
+		}, tp.Stats())
+
+		id, _ := NewTreePairAlpha("012")
+		assert.Equal(t, ShapeStats{Leaves: 1, DomainDepths: []int{1}, RangeDepths: []int{1}, DepthDifferences: map[int]int{0: 1}}, id.Stats())
+	})
+
+	t.Run("ToLevelPermutation test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		_, err := ToLevelPermutation(c, 2)
+		assert.NotNil(t, err, "C carries 0 to 11.")
+
+		pi0, _ := GeneratorPi0()
+		_, err = ToLevelPermutation(pi0, 1)
+		assert.NotNil(t, err, "the leaves 10 and 11 are deeper than 1.")
+		perm, err := ToLevelPermutation(pi0, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{0, 1, 3, 2}, perm)
+		perm, err = ToLevelPermutation(pi0, 3)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{0, 1, 2, 3, 6, 7, 4, 5}, perm)
+		_, err = ToLevelPermutation(pi0, -1)
+		assert.NotNil(t, err)
+
+		tp, _ := NewTreePairAlpha("012")
+		EncodeDFS(tp, "{1000,1000,2 0 1}")
+		perm, err = ToLevelPermutation(tp, 1)
+		assert.Nil(t, err)
+		image, _ := ImageOfWord(tp, "0")
+		assert.Equal(t, "1", image)
+		assert.Equal(t, []int{1, 2, 0}, perm)
+		_, err = ToLevelPermutation(tp, 0)
+		assert.NotNil(t, err, "the leaves have depth 1.")
+
+		perm, err = ToLevelPermutation(identityOver([]rune("01")), 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{0}, perm)
+	})
+}
@@ -0,0 +1,110 @@
+package treepair
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/loeksnokes/prefcode"
+)
+
+// DFSMode selects how exactly EncodeDFSMode expects DFS strings to be written.
+type DFSMode int
+
+const (
+	// DFSStrict accepts exactly the format written by DFSString, as "{11000,10100,1 2 0}":
+	// braces, no spaces around the commas and single spaces in the permutation.
+	DFSStrict DFSMode = iota
+	// DFSLenient also accepts surrounding whitespace, missing braces, spaces around the commas
+	// and within the trees, and permutations separated by any mix of spaces and commas, as in
+	// " 11000, 10100, 1,2,0 ".
+	DFSLenient
+)
+
+// DFSError describes why a DFS string could not be read.
+type DFSError struct {
+	// DFS is the string as given.
+	DFS string
+	// Part is "format", "domain", "range" or "permutation".
+	Part   string
+	Reason string
+}
+
+func (e *DFSError) Error() string {
+	return "EncodeDFS(): " + e.Part + " of " + e.DFS + ": " + e.Reason
+}
+
+// EncodeDFSMode sets tp to the element written in DFS notation, as EncodeDFS does, reading
+// the string as mode allows.  The trivial tree pair is "{0,0,0}".  Errors are *DFSError, and
+// tp is unchanged by a string which cannot be read.
+func EncodeDFSMode(tp TreePair, DFS string, mode DFSMode) error {
+	fail := func(part, reason string) error {
+		return &DFSError{DFS: DFS, Part: part, Reason: reason}
+	}
+
+	s := DFS
+	if DFSLenient == mode {
+		s = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(s), "{"), "}"))
+	} else {
+		if !strings.HasPrefix(s, "{") || !strings.HasSuffix(s, "}") {
+			return fail("format", "not enclosed in braces")
+		}
+		s = strings.TrimSuffix(strings.TrimPrefix(s, "{"), "}")
+	}
+
+	fields := strings.Split(s, ",")
+	var trees [2]string
+	var perm []string
+	switch {
+	case DFSStrict == mode && 3 != len(fields):
+		return fail("format", "not three fields separated by commas")
+	case DFSStrict == mode:
+		trees = [2]string{fields[0], fields[1]}
+		perm = strings.Split(fields[2], " ")
+	case len(fields) < 3:
+		return fail("format", "fewer than three fields separated by commas")
+	default:
+		trees = [2]string{strings.Join(strings.Fields(fields[0]), ""), strings.Join(strings.Fields(fields[1]), "")}
+		perm = strings.Fields(strings.Join(fields[2:], " "))
+	}
+
+	alphaSize := len(tp.Alphabet())
+	leaves := 0
+	for k, part := range []string{"domain", "range"} {
+		tree := trees[k]
+		if "" == strings.Trim(tree, "01") && ("0" == tree || prefcode.ValidDFSForPrefC(alphaSize, tree)) {
+			if 0 == k {
+				leaves = strings.Count(tree, "0")
+			} else if strings.Count(tree, "0") != leaves {
+				return fail(part, "the trees have different numbers of leaves")
+			}
+			continue
+		}
+		return fail(part, tree+" is not the DFS of a tree over an alphabet of size "+strconv.Itoa(alphaSize))
+	}
+
+	if len(perm) != leaves {
+		return fail("permutation", "has "+strconv.Itoa(len(perm))+" entries for "+strconv.Itoa(leaves)+" leaves")
+	}
+	labels := make(map[int]int, leaves)
+	seen := make([]bool, leaves)
+	for k, v := range perm {
+		label, err := strconv.Atoi(v)
+		if nil != err || label < 0 || label >= leaves || seen[label] {
+			return fail("permutation", "is not an ordering of 0, ..., "+strconv.Itoa(leaves-1))
+		}
+		seen[label] = true
+		labels[k] = label
+	}
+
+	for k, code := range []prefcode.PrefCode{tp.MutableCodeDomain(), tp.MutableCodeRange()} {
+		if "0" == trees[k] {
+			reduceCodeAt(code, prefcode.EmptyString)
+		} else if !prefcode.DFSToPrefCode(code, trees[k]) {
+			return fail([]string{"domain", "range"}[k], "could not be built")
+		}
+	}
+	if !tp.ApplyPermRange(labels) {
+		return fail("permutation", "could not be applied")
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package treepair
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDFS(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	t.Run("EncodeDFSMode strict test", func(t *testing.T) {
+		tp, _ := NewTreePairAlpha("01")
+		assert.Nil(t, EncodeDFSMode(tp, "{11000,10100,1 2 0}", DFSStrict))
+		assertCorrectMessage(t, tp.DFSString(), "{11000,10100,1 2 0}")
+		assert.Nil(t, EncodeDFSMode(tp, "{0,0,0}", DFSStrict))
+		assert.True(t, tp.IsIdentity())
+		assertCorrectMessage(t, tp.FullString(), "{D: [𝛆 0] || R: [𝛆 0]}")
+
+		for _, v := range []struct {
+			dfs, part string
+		}{
+			{"11000,10100,1 2 0", "format"},
+			{"{11000, 10100, 1 2 0}", "range"},
+			{"{11000,10100}", "format"},
+			{"{1100,10100,1 2 0}", "domain"},
+			{"{11000,1x100,1 2 0}", "range"},
+			{"{11000,1100100,1 2 0}", "range"},
+			{"{11000,10100,1  2 0}", "permutation"},
+			{"{11000,10100,1 2}", "permutation"},
+			{"{11000,10100,1 1 0}", "permutation"},
+			{"{11000,10100,1 2 3}", "permutation"},
+		} {
+			err := EncodeDFSMode(tp, v.dfs, DFSStrict)
+			var dfsErr *DFSError
+			assert.True(t, errors.As(err, &dfsErr), v.dfs)
+			assertCorrectMessage(t, dfsErr.Part, v.part)
+			assertCorrectMessage(t, dfsErr.DFS, v.dfs)
+			assert.True(t, tp.IsIdentity(), "tp is unchanged by "+v.dfs)
+		}
+		assertCorrectMessage(t, EncodeDFSMode(tp, "{11000,10100,1 2}", DFSStrict).Error(),
+			"EncodeDFS(): permutation of {11000,10100,1 2}: has 2 entries for 3 leaves")
+	})
+
+	t.Run("EncodeDFSMode lenient test", func(t *testing.T) {
+		for _, dfs := range []string{
+			"{11000,10100,1 2 0}",
+			"  11000, 10100, 1 2 0 ",
+			"{ 110 00 ,10100 , 1,2,0}",
+			"{11000,10100,1  2\t0}",
+		} {
+			tp, _ := NewTreePairAlpha("01")
+			assert.Nil(t, EncodeDFSMode(tp, dfs, DFSLenient), dfs)
+			assertCorrectMessage(t, tp.DFSString(), "{11000,10100,1 2 0}")
+		}
+		tp, _ := NewTreePairAlpha("01")
+		assert.Nil(t, EncodeDFSMode(tp, " 0, 0, 0 ", DFSLenient))
+		assert.True(t, tp.IsIdentity())
+		for _, dfs := range []string{"11000 10100 1 2 0", "{11000,10100,1 2 0 3}", "{11000,10100,a,b,c}"} {
+			assert.NotNil(t, EncodeDFSMode(tp, dfs, DFSLenient), dfs)
+		}
+	})
+}
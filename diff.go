@@ -0,0 +1,72 @@
+package treepair
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Diff describes, one difference per line, where the tree pairs a and b differ as written:
+// leaves of either tree present in one but not the other, common leaves with different
+// labels, and common domain leaves carried to different range leaves.  When they differ it
+// ends by saying whether they still represent the same element.  Diff returns "" for equal
+// tree pairs, and neither is modified.
+func Diff(a, b TreePair) string {
+	if string(a.Alphabet()) != string(b.Alphabet()) {
+		return "alphabets differ: " + string(a.Alphabet()) + " in a and " + string(b.Alphabet()) + " in b\n"
+	}
+	var lines []string
+	for _, side := range []struct {
+		name         string
+		codeA, codeB map[string]int
+	}{
+		{"domain", a.CodeDomain().Code(), b.CodeDomain().Code()},
+		{"range", a.CodeRange().Code(), b.CodeRange().Code()},
+	} {
+		if onlyA := missingLeaves(side.codeA, side.codeB); 0 != len(onlyA) {
+			lines = append(lines, side.name+" leaves only in a: "+strings.Join(onlyA, ", "))
+		}
+		if onlyB := missingLeaves(side.codeB, side.codeA); 0 != len(onlyB) {
+			lines = append(lines, side.name+" leaves only in b: "+strings.Join(onlyB, ", "))
+		}
+		for _, v := range missingLeaves(side.codeA, nil) {
+			if labelB, ok := side.codeB[v]; ok && labelB != side.codeA[v] {
+				lines = append(lines, side.name+" leaf "+v+" has label "+strconv.Itoa(side.codeA[v])+" in a and "+
+					strconv.Itoa(labelB)+" in b")
+			}
+		}
+	}
+
+	imageB := map[string]string{}
+	for _, v := range leafPairs(b) {
+		imageB[v[0]] = v[1]
+	}
+	for _, v := range leafPairs(a) {
+		if w, ok := imageB[v[0]]; ok && w != v[1] {
+			lines = append(lines, "a carries "+v[0]+" to "+v[1]+" and b carries it to "+w)
+		}
+	}
+
+	if 0 == len(lines) {
+		return ""
+	}
+	if EqualsAsElements(a, b) {
+		lines = append(lines, "they represent the same element")
+	} else {
+		lines = append(lines, "they represent different elements")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// missingLeaves lists in dictionary order the leaves of code which are not leaves of other,
+// which may be nil.
+func missingLeaves(code, other map[string]int) []string {
+	var leaves []string
+	for v := range code {
+		if _, ok := other[v]; !ok {
+			leaves = append(leaves, v)
+		}
+	}
+	sort.Strings(leaves)
+	return leaves
+}
@@ -0,0 +1,52 @@
+package treepair
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	parse := func(alphaStr, s string) TreePair {
+		tp, err := ParseElement(alphaStr, s)
+		if nil != err {
+			t.Fatal(err)
+		}
+		return tp
+	}
+
+	t.Run("Diff test", func(t *testing.T) {
+		x0 := parse("01", "{10100,11000,0 1 2}")
+		assertCorrectMessage(t, Diff(x0, parse("01", "{10100,11000,0 1 2}")), "")
+
+		// x0 with an extra caret: leaves differ but the element is the same.
+		assertCorrectMessage(t, Diff(x0, parse("01", "{1100100,1110000,0 1 2 3}")),
+			"domain leaves only in a: 0\n"+
+				"domain leaves only in b: 00, 01\n"+
+				"domain leaf 10 has label 1 in a and 2 in b\n"+
+				"domain leaf 11 has label 2 in a and 3 in b\n"+
+				"range leaves only in a: 00\n"+
+				"range leaves only in b: 000, 001\n"+
+				"range leaf 01 has label 1 in a and 2 in b\n"+
+				"range leaf 1 has label 2 in a and 3 in b\n"+
+				"they represent the same element\n")
+
+		// C and its inverse have the same trees and differ in the permutation.
+		assertCorrectMessage(t, Diff(parse("01", "{10100,10100,1 2 0}"), parse("01", "{10100,10100,2 0 1}")),
+			"range leaf 0 has label 1 in a and 2 in b\n"+
+				"range leaf 10 has label 2 in a and 0 in b\n"+
+				"range leaf 11 has label 0 in a and 1 in b\n"+
+				"a carries 0 to 11 and b carries it to 10\n"+
+				"a carries 10 to 0 and b carries it to 11\n"+
+				"a carries 11 to 10 and b carries it to 0\n"+
+				"they represent different elements\n")
+
+		assertCorrectMessage(t, Diff(x0, parse("012", "{0,0,0}")), "alphabets differ: 01 in a and 012 in b\n")
+	})
+}
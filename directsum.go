@@ -0,0 +1,68 @@
+package treepair
+
+import (
+	"errors"
+	"strings"
+)
+
+// DirectSum returns the element acting as g inside the cone at addr1, as h inside the cone at
+// addr2 and as the identity elsewhere: a pair d -> r of g gives the pair addr1 d -> addr1 r,
+// and likewise for h.  The cones must be disjoint, so neither address is a prefix of the
+// other, and g and h must be over the same alphabet.  g and h are not modified.
+func DirectSum(addr1 string, g TreePair, addr2 string, h TreePair) (*treePair, error) {
+	alpha := g.Alphabet()
+	if string(alpha) != string(h.Alphabet()) {
+		return nil, errors.New("DirectSum(): elements are over different alphabets")
+	}
+	addr1, addr2 = rootAsEmpty(addr1), rootAsEmpty(addr2)
+	for _, w := range []string{addr1, addr2} {
+		for _, c := range w {
+			if !strings.ContainsRune(string(alpha), c) {
+				return nil, errors.New("DirectSum(): letter " + string(c) + " of " + w + " is not in the alphabet " + string(alpha))
+			}
+		}
+	}
+	if strings.HasPrefix(addr1, addr2) || strings.HasPrefix(addr2, addr1) {
+		return nil, errors.New("DirectSum(): the cones at " + emptyAsRoot(addr1) + " and " + emptyAsRoot(addr2) + " are not disjoint")
+	}
+
+	sum := make(leafMap)
+	for _, leaf := range leavesAround(alpha, []string{addr1, addr2}) {
+		sum[leaf] = leaf
+	}
+	for _, v := range []struct {
+		addr string
+		tp   TreePair
+	}{{addr1, g}, {addr2, h}} {
+		delete(sum, v.addr)
+		for d, r := range newLeafMap(canonicalCopy(v.tp)) {
+			sum[v.addr+d] = v.addr + r
+		}
+	}
+	answer := identityOver(alpha)
+	answer.setLeafMap(sum)
+	return answer, nil
+}
+
+// leavesAround returns the leaves of the smallest tree over alpha having the words of the
+// antichain addrs among its leaves, writing the root as "".
+func leavesAround(alpha []rune, addrs []string) []string {
+	leaves := map[string]bool{"": true}
+	for _, w := range addrs {
+		prefix := ""
+		for _, c := range w {
+			if leaves[prefix] {
+				delete(leaves, prefix)
+				for _, a := range alpha {
+					leaves[prefix+string(a)] = true
+				}
+			}
+			prefix += string(c)
+		}
+	}
+	words := make([]string, 0, len(leaves))
+	for k := range leaves {
+		words = append(words, k)
+	}
+	return words
+}
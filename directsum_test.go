@@ -0,0 +1,53 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirectSum(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	// x0 in the cone at 0 and C in the cone at 10 fix the cone at 11.
+	t.Run("DirectSum test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		c, _ := GeneratorC()
+		sum, err := DirectSum("0", x0, "10", c)
+		assert.Nil(t, err)
+		for w, want := range map[string]string{"010": "001", "0111": "011", "100": "1011", "1010": "100", "11": "11", "110": "110"} {
+			image, err := ImageOfWord(sum, w)
+			assert.Nil(t, err)
+			assertCorrectMessage(t, image, want)
+		}
+		assert.False(t, sum.InT())
+
+		// the summands commute and make up the sum.
+		id := identityOver([]rune("01"))
+		left, _ := DirectSum("0", x0, "10", id)
+		right, _ := DirectSum("0", id, "10", c)
+		assert.True(t, EqualsAsElements(sum, Multiply(left, right)))
+		assert.True(t, EqualsAsElements(sum, Multiply(right, left)))
+		assertCorrectMessage(t, x0.DFSString(), "{10100,11000,0 1 2}")
+
+		whole, _ := DirectSum("0", id, "1", id)
+		assert.True(t, whole.IsIdentity())
+	})
+
+	t.Run("DirectSum errors test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		for _, addrs := range [][2]string{{"0", "01"}, {"10", "1"}, {"", "1"}, {"0", "2"}} {
+			_, err := DirectSum(addrs[0], x0, addrs[1], x0)
+			assert.NotNil(t, err, addrs)
+		}
+		c3, _ := ParseElement("012", "{1000,1000,1 2 0}")
+		_, err := DirectSum("0", x0, "1", c3)
+		assert.NotNil(t, err)
+	})
+}
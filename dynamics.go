@@ -0,0 +1,207 @@
+package treepair
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// DynamicsKind says how an element behaves on one component of its dynamics.
+type DynamicsKind int
+
+const (
+	// Periodic components are cycles of cones carried round onto one another, so that a power
+	// of the element fixes them pointwise.
+	Periodic DynamicsKind = iota
+	// Attracting components contain an attracting periodic orbit.
+	Attracting
+	// Repelling components contain a repelling periodic orbit.
+	Repelling
+)
+
+// String names the kind.
+func (k DynamicsKind) String() string {
+	switch k {
+	case Periodic:
+		return "periodic"
+	case Attracting:
+		return "attracting"
+	case Repelling:
+		return "repelling"
+	}
+	return "unknown"
+}
+
+// DynamicsComponent is one component of the dynamics of an element, read off a revealing pair.
+type DynamicsComponent struct {
+	Kind DynamicsKind
+	// Orbit lists the cones visited by the orbit in turn, Period of them.  For a periodic
+	// component these are the leaves of the cycle.  For an attracting (repelling) component
+	// they run forwards (backwards) from the root of the component of B-A (A-B) through leaves
+	// common to both trees.
+	Orbit  []string
+	Period int
+	// Point is the word u with Orbit[0] u the end of the chain, for attracting and repelling
+	// components, so that the periodic point is Orbit[0] u u u ....  It is empty for periodic
+	// components.
+	Point string
+}
+
+// Dynamics classifies the dynamics of tp: it computes a revealing pair and returns its
+// attracting components, then its repelling components, then its periodic cycles.  Points
+// outside these cones wander from repellers to attractors.  Words are written with "" for the
+// root.  tp itself is not modified.
+func Dynamics(tp TreePair) ([]DynamicsComponent, error) {
+	revealing, data, err := Revealing(tp)
+	if nil != err {
+		return nil, err
+	}
+	m := newLeafMap(revealing)
+	inverse := make(map[string]string, len(m))
+	for k, v := range m {
+		inverse[v] = k
+	}
+
+	var components []DynamicsComponent
+	for _, o := range data.Attractors {
+		orbit := []string{o.Root}
+		for len(orbit) < o.Period {
+			orbit = append(orbit, m[orbit[len(orbit)-1]])
+		}
+		components = append(components, DynamicsComponent{Kind: Attracting, Orbit: orbit, Period: o.Period, Point: o.Leaf[len(o.Root):]})
+	}
+	for _, o := range data.Repellers {
+		orbit := []string{o.Root}
+		for len(orbit) < o.Period {
+			orbit = append(orbit, inverse[orbit[len(orbit)-1]])
+		}
+		components = append(components, DynamicsComponent{Kind: Repelling, Orbit: orbit, Period: o.Period, Point: o.Leaf[len(o.Root):]})
+	}
+	for _, cycle := range data.Periodic {
+		components = append(components, DynamicsComponent{Kind: Periodic, Orbit: cycle, Period: len(cycle)})
+	}
+	return components, nil
+}
+
+// PeriodicOrbit is a finite orbit of cones, each carried onto the next by a prefix
+// substitution and the last onto the first, so that the Period-th power of the element fixes
+// every cone of the orbit pointwise.
+type PeriodicOrbit struct {
+	// Cones lists the cones of the orbit in turn, starting from the least in dictionary order.
+	Cones  []string
+	Period int
+}
+
+// PeriodicOrbits returns the periodic orbits of cones of tp, the cycles of leaves common to
+// the trees of a revealing pair, by increasing period and then dictionary order of their
+// first cones.  Every point outside these cones wanders from a repeller to an attractor, bar
+// the periodic points of the repellers and attractors themselves, which Dynamics reports.
+// Cycles whose cones are the children of one caret at every step, each child carried to the
+// child with the same letter, are merged into the cycle of the carets' roots, so the orbits
+// do not depend on how far the revealing pair happens to be subdivided.  The orbits are not
+// an invariant of conjugacy, which may split a cone of an orbit into cones lying in different
+// orbits: Periods gives what is.  Words are written with "" for the root.  tp itself is not
+// modified.
+func PeriodicOrbits(tp TreePair) ([]PeriodicOrbit, error) {
+	_, data, err := Revealing(tp)
+	if nil != err {
+		return nil, err
+	}
+	cycles := append([][]string{}, data.Periodic...)
+	for merged := true; merged; {
+		cycles, merged = mergeSiblingCycles(tp.Alphabet(), cycles)
+	}
+	orbits := make([]PeriodicOrbit, len(cycles))
+	for k, cycle := range cycles {
+		least := 0
+		for i, c := range cycle {
+			if c < cycle[least] {
+				least = i
+			}
+		}
+		orbits[k] = PeriodicOrbit{Cones: append(append([]string{}, cycle[least:]...), cycle[:least]...), Period: len(cycle)}
+	}
+	sort.SliceStable(orbits, func(i, j int) bool {
+		if orbits[i].Period != orbits[j].Period {
+			return orbits[i].Period < orbits[j].Period
+		}
+		return orbits[i].Cones[0] < orbits[j].Cones[0]
+	})
+	return orbits, nil
+}
+
+// mergeSiblingCycles looks for cycles of cones, one for each letter of alpha, whose k-th cones
+// are the children of one caret for every k, each child carried to the child with the same
+// letter.  It replaces the first such family it finds by the cycle of the roots of the carets,
+// and reports whether it found one.
+func mergeSiblingCycles(alpha []rune, cycles [][]string) ([][]string, bool) {
+	type place struct{ cycle, step int }
+	at := make(map[string]place)
+	for i, cycle := range cycles {
+		for k, c := range cycle {
+			at[c] = place{i, k}
+		}
+	}
+	for i, cycle := range cycles {
+		if "" == cycle[0] {
+			continue
+		}
+		letter := lastRune(cycle[0])
+		roots := make([]string, len(cycle))
+		family := true
+		for k, c := range cycle {
+			if "" == c || lastRune(c) != letter {
+				family = false
+				break
+			}
+			roots[k] = c[:len(c)-utf8.RuneLen(letter)]
+		}
+		members := map[int]bool{i: true}
+		for _, b := range alpha {
+			if !family || b == letter {
+				continue
+			}
+			sibling, ok := at[roots[0]+string(b)]
+			if !ok || len(cycles[sibling.cycle]) != len(cycle) {
+				family = false
+				break
+			}
+			other := cycles[sibling.cycle]
+			for k := range cycle {
+				if other[(sibling.step+k)%len(other)] != roots[k]+string(b) {
+					family = false
+					break
+				}
+			}
+			members[sibling.cycle] = true
+		}
+		if !family {
+			continue
+		}
+		kept := [][]string{roots}
+		for j, other := range cycles {
+			if !members[j] {
+				kept = append(kept, other)
+			}
+		}
+		return kept, true
+	}
+	return cycles, false
+}
+
+// Periods returns the periods of the periodic orbits of cones of tp, as PeriodicOrbits finds
+// them, in increasing order and each once.  These are the periods of the periodic points of
+// tp with a neighbourhood of periodic points of the same period, which conjugation carries to
+// one another, so the list is an invariant of conjugacy in V.  tp itself is not modified.
+func Periods(tp TreePair) ([]int, error) {
+	orbits, err := PeriodicOrbits(tp)
+	if nil != err {
+		return nil, err
+	}
+	var periods []int
+	for _, o := range orbits {
+		if 0 == len(periods) || periods[len(periods)-1] != o.Period {
+			periods = append(periods, o.Period)
+		}
+	}
+	return periods, nil
+}
@@ -0,0 +1,128 @@
+package treepair
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamics(t *testing.T) {
+
+	t.Run("Dynamics test", func(t *testing.T) {
+		b, _ := GeneratorB()
+		components, err := Dynamics(b)
+		assert.Nil(t, err)
+		assert.Equal(t, []DynamicsComponent{
+			{Kind: Attracting, Orbit: []string{"10"}, Period: 1, Point: "0"},
+			{Kind: Repelling, Orbit: []string{"11"}, Period: 1, Point: "1"},
+			{Kind: Periodic, Orbit: []string{"0"}, Period: 1},
+		}, components)
+
+		c, _ := GeneratorC()
+		components, err = Dynamics(c)
+		assert.Nil(t, err)
+		assert.Equal(t, []DynamicsComponent{{Kind: Periodic, Orbit: []string{"0", "11", "10"}, Period: 3}}, components)
+	})
+
+	// B pi0 C has a repelling orbit of period 2, through 11 and 0.
+	t.Run("Dynamics period test", func(t *testing.T) {
+		tp, _ := EvaluateWord("01", "B pi0 C")
+		components, err := Dynamics(tp)
+		assert.Nil(t, err)
+		assert.Equal(t, DynamicsComponent{Kind: Repelling, Orbit: []string{"11", "0"}, Period: 2, Point: "1"}, components[1])
+		for _, v := range components {
+			assert.Equal(t, len(v.Orbit), v.Period)
+			if Periodic == v.Kind {
+				continue
+			}
+			pow := v.Period
+			if Repelling == v.Kind {
+				pow = -pow
+			}
+			image, err := ImageOfWord(Power(tp, pow), v.Orbit[0])
+			assert.Nil(t, err)
+			assert.Equal(t, v.Orbit[0]+v.Point, image)
+		}
+	})
+
+	t.Run("DynamicsKind String test", func(t *testing.T) {
+		assert.Equal(t, "periodic attracting repelling", Periodic.String()+" "+Attracting.String()+" "+Repelling.String())
+	})
+
+	t.Run("PeriodicOrbits test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		b, _ := GeneratorB()
+		sum, err := DirectSum("0", c, "1", b)
+		assert.Nil(t, err)
+		orbits, err := PeriodicOrbits(sum)
+		assert.Nil(t, err)
+		assert.Equal(t, []PeriodicOrbit{
+			{Cones: []string{"10"}, Period: 1},
+			{Cones: []string{"00", "011", "010"}, Period: 3},
+		}, orbits)
+		for _, o := range orbits {
+			assert.True(t, FixesCone(Power(sum, o.Period), o.Cones[0]))
+		}
+
+		x0, _ := GeneratorX(0)
+		orbits, err = PeriodicOrbits(x0)
+		assert.Nil(t, err)
+		assert.Empty(t, orbits)
+
+		orbits, err = PeriodicOrbits(identityOver([]rune("01")))
+		assert.Nil(t, err)
+		assert.Equal(t, []PeriodicOrbit{{Cones: []string{""}, Period: 1}}, orbits)
+	})
+
+	t.Run("mergeSiblingCycles test", func(t *testing.T) {
+		alpha := []rune("01")
+		merged, ok := mergeSiblingCycles(alpha, [][]string{{"00", "10"}, {"11", "01"}, {"111"}})
+		assert.True(t, ok)
+		assert.Equal(t, [][]string{{"0", "1"}, {"111"}}, merged)
+
+		// 00 and 011 lie in one cycle, but are not siblings.
+		cycles := [][]string{{"00", "010"}, {"011", "1"}}
+		merged, ok = mergeSiblingCycles(alpha, cycles)
+		assert.False(t, ok)
+		assert.Equal(t, cycles, merged)
+		// the children of 0 are carried to children of 1 with the letters swapped.
+		_, ok = mergeSiblingCycles(alpha, [][]string{{"00", "11"}, {"01", "10"}})
+		assert.False(t, ok)
+	})
+
+	// the periods survive conjugation, which may cut the cycles of cones differently.
+	t.Run("Periods conjugacy test", func(t *testing.T) {
+		swap, _ := ParseElement("01", "{100,100,1 0}")
+		conjugate, _ := ParseElement("01", "{1101000,1101000,1 0 3 2}")
+		orbits, err := PeriodicOrbits(swap)
+		assert.Nil(t, err)
+		assert.Equal(t, []PeriodicOrbit{{Cones: []string{"0", "1"}, Period: 2}}, orbits)
+		orbits, err = PeriodicOrbits(conjugate)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(orbits))
+		periods, err := Periods(conjugate)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{2}, periods)
+
+		c, _ := GeneratorC()
+		b, _ := GeneratorB()
+		x0, _ := GeneratorX(0)
+		sum, _ := DirectSum("0", c, "1", b)
+		mixed, _ := DirectSum("0", swap, "1", x0)
+		r := rand.New(rand.NewSource(1601))
+		for _, g := range []TreePair{swap, c, sum, mixed, identityOver([]rune("01"))} {
+			want, err := Periods(g)
+			assert.Nil(t, err)
+			for k := 0; k < 10; k++ {
+				leaves := 2 + r.Intn(4)
+				h, err := randomPair(randomTreeShape(leaves, r), randomTreeShape(leaves, r), r.Perm(leaves))
+				assert.Nil(t, err)
+				conjugated := Multiply(Multiply(Power(h, -1), clone(g)), clone(h))
+				got, err := Periods(conjugated)
+				assert.Nil(t, err)
+				assert.Equal(t, want, got, g.DFSString()+" conjugated by "+h.DFSString())
+			}
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package treepair
+
+// Element is an immutable element of F, T or V: every operation returns a new Element and
+// none changes its receiver, so Elements can be shared freely, across goroutines too.  It
+// holds a minimised tree pair with labels reset, which is never handed out.  The zero Element
+// is the identity over "01".
+type Element struct {
+	min *treePair
+}
+
+// NewElement returns the Element represented by tp, which is not modified.
+func NewElement(tp TreePair) Element {
+	return Element{min: canonicalCopy(tp)}
+}
+
+// ParseImmutable reads an Element over the alphabet alphaStr, written as for ParseElement.
+func ParseImmutable(alphaStr, s string) (Element, error) {
+	tp, err := ParseElement(alphaStr, s)
+	if nil != err {
+		return Element{}, err
+	}
+	return NewElement(tp), nil
+}
+
+// pair returns the tree pair of e, which must not be modified.
+func (e Element) pair() *treePair {
+	if nil == e.min {
+		return identityOver([]rune("01"))
+	}
+	return e.min
+}
+
+// TreePair returns a mutable copy of the minimal tree pair of e.
+func (e Element) TreePair() TreePair {
+	return clone(e.pair())
+}
+
+// Alphabet returns a copy of the alphabet of e.
+func (e Element) Alphabet() []rune {
+	return e.pair().Alphabet()
+}
+
+// Multiply returns the product of e and other, applying e first.  Both must be over the same
+// alphabet.
+func (e Element) Multiply(other Element) Element {
+	return Element{min: canonicalCopy(Multiply(clone(e.pair()), clone(other.pair())))}
+}
+
+// Inverse returns the inverse of e.
+func (e Element) Inverse() Element {
+	return e.Power(-1)
+}
+
+// Power returns the n-th power of e, for any integer n.
+func (e Element) Power(n int) Element {
+	return Element{min: canonicalCopy(Power(e.pair(), n))}
+}
+
+// Equal reports whether e and other are the same element.
+func (e Element) Equal(other Element) bool {
+	return 0 == e.Compare(other)
+}
+
+// Compare orders elements as the function Compare does.
+func (e Element) Compare(other Element) int {
+	return Compare(e.pair(), other.pair())
+}
+
+// Hash returns the hash of e, as TreePair's Hash does.
+func (e Element) Hash() uint64 {
+	return e.pair().Hash()
+}
+
+func (e Element) IsIdentity() bool { return e.pair().IsIdentity() }
+func (e Element) InF() bool        { return e.pair().InF() }
+func (e Element) InT() bool        { return e.pair().InT() }
+func (e Element) InV() bool        { return e.pair().InV() }
+func (e Element) Size() int        { return e.pair().Size() }
+
+// DFSString returns the DFS notation of the minimal tree pair of e.
+func (e Element) DFSString() string {
+	return e.pair().DFSString()
+}
+
+// String returns the Full notation of the minimal tree pair of e.
+func (e Element) String() string {
+	return e.pair().FullString()
+}
@@ -0,0 +1,104 @@
+package treepair
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElement(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	// C = {10100,10100,1 2 0} has order 3.
+	t.Run("Element test", func(t *testing.T) {
+		c, err := ParseImmutable("01", "{10100,10100,1 2 0}")
+		assert.Nil(t, err)
+		c2 := c.Multiply(c)
+		assertCorrectMessage(t, c.DFSString(), "{10100,10100,1 2 0}")
+		assertCorrectMessage(t, c2.DFSString(), "{10100,10100,2 0 1}")
+		assert.True(t, c2.Equal(c.Inverse()))
+		assert.True(t, c.Power(3).IsIdentity())
+		assert.True(t, c.Power(3).Equal(Element{}))
+		assert.True(t, c.InT() && !c.InF())
+		assert.Equal(t, 3, c.Size())
+		assertCorrectMessage(t, c.String(), "{D: [0 0], [10 1], [11 2] || R: [0 1], [10 2], [11 0]}")
+
+		tp, _ := NewTreePairAlpha("01")
+		EncodeDFS(tp, "{1100100,1110000,0 1 2 3}")
+		x0 := NewElement(tp)
+		assertCorrectMessage(t, tp.DFSString(), "{1100100,1110000,0 1 2 3}")
+		assertCorrectMessage(t, x0.DFSString(), "{10100,11000,0 1 2}")
+		assert.Equal(t, tp.Hash(), x0.Hash())
+		assert.Equal(t, -1, x0.Compare(x0.Power(2)))
+
+		// changing a copy leaves the element alone.
+		mutable := x0.TreePair()
+		mutable.Invert()
+		assertCorrectMessage(t, x0.DFSString(), "{10100,11000,0 1 2}")
+		assert.True(t, NewElement(mutable).Equal(x0.Inverse()))
+
+		_, err = ParseImmutable("01", "{10100,10100,1 2}")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Element shared test", func(t *testing.T) {
+		c, _ := ParseImmutable("01", "{10100,10100,1 2 0}")
+		x1, err := ParseImmutable("01", "{1010100,1101000,0 1 2 3}")
+		assert.Nil(t, err)
+		var wg sync.WaitGroup
+		results := make([]Element, 8)
+		for k := range results {
+			wg.Add(1)
+			go func(k int) {
+				defer wg.Done()
+				results[k] = c.Multiply(x1).Multiply(c.Power(k))
+			}(k)
+		}
+		wg.Wait()
+		for k, v := range results {
+			assert.True(t, v.Equal(results[k%3]))
+		}
+		assertCorrectMessage(t, c.DFSString(), "{10100,10100,1 2 0}")
+	})
+
+	// goroutines reading one Element whose codes are shared with a copy: reads must leave the
+	// codes in place, which go test -race checks.
+	t.Run("Element concurrent reads test", func(t *testing.T) {
+		x1, _ := ParseImmutable("01", "{1010100,1101000,0 1 2 3}")
+		copied := x1.TreePair()
+		want, full, hash := x1.DFSString(), x1.String(), x1.Hash()
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		results := make([]string, 8)
+		for k := range results {
+			wg.Add(1)
+			go func(k int) {
+				defer wg.Done()
+				<-start
+				for j := 0; j < 20; j++ {
+					x1.TreePair()
+					assert.Equal(t, hash, x1.Hash())
+					assert.Equal(t, full, x1.String())
+					x1.InF()
+					x1.Compare(x1.Power(k))
+					x1.Multiply(x1)
+					Classify(x1.pair())
+					results[k] = x1.DFSString()
+				}
+			}(k)
+		}
+		close(start)
+		wg.Wait()
+		for _, v := range results {
+			assert.Equal(t, want, v)
+		}
+		assert.Equal(t, want, copied.DFSString())
+	})
+}
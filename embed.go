@@ -0,0 +1,85 @@
+package treepair
+
+import (
+	"errors"
+	"strconv"
+)
+
+// EmbedFInT returns a copy of tp, an element of F, as an element of T.  F is a subgroup of T
+// and its tree pairs are tree pairs of T already, so nothing changes but the checking.
+func EmbedFInT(tp TreePair) (*treePair, error) {
+	if !tp.InF() {
+		return nil, errors.New("EmbedFInT(): " + tp.DFSString() + " is not in F")
+	}
+	return clone(tp), nil
+}
+
+// EmbedTInV returns a copy of tp, an element of T, as an element of V, as EmbedFInT does.
+func EmbedTInV(tp TreePair) (*treePair, error) {
+	if !tp.InT() {
+		return nil, errors.New("EmbedTInV(): " + tp.DFSString() + " is not in T")
+	}
+	return clone(tp), nil
+}
+
+/*
+EmbedV2InVn returns the image of tp, an element of V_2, in V_n, over the first n of the letters
+of Reencode.  For n = 2 this is a copy of tp.
+
+For n > 2, each binary word w stands for a block of n-1 cones of the n-ary Cantor set.  The
+root stands for the cones at the letters 0, ..., n-2.  If w stands for the block c_1, ...,
+c_{n-1}, expand c_1 to its n children c_1 0, ..., c_1 (n-1): then w0 stands for c_1 0, ...,
+c_1 (n-2), the first n-1 of the cones, and w1 stands for c_1 (n-1), c_2, ..., c_{n-1}, the
+rest.  A pair d -> r of tp then carries the k-th cone of the block of d onto the k-th cone of
+the block of r, for each k, and the cone at n-1 is fixed.  Carrying the blocks cone by cone
+respects the splitting of blocks, so this is an injective homomorphism.
+*/
+func EmbedV2InVn(tp TreePair, n int) (*treePair, error) {
+	alpha := tp.Alphabet()
+	if 2 != len(alpha) {
+		return nil, errors.New("EmbedV2InVn(): " + string(alpha) + " is not a binary alphabet")
+	}
+	if n < 2 || n > len(standInLetters) {
+		return nil, errors.New("EmbedV2InVn(): alphabet size " + strconv.Itoa(n) + " is not between 2 and " + strconv.Itoa(len(standInLetters)))
+	}
+	newAlpha := []rune(standInLetters[:n])
+	m := newLeafMap(canonicalCopy(tp))
+	if 2 == n {
+		answer := identityOver(newAlpha)
+		answer.setLeafMap(m)
+		return answer, nil
+	}
+
+	embedded := make(leafMap, (n-1)*len(m)+1)
+	embedded[string(newAlpha[n-1])] = string(newAlpha[n-1])
+	for d, r := range m {
+		domainBlock, rangeBlock := coneBlock(alpha, newAlpha, d), coneBlock(alpha, newAlpha, r)
+		for k := range domainBlock {
+			embedded[domainBlock[k]] = rangeBlock[k]
+		}
+	}
+	answer := identityOver(newAlpha)
+	answer.setLeafMap(embedded)
+	return answer, nil
+}
+
+// coneBlock returns the n-1 cones of the block the binary word w stands for, as described for
+// EmbedV2InVn, where n is the length of newAlpha.
+func coneBlock(alpha, newAlpha []rune, w string) []string {
+	n := len(newAlpha)
+	block := make([]string, n-1)
+	for k := range block {
+		block[k] = string(newAlpha[k])
+	}
+	for _, c := range w {
+		first := block[0]
+		if alpha[0] == c {
+			for k := range block {
+				block[k] = first + string(newAlpha[k])
+			}
+		} else {
+			block[0] = first + string(newAlpha[n-1])
+		}
+	}
+	return block
+}
@@ -0,0 +1,70 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbed(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	t.Run("EmbedFInT and EmbedTInV test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		c, _ := GeneratorC()
+		pi0, _ := GeneratorPi0()
+		inT, err := EmbedFInT(x0)
+		assert.Nil(t, err)
+		assert.True(t, EqualsAsElements(inT, x0) && inT.InT())
+		_, err = EmbedFInT(c)
+		assert.NotNil(t, err)
+		inV, err := EmbedTInV(c)
+		assert.Nil(t, err)
+		assert.True(t, EqualsAsElements(inV, c))
+		_, err = EmbedTInV(pi0)
+		assert.NotNil(t, err)
+	})
+
+	// x0 acts on the cones at 0 and 1 of the ternary Cantor set and fixes the cone at 2.
+	t.Run("EmbedV2InVn test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		ternary, err := EmbedV2InVn(x0, 3)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, ternary.FullString(), "{D: [00 0], [01 1], [020 2], [021 3], [022 4], [1 5], [2 6] || R: [000 0], [001 1], [002 2], [01 3], [02 4], [1 5], [2 6]}")
+		binary, _ := EmbedV2InVn(x0, 2)
+		assert.True(t, EqualsAsElements(binary, x0))
+
+		_, err = EmbedV2InVn(x0, 1)
+		assert.NotNil(t, err)
+		c3, _ := ParseElement("012", "{1000,1000,1 2 0}")
+		_, err = EmbedV2InVn(c3, 4)
+		assert.NotNil(t, err)
+	})
+
+	// the embedding is a homomorphism and sends only the identity to the identity.
+	t.Run("EmbedV2InVn homomorphism test", func(t *testing.T) {
+		e, _ := NewEnumerator("01", 3, GroupV)
+		var elements []TreePair
+		e.Each(func(tp TreePair) bool {
+			elements = append(elements, tp)
+			return true
+		})
+		for _, n := range []int{3, 4, 5} {
+			for _, a := range elements {
+				ea, _ := EmbedV2InVn(a, n)
+				assert.Equal(t, a.IsIdentity(), ea.IsIdentity())
+				for _, b := range elements {
+					eb, _ := EmbedV2InVn(b, n)
+					eab, _ := EmbedV2InVn(Multiply(Power(a, 1), Power(b, 1)), n)
+					assert.True(t, EqualsAsElements(eab, Multiply(ea, eb)))
+				}
+			}
+		}
+	})
+}
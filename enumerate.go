@@ -0,0 +1,158 @@
+package treepair
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// GroupKind picks out one of R. Thompson's groups F, T or V by the permutations it allows.
+type GroupKind int
+
+const (
+	GroupF GroupKind = iota // trivial permutations
+	GroupT                  // cyclic rotations
+	GroupV                  // all permutations
+)
+
+// Enumerator lists the reduced tree pairs of one of the groups F, T or V over an alphabet
+// with at most a given number of leaves.
+type Enumerator struct {
+	alphabet  []rune
+	maxLeaves int
+	group     GroupKind
+}
+
+// NewEnumerator returns an enumerator of the elements of the group g over the alphabet
+// alphaStr whose reduced tree pairs have at most maxLeaves leaves.
+func NewEnumerator(alphaStr string, maxLeaves int, g GroupKind) (*Enumerator, error) {
+	if _, err := NewTreePairAlpha(alphaStr); nil != err {
+		return nil, err
+	}
+	if g < GroupF || g > GroupV {
+		return nil, errors.New("NewEnumerator(): unknown group " + strconv.Itoa(int(g)))
+	}
+	return &Enumerator{alphabet: []rune(alphaStr), maxLeaves: maxLeaves, group: g}, nil
+}
+
+// Each calls visit on every element of the enumerator, by increasing number of leaves, until
+// visit returns false.  Each element is visited once, as its reduced tree pair, which visit
+// may keep and modify.  Returns false if visit stopped early.
+func (e *Enumerator) Each(visit func(tp TreePair) bool) bool {
+	for leaves := 1; leaves <= e.maxLeaves; leaves++ {
+		if !forEachElement(e.alphabet, leaves, e.group, func(tp *treePair) bool { return visit(tp) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// Count returns the number of elements of the enumerator.
+func (e *Enumerator) Count() int {
+	count := 0
+	e.Each(func(TreePair) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// treeShapes returns the DFS strings of all trees over an alphabet of size alphaSize with
+// exactly leaves leaves (none unless leaves is 1 more than a multiple of alphaSize-1).
+func treeShapes(alphaSize, leaves int) []string {
+	if 1 == leaves {
+		return []string{"0"}
+	}
+	if leaves < 1 || alphaSize < 2 || 0 != (leaves-1)%(alphaSize-1) {
+		return nil
+	}
+	// a caret followed by alphaSize subtrees whose leaves add up to leaves.
+	var shapes []string
+	var fill func(prefix string, children, remaining int)
+	fill = func(prefix string, children, remaining int) {
+		if 0 == children {
+			if 0 == remaining {
+				shapes = append(shapes, prefix)
+			}
+			return
+		}
+		for first := 1; first <= remaining-(children-1); first++ {
+			for _, sub := range treeShapes(alphaSize, first) {
+				fill(prefix+sub, children-1, remaining-first)
+			}
+		}
+	}
+	fill("1", alphaSize, leaves)
+	return shapes
+}
+
+// groupPermutations returns the range labellings with size leaves allowed in the group g.
+func groupPermutations(g GroupKind, size int) [][]int {
+	identity := make([]int, size)
+	for k := range identity {
+		identity[k] = k
+	}
+	switch g {
+	case GroupF:
+		return [][]int{identity}
+	case GroupT:
+		perms := make([][]int, size)
+		for r := range perms {
+			perms[r] = make([]int, size)
+			for k := range identity {
+				perms[r][k] = (k + r) % size
+			}
+		}
+		return perms
+	}
+	var perms [][]int
+	var permute func(k int)
+	permute = func(k int) {
+		if k == size {
+			perms = append(perms, append([]int(nil), identity...))
+			return
+		}
+		for j := k; j < size; j++ {
+			identity[k], identity[j] = identity[j], identity[k]
+			permute(k + 1)
+			identity[k], identity[j] = identity[j], identity[k]
+		}
+	}
+	permute(0)
+	return perms
+}
+
+// forEachElement calls visit on every reduced tree pair of the group g over alpha whose trees
+// have exactly leaves leaves, until visit returns false.  Each element is visited at most
+// once.  Returns false if visit stopped early.
+func forEachElement(alpha []rune, leaves int, g GroupKind, visit func(*treePair) bool) bool {
+	// EncodeDFS cannot build the one-leaf trees.
+	if 1 == leaves {
+		return visit(identityOver(alpha))
+	}
+	shapes := treeShapes(len(alpha), leaves)
+	perms := groupPermutations(g, leaves)
+	for _, dom := range shapes {
+		for _, ran := range shapes {
+			for _, perm := range perms {
+				labels := make([]string, len(perm))
+				for k, v := range perm {
+					labels[k] = strconv.Itoa(v)
+				}
+				tp := identityOver(alpha)
+				if !EncodeDFS(tp, "{"+dom+","+ran+","+strings.Join(labels, " ")+"}") {
+					continue
+				}
+				min := clone(tp)
+				min.Minimise()
+				if min.Size() != leaves {
+					continue
+				}
+				if !visit(tp) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnumerate(t *testing.T) {
+
+	t.Run("treeShapes test", func(t *testing.T) {
+		// binary trees are counted by the Catalan numbers.
+		for leaves, want := range []int{0, 1, 1, 2, 5, 14, 42} {
+			assert.Equal(t, want, len(treeShapes(2, leaves)))
+		}
+		assert.Equal(t, []string{"1000"}, treeShapes(3, 3))
+		assert.Equal(t, 0, len(treeShapes(3, 4)))
+		assert.Equal(t, 3, len(treeShapes(3, 5)))
+	})
+
+	// of the pairs of 3-leaf trees, the trivial ones and those swapping the halves reduce.
+	t.Run("forEachElement test", func(t *testing.T) {
+		count := map[GroupKind]int{}
+		for _, g := range []GroupKind{GroupF, GroupT, GroupV} {
+			forEachElement([]rune("01"), 3, g, func(tp *treePair) bool {
+				count[g]++
+				return true
+			})
+		}
+		assert.Equal(t, 2, count[GroupF])
+		assert.Equal(t, 8, count[GroupT])
+		assert.Equal(t, 20, count[GroupV])
+	})
+
+	// up to 3 leaves: the identity, the swap of the halves (in T and V) and the 3-leaf elements.
+	t.Run("Enumerator test", func(t *testing.T) {
+		for g, want := range map[GroupKind]int{GroupF: 3, GroupT: 10, GroupV: 22} {
+			e, err := NewEnumerator("01", 3, g)
+			assert.Nil(t, err)
+			assert.Equal(t, want, e.Count())
+
+			seen := NewTreePairSet()
+			e.Each(func(tp TreePair) bool {
+				assert.True(t, seen.Add(tp))
+				return true
+			})
+			assert.Equal(t, want, seen.Len())
+		}
+
+		e, _ := NewEnumerator("01", 4, GroupV)
+		visited := 0
+		assert.False(t, e.Each(func(tp TreePair) bool {
+			visited++
+			return visited < 5
+		}))
+		assert.Equal(t, 5, visited)
+
+		_, err := NewEnumerator("01", 3, GroupKind(7))
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,49 @@
+package treepair
+
+import (
+	"errors"
+	"math/big"
+)
+
+// FixedInterval is a closed interval [Left, Right] of [0,1] fixed pointwise by an element.
+// An isolated fixed point has Left equal to Right.
+type FixedInterval struct {
+	Left, Right *big.Rat
+}
+
+// IsPoint reports whether the interval is a single point.
+func (fi FixedInterval) IsPoint() bool {
+	return fi.Left.Cmp(fi.Right) == 0
+}
+
+// String writes the interval as "[Left, Right]", or "{x}" for a point.
+func (fi FixedInterval) String() string {
+	if fi.IsPoint() {
+		return "{" + fi.Left.RatString() + "}"
+	}
+	return "[" + fi.Left.RatString() + ", " + fi.Right.RatString() + "]"
+}
+
+// FixedPoints returns, in increasing order, the maximal intervals and the isolated points of
+// [0,1] fixed by the element tp of F, viewed as a piecewise-linear map with the leaves of the
+// trees read as base len(alphabet) intervals.  The ends of fixed intervals are dyadic (for
+// the alphabet "01"), but isolated fixed points need only be rational.  0 and 1 are always
+// fixed.  tp itself is not modified.
+func FixedPoints(tp TreePair) ([]FixedInterval, error) {
+	min := clone(tp)
+	min.Minimise()
+	if !min.InF() {
+		return nil, errors.New("FixedPoints(): element is not in F")
+	}
+
+	spans := newCircleLift(min).fixedSpans()
+	one := big.NewRat(1, 1)
+	if last := spans[len(spans)-1]; last.right.Cmp(one) != 0 {
+		spans = append(spans, fixedSpan{one, one})
+	}
+	fixed := make([]FixedInterval, len(spans))
+	for k, v := range spans {
+		fixed[k] = FixedInterval{Left: v.left, Right: v.right}
+	}
+	return fixed, nil
+}
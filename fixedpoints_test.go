@@ -0,0 +1,50 @@
+package treepair
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixedPoints(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	fixedString := func(t *testing.T, word string) string {
+		t.Helper()
+		tp, err := EvaluateWord("01", word)
+		assert.Nil(t, err)
+		fixed, err := FixedPoints(tp)
+		assert.Nil(t, err)
+		parts := make([]string, len(fixed))
+		for k, v := range fixed {
+			parts[k] = v.String()
+		}
+		return strings.Join(parts, " ")
+	}
+
+	t.Run("FixedPoints test", func(t *testing.T) {
+		assertCorrectMessage(t, fixedString(t, ""), "[0, 1]")
+		assertCorrectMessage(t, fixedString(t, "x0"), "{0} {1}")
+		assertCorrectMessage(t, fixedString(t, "x1"), "[0, 1/2] {1}")
+		assertCorrectMessage(t, fixedString(t, "x2^-3"), "[0, 3/4] {1}")
+		assertCorrectMessage(t, fixedString(t, "x0 x1^-1"), "{0} [7/8, 1]")
+	})
+
+	// x0^-1 x1^2 carries 7/12 to 19/24, then to 2/3 and back to 7/12.
+	t.Run("FixedPoints non-dyadic test", func(t *testing.T) {
+		assertCorrectMessage(t, fixedString(t, "x0^-1 x1^2"), "{0} {7/12} {1}")
+	})
+
+	t.Run("FixedPoints outside F test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		_, err := FixedPoints(c)
+		assert.NotNil(t, err)
+	})
+}
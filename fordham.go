@@ -0,0 +1,162 @@
+package treepair
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/loeksnokes/prefcode"
+)
+
+// caretType is one of Fordham's seven caret types in a binary tree.
+type caretType int
+
+const (
+	caretL0 caretType = iota // the first caret in infix order
+	caretLL                  // any other caret on the left side (the root included)
+	caretIR                  // an interior caret with a right child
+	caretI0                  // an interior caret without a right child
+	caretR0                  // the last caret in infix order
+	caretRR                  // a right side caret followed in infix order by a right side caret
+	caretRI                  // a right side caret followed in infix order by an interior caret
+)
+
+// fordhamWeights is Fordham's table of weights of pairs of caret types.  It is symmetric and pairs
+// which cannot occur in a reduced tree pair diagram are left at zero.
+var fordhamWeights = [7][7]int{
+	//       L0 LL IR I0 R0 RR RI
+	caretL0: {0, 0, 0, 0, 0, 0, 0},
+	caretLL: {0, 2, 2, 2, 1, 1, 1},
+	caretIR: {0, 2, 4, 4, 0, 3, 3},
+	caretI0: {0, 2, 4, 2, 0, 1, 3},
+	caretR0: {0, 1, 0, 0, 0, 0, 0},
+	caretRR: {0, 1, 3, 1, 0, 2, 2},
+	caretRI: {0, 1, 3, 3, 0, 2, 2},
+}
+
+// WordLengthF returns the word length of an element of F with respect to {x0, x1}, computed by
+// Fordham's method: the carets of the minimised domain and range trees are paired off in infix
+// order and the length is the sum of the table weights of the caret types of the pairs.
+func WordLengthF(tp TreePair) (int, error) {
+	if "01" != string(tp.Alphabet()) {
+		return 0, errors.New("WordLengthF(): element is not over the alphabet 01")
+	}
+	min := clone(tp)
+	min.Minimise()
+	if !min.InF() {
+		return 0, errors.New("WordLengthF(): element is not in F")
+	}
+
+	domTypes := caretTypes(min.CodeDomain())
+	ranTypes := caretTypes(min.CodeRange())
+	length := 0
+	for k := range domTypes {
+		length += fordhamWeights[domTypes[k]][ranTypes[k]]
+	}
+	return length, nil
+}
+
+// CompareByLength orders elements by word length, returning -1, 0 or 1, and breaks ties with
+// Compare.  When gens is nil or is {x0, x1} (up to order and inverses) the length of an
+// element of F over "01" is exact, by WordLengthF.  Otherwise it is estimated by the number of
+// carets of the minimised domain tree.  Neither element is modified.
+func CompareByLength(a, b TreePair, gens []TreePair) int {
+	standard := isStandardGeneratingSetF(gens)
+	lengthA, lengthB := lengthEstimate(a, standard), lengthEstimate(b, standard)
+	switch {
+	case lengthA < lengthB:
+		return -1
+	case lengthA > lengthB:
+		return 1
+	}
+	return Compare(a, b)
+}
+
+// lengthEstimate returns the word length of tp in {x0, x1} when exact is set and tp is in F
+// over "01", and otherwise the number of carets of its minimised domain tree.
+func lengthEstimate(tp TreePair, exact bool) int {
+	if exact {
+		if length, err := WordLengthF(tp); nil == err {
+			return length
+		}
+	}
+	min := clone(tp)
+	min.Minimise()
+	return min.Size() - 1
+}
+
+// isStandardGeneratingSetF reports whether gens is nil or consists of x0 and x1, each possibly
+// inverted.
+func isStandardGeneratingSetF(gens []TreePair) bool {
+	if nil == gens {
+		return true
+	}
+	if 2 != len(gens) {
+		return false
+	}
+	found := [2]bool{}
+	for _, g := range gens {
+		for n := 0; n < 2; n++ {
+			x, _ := GeneratorX(n)
+			if EqualsAsElements(g, x) || EqualsAsElements(g, Power(x, -1)) {
+				found[n] = true
+			}
+		}
+	}
+	return found[0] && found[1]
+}
+
+// caretTypes lists Fordham's types of the carets of a binary prefix code in infix order.
+func caretTypes(pc prefcode.PrefCode) []caretType {
+	carets := infixCarets(pc)
+	isCaret := make(map[string]bool, len(carets))
+	for _, v := range carets {
+		isCaret[v] = true
+	}
+	onRightSide := func(s string) bool { return "" == strings.Trim(s, "1") }
+
+	types := make([]caretType, len(carets))
+	for k, v := range carets {
+		switch {
+		case 0 == k:
+			types[k] = caretL0
+		case "" == strings.Trim(v, "0"):
+			types[k] = caretLL
+		case onRightSide(v) && len(carets)-1 == k:
+			types[k] = caretR0
+		case onRightSide(v) && onRightSide(carets[k+1]):
+			types[k] = caretRR
+		case onRightSide(v):
+			types[k] = caretRI
+		case isCaret[v+"1"]:
+			types[k] = caretIR
+		default:
+			types[k] = caretI0
+		}
+	}
+	return types
+}
+
+// infixCarets lists the roots of the carets of a binary prefix code in infix order: left subtree,
+// then the caret, then the right subtree.
+func infixCarets(pc prefcode.PrefCode) []string {
+	seen := make(map[string]bool)
+	var carets []string
+	for leaf := range pc.Code() {
+		if prefcode.EmptyString == leaf {
+			continue
+		}
+		for k := 0; k < len(leaf); k++ {
+			if !seen[leaf[:k]] {
+				seen[leaf[:k]] = true
+				carets = append(carets, leaf[:k])
+			}
+		}
+	}
+	// a caret sits between its left descendants (0 -> a) and its right descendants (1 -> c).
+	infixKey := strings.NewReplacer("0", "a", "1", "c")
+	sort.Slice(carets, func(i, j int) bool {
+		return infixKey.Replace(carets[i])+"b" < infixKey.Replace(carets[j])+"b"
+	})
+	return carets
+}
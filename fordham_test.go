@@ -0,0 +1,88 @@
+package treepair
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFordham(t *testing.T) {
+
+	t.Run("WordLengthF test", func(t *testing.T) {
+		for word, want := range map[string]int{
+			"":                 0,
+			"x0":               1,
+			"x1^-1":            1,
+			"x0 x1 x0^-1":      3,
+			"x0^3":             3,
+			"x1 x0^-2 x1^2 x0": 6,
+		} {
+			g, _ := EvaluateWord("01", word)
+			got, err := WordLengthF(g)
+			assert.Nil(t, err)
+			assert.Equal(t, want, got, "word length of "+word)
+		}
+
+		c, _ := GeneratorC()
+		_, err := WordLengthF(c)
+		assert.NotNil(t, err, "C is not in F.")
+	})
+
+	// The ball of radius 5 is built by breadth first search, so the radius at which an
+	// element first appears is its word length.
+	t.Run("WordLengthF matches breadth first search", func(t *testing.T) {
+		id, _ := EvaluateWord("01", "")
+		seen := map[string]bool{id.FullString(): true}
+		frontier := []TreePair{id}
+		for radius := 1; radius <= 5; radius++ {
+			var next []TreePair
+			for _, g := range frontier {
+				for _, s := range []string{"x0", "x0^-1", "x1", "x1^-1"} {
+					h, _ := EvaluateWord("01", s)
+					p := Multiply(clone(g), h)
+					if seen[p.FullString()] {
+						continue
+					}
+					seen[p.FullString()] = true
+					next = append(next, p)
+					got, _ := WordLengthF(p)
+					assert.Equal(t, radius, got, p.FullString())
+				}
+			}
+			frontier = next
+		}
+		assert.Equal(t, 1+4+12+36+108+314, len(seen))
+	})
+
+	// with {x0, x1} the lengths are exact, and otherwise caret counts, which tie for x0^3 and x2.
+	t.Run("CompareByLength test", func(t *testing.T) {
+		words := []string{"x0^3", "x1^-1", "", "x0 x1 x0^-1", "x0", "x1 x0^-2 x1^2 x0"}
+		elements := map[string]TreePair{}
+		for _, w := range words {
+			elements[w], _ = EvaluateWord("01", w)
+		}
+		x0, _ := GeneratorX(0)
+		x1, _ := GeneratorX(1)
+		c, _ := GeneratorC()
+		for _, v := range []struct {
+			gens []TreePair
+			want []string
+		}{
+			{nil, []string{"", "x0", "x1^-1"}},
+			{[]TreePair{Power(x1, -1), x0}, []string{"", "x0", "x1^-1"}},
+			{[]TreePair{x0, c}, []string{"", "x0", "x1^-1"}},
+		} {
+			sort.SliceStable(words, func(i, j int) bool {
+				return CompareByLength(elements[words[i]], elements[words[j]], v.gens) < 0
+			})
+			assert.Equal(t, v.want, words[:3])
+			assert.Equal(t, "x1 x0^-2 x1^2 x0", words[5])
+		}
+
+		assert.Equal(t, -1, CompareByLength(elements["x0^3"], elements["x1 x0^-2 x1^2 x0"], nil))
+		assert.Equal(t, 1, CompareByLength(elements["x0^3"], elements["x0 x1 x0^-1"], []TreePair{x0, c}))
+		assert.Equal(t, 0, CompareByLength(elements["x0"], Power(x0, 1), nil))
+		assert.Equal(t, -1, CompareByLength(x0, c, nil))
+	})
+}
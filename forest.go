@@ -0,0 +1,232 @@
+package treepair
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/loeksnokes/prefcode"
+)
+
+/*
+ForestPair is an element of the Higman-Thompson group G(n,r): a prefix replacement map between
+two forests with r roots over an alphabet of size n, carrying the leaves of the domain forest
+to the leaves of the range forest.  A leaf is written "k:w" for the word w below the root k,
+with 0 <= k < r and prefcode.EmptyString (or nothing) for w = the root itself.  A TreePair is a
+ForestPair with one root.
+
+Can:
+ 1. Return its alphabet, number of roots and leaf pairs, and the image of a word.
+ 2. Minimise, by reducing carets carried onto carets.
+ 3. Invert.
+ 4. Multiply with another forest pair with as many roots (see MultiplyForest).
+ 5. Convert to and from a TreePair when there is one root.
+*/
+type ForestPair interface {
+	Alphabet() []rune
+	Roots() int
+	Size() int
+	Image(w string) (string, error)
+	Invert()
+	IsIdentity() bool
+	Minimise()
+	FullString() string
+	ToTreePair() (TreePair, error)
+}
+
+// forestPair keeps the leaves as words of a partial tree pair, each led by a rune standing
+// for its root, so the roots are never reduced into one another.
+type forestPair struct {
+	roots   int
+	partial *partialTreePair
+}
+
+// forestRootRune stands for the root k at the head of the internal words of a forestPair.
+func forestRootRune(k int) rune {
+	return rune(0xE000 + k)
+}
+
+// NewForestPair returns the forest pair over the alphabet alphaStr with roots roots carrying
+// the leaf domain[k] to the leaf ran[k], for each k.  Each list must be the leaves of a forest
+// with the given number of roots, written as "k:w".
+func NewForestPair(alphaStr string, roots int, domain, ran []string) (*forestPair, error) {
+	if roots < 1 {
+		return nil, errors.New("NewForestPair(): there must be at least one root")
+	}
+	if len(domain) != len(ran) {
+		return nil, errors.New("NewForestPair(): domain and range have different sizes")
+	}
+	alpha := prefcode.StringToRuneSlice(alphaStr)
+	encode := func(words []string) ([]string, error) {
+		encoded := make([]string, len(words))
+		for k, v := range words {
+			root, w, found := strings.Cut(v, ":")
+			r, err := strconv.Atoi(root)
+			if !found || nil != err || r < 0 || r >= roots {
+				return nil, errors.New("NewForestPair(): " + v + " is not a leaf k:w of a forest with " + strconv.Itoa(roots) + " roots")
+			}
+			for _, c := range rootAsEmpty(w) {
+				if !strings.ContainsRune(alphaStr, c) {
+					return nil, errors.New("NewForestPair(): letter " + string(c) + " of " + v + " is not in the alphabet " + alphaStr)
+				}
+			}
+			encoded[k] = string(forestRootRune(r)) + rootAsEmpty(w)
+		}
+		if !coversForest(alpha, roots, encoded) {
+			return nil, errors.New("NewForestPair(): " + strings.Join(words, " ") + " are not the leaves of a forest with " + strconv.Itoa(roots) + " roots")
+		}
+		return encoded, nil
+	}
+	encodedDomain, err := encode(domain)
+	if nil != err {
+		return nil, err
+	}
+	encodedRange, err := encode(ran)
+	if nil != err {
+		return nil, err
+	}
+	pairs := make(leafMap, len(domain))
+	for k := range encodedDomain {
+		pairs[encodedDomain[k]] = encodedRange[k]
+	}
+	partial := &partialTreePair{alphabet: alpha, pairs: pairs}
+	if len(pairs) != len(domain) || !isAntichain(sortedKeys(pairs)) || !isAntichain(partial.rangeWords()) {
+		return nil, errors.New("NewForestPair(): domain or range has a repeated leaf")
+	}
+	return &forestPair{roots: roots, partial: partial}, nil
+}
+
+// coversForest reports whether the encoded words are the leaves of a forest with roots roots:
+// below each root the cones must have measures adding up to 1.
+func coversForest(alpha []rune, roots int, words []string) bool {
+	byRoot := make([][]string, roots)
+	for _, v := range words {
+		k := int([]rune(v)[0] - forestRootRune(0))
+		byRoot[k] = append(byRoot[k], strings.TrimPrefix(v, string(forestRootRune(k))))
+	}
+	for _, v := range byRoot {
+		if !coversCantorSet(alpha, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// ForestFromTreePair returns tp as a forest pair with one root.  tp itself is not modified.
+func ForestFromTreePair(tp TreePair) *forestPair {
+	partial := PartialFromTreePair(tp)
+	encoded := make(leafMap, len(partial.pairs))
+	for k, v := range partial.pairs {
+		encoded[string(forestRootRune(0))+k] = string(forestRootRune(0)) + v
+	}
+	partial.pairs = encoded
+	return &forestPair{roots: 1, partial: partial}
+}
+
+// decodeForestWord writes the internal word of a forestPair as "k:w".
+func decodeForestWord(v string) string {
+	runes := []rune(v)
+	return strconv.Itoa(int(runes[0]-forestRootRune(0))) + ":" + emptyAsRoot(string(runes[1:]))
+}
+
+// Alphabet returns a copy of the alphabet of fp.
+func (fp *forestPair) Alphabet() []rune {
+	return fp.partial.Alphabet()
+}
+
+// Roots returns the number of roots of the forests of fp.
+func (fp *forestPair) Roots() int {
+	return fp.roots
+}
+
+// Size returns the number of leaves of the domain forest.
+func (fp *forestPair) Size() int {
+	return fp.partial.Size()
+}
+
+// Image returns the image "j:v" of the finite word "k:w", which must lie below a domain leaf.
+func (fp *forestPair) Image(w string) (string, error) {
+	root, word, found := strings.Cut(w, ":")
+	k, err := strconv.Atoi(root)
+	if !found || nil != err || k < 0 || k >= fp.roots {
+		return "", errors.New("ForestPair.Image(): " + w + " is not a word k:w below a root")
+	}
+	image, err := fp.partial.Image(string(forestRootRune(k)) + rootAsEmpty(word))
+	if nil != err {
+		return "", errors.New("ForestPair.Image(): " + w + " lies above the leaves of the domain forest")
+	}
+	return decodeForestWord(image), nil
+}
+
+// Invert replaces fp by its inverse.
+func (fp *forestPair) Invert() {
+	fp.partial.Invert()
+}
+
+// Minimise reduces carets of the domain forest carried onto carets of the range forest, in the
+// same order, until there are none left.
+func (fp *forestPair) Minimise() {
+	fp.partial.Minimise()
+}
+
+// IsIdentity assesses if fp is the identity: a minimised copy carries each root to itself.
+func (fp *forestPair) IsIdentity() bool {
+	min := fp.clone()
+	min.Minimise()
+	for k, v := range min.partial.pairs {
+		if k != v || 1 != len([]rune(k)) {
+			return false
+		}
+	}
+	return true
+}
+
+// clone returns a deep copy of fp.
+func (fp *forestPair) clone() *forestPair {
+	pairs := make(leafMap, len(fp.partial.pairs))
+	for k, v := range fp.partial.pairs {
+		pairs[k] = v
+	}
+	return &forestPair{roots: fp.roots, partial: &partialTreePair{alphabet: fp.partial.Alphabet(), pairs: pairs}}
+}
+
+// FullString returns the leaf pairs of fp in order of the domain leaves, e.g.
+// "[0:0 1:𝛆], [0:1 0:𝛆], [1:𝛆 0:1]".
+func (fp *forestPair) FullString() string {
+	parts := make([]string, 0, fp.Size())
+	for _, d := range sortedKeys(fp.partial.pairs) {
+		parts = append(parts, "["+decodeForestWord(d)+" "+decodeForestWord(fp.partial.pairs[d])+"]")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ToTreePair returns fp as a tree pair, if it has one root.
+func (fp *forestPair) ToTreePair() (TreePair, error) {
+	if 1 != fp.roots {
+		return nil, errors.New("ForestPair.ToTreePair(): forest pair has " + strconv.Itoa(fp.roots) + " roots")
+	}
+	pairs := make(leafMap, fp.Size())
+	for k, v := range fp.partial.pairs {
+		pairs[string([]rune(k)[1:])] = string([]rune(v)[1:])
+	}
+	return pairs.treePair(fp.partial.Alphabet()), nil
+}
+
+// MultiplyForest returns the forest pair applying first and then second, following the
+// convention of Multiply.  The two must have the same alphabet and number of roots.  The
+// result is minimised, and first and second are not modified.
+func MultiplyForest(first, second ForestPair) (*forestPair, error) {
+	if first.Roots() != second.Roots() {
+		return nil, errors.New("MultiplyForest(): forest pairs have different numbers of roots")
+	}
+	a, okA := first.(*forestPair)
+	b, okB := second.(*forestPair)
+	if !okA || !okB {
+		return nil, errors.New("MultiplyForest(): unsupported ForestPair implementation")
+	}
+	product, err := ComposePartial(a.partial, b.partial)
+	if nil != err {
+		return nil, errors.New("MultiplyForest(): " + err.Error())
+	}
+	return &forestPair{roots: a.roots, partial: product}, nil
+}
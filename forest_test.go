@@ -0,0 +1,88 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForestPair(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	t.Run("NewForestPair test", func(t *testing.T) {
+		// the cyclic shift of the two roots, with the first one split.
+		fp, err := NewForestPair("01", 2, []string{"0:0", "0:1", "1:"}, []string{"1:𝛆", "0:0", "0:1"})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, fp.Roots())
+		assert.Equal(t, 3, fp.Size())
+		assertCorrectMessage(t, fp.FullString(), "[0:0 1:𝛆], [0:1 0:0], [1:𝛆 0:1]")
+
+		image, err := fp.Image("0:110")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, image, "0:010")
+		image, err = fp.Image("1:𝛆")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, image, "0:1")
+		_, err = fp.Image("0:")
+		assert.NotNil(t, err)
+		_, err = fp.Image("2:0")
+		assert.NotNil(t, err)
+
+		for _, leaves := range [][2][]string{
+			{{"0:0", "0:1"}, {"0:", "1:"}},
+			{{"0:0", "0:1", "2:"}, {"0:", "1:0", "1:1"}},
+			{{"0:0", "0:1", "1:2"}, {"0:", "1:0", "1:1"}},
+			{{"0:0", "0:0", "1:"}, {"0:", "1:0", "1:1"}},
+			{{"0:0", "0:1", "1"}, {"0:", "1:0", "1:1"}},
+		} {
+			_, err = NewForestPair("01", 2, leaves[0], leaves[1])
+			assert.NotNil(t, err)
+		}
+	})
+
+	t.Run("MultiplyForest test", func(t *testing.T) {
+		fp, _ := NewForestPair("01", 2, []string{"0:0", "0:1", "1:"}, []string{"1:", "0:0", "0:1"})
+		assert.False(t, fp.IsIdentity())
+		power := fp.clone()
+		for k := 2; k <= 3; k++ {
+			var err error
+			power, err = MultiplyForest(power, fp)
+			assert.Nil(t, err)
+		}
+		assert.True(t, power.IsIdentity())
+
+		inverse := fp.clone()
+		inverse.Invert()
+		product, err := MultiplyForest(fp, inverse)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, product.FullString(), "[0:𝛆 0:𝛆], [1:𝛆 1:𝛆]")
+
+		one, _ := NewForestPair("01", 1, []string{"0:"}, []string{"0:"})
+		_, err = MultiplyForest(fp, one)
+		assert.NotNil(t, err)
+		_, err = one.ToTreePair()
+		assert.Nil(t, err)
+		_, err = fp.ToTreePair()
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ForestPair agrees with TreePair test", func(t *testing.T) {
+		for _, words := range [][2]string{{"x0", "x1"}, {"C", "A pi0"}, {"B C x2^2", "C^-1 x0"}} {
+			a, _ := EvaluateWord("01", words[0])
+			b, _ := EvaluateWord("01", words[1])
+			ab, err := MultiplyForest(ForestFromTreePair(a), ForestFromTreePair(b))
+			assert.Nil(t, err)
+			assert.Equal(t, 1, ab.Roots())
+			tp, err := ab.ToTreePair()
+			assert.Nil(t, err)
+			assert.True(t, EqualsAsElements(tp, Multiply(clone(a), clone(b))), words[0]+" "+words[1])
+			assert.Equal(t, 1, tp.Roots())
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package treepair
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// address is the cone at word below the root numbered root of a forest, a disjoint union of
+// copies of Cantor space, writing the root itself as "".
+type address struct {
+	root int
+	word string
+}
+
+// forestMap records a homeomorphism between two forests which replaces prefixes, as leafMap
+// does for a single tree: the cones of its domain, a complete prefix code below each root, are
+// each carried to the cone of the range they map to.  The roots of the domain and of the range
+// need not be numbered alike, nor be as many.
+type forestMap map[address]address
+
+// compose returns the map f followed by g, as Multiply does for tree pairs.  Each cone of f
+// whose image lies inside a cone of g is followed into the image of that cone, and each cone
+// of f whose image is split by g is split with it.  Neither f nor g is modified.
+func (f forestMap) compose(g forestMap) forestMap {
+	byRoot := make(map[int]map[string]address)
+	for d, r := range g {
+		if nil == byRoot[d.root] {
+			byRoot[d.root] = make(map[string]address)
+		}
+		byRoot[d.root][d.word] = r
+	}
+
+	h := make(forestMap, len(f))
+	for d, r := range f {
+		cones := byRoot[r.root]
+		inside := false
+		for k := 0; k <= len(r.word) && !inside; k++ {
+			if k < len(r.word) && !utf8.RuneStart(r.word[k]) {
+				continue
+			}
+			if image, ok := cones[r.word[:k]]; ok {
+				h[d] = address{image.root, image.word + r.word[k:]}
+				inside = true
+			}
+		}
+		if inside {
+			continue
+		}
+		for w, image := range cones {
+			if strings.HasPrefix(w, r.word) {
+				h[address{d.root, d.word + w[len(r.word):]}] = image
+			}
+		}
+	}
+	return h
+}
+
+// inverse returns the inverse of f.  f itself is not modified.
+func (f forestMap) inverse() forestMap {
+	g := make(forestMap, len(f))
+	for d, r := range f {
+		g[r] = d
+	}
+	return g
+}
+
+// minimise reduces f over the alphabet alpha, replacing the len(alpha) cones below a domain
+// word which are carried in order to the cones below a single range word by that one pair,
+// until there are none left.  As leafMap.minimise does, it keeps a worklist of the carets to
+// check and puts back only the caret above each reduction made.
+func (f forestMap) minimise(alpha []rune) {
+	first := string(alpha[0])
+	var pending []address
+	for d := range f {
+		if strings.HasSuffix(d.word, first) {
+			pending = append(pending, address{d.root, strings.TrimSuffix(d.word, first)})
+		}
+	}
+	for 0 != len(pending) {
+		top := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		r, ok := f[address{top.root, top.word + first}]
+		if !ok || !strings.HasSuffix(r.word, first) {
+			continue
+		}
+		image := address{r.root, strings.TrimSuffix(r.word, first)}
+		family := true
+		for _, c := range alpha[1:] {
+			if v, ok := f[address{top.root, top.word + string(c)}]; !ok || v != (address{image.root, image.word + string(c)}) {
+				family = false
+				break
+			}
+		}
+		if !family {
+			continue
+		}
+		for _, c := range alpha {
+			delete(f, address{top.root, top.word + string(c)})
+		}
+		f[top] = image
+		if "" != top.word {
+			pending = append(pending, address{top.root, top.word[:len(top.word)-utf8.RuneLen(lastRune(top.word))]})
+		}
+	}
+}
+
+// treePair returns the minimised tree pair over alpha of the map f from one tree to one tree.
+func (f forestMap) treePair(alpha []rune) *treePair {
+	m := make(leafMap, len(f))
+	for d, r := range f {
+		m[d.word] = r.word
+	}
+	tp := m.treePair(alpha)
+	tp.Minimise()
+	return tp
+}
@@ -0,0 +1,42 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForestMap(t *testing.T) {
+
+	alpha := []rune("01")
+
+	// x0 on one tree, and the map carrying the tree onto a forest of two trees by its halves.
+	x0 := forestMap{{0, "0"}: {0, "00"}, {0, "10"}: {0, "01"}, {0, "11"}: {0, "1"}}
+	halves := forestMap{{0, "0"}: {1, ""}, {0, "1"}: {2, ""}}
+
+	t.Run("forestMap compose test", func(t *testing.T) {
+		got := x0.compose(halves)
+		assert.Equal(t, forestMap{{0, "0"}: {1, "0"}, {0, "10"}: {1, "1"}, {0, "11"}: {2, ""}}, got)
+
+		got = halves.inverse().compose(x0)
+		assert.Equal(t, forestMap{{1, ""}: {0, "00"}, {2, "0"}: {0, "01"}, {2, "1"}: {0, "1"}}, got)
+
+		x0Squared, _ := EvaluateWord("01", "x0^2")
+		assert.True(t, EqualsAsElements(x0.compose(x0).treePair(alpha), x0Squared))
+	})
+
+	t.Run("forestMap minimise test", func(t *testing.T) {
+		got := x0.compose(x0.inverse())
+		assert.Equal(t, 3, len(got))
+		got.minimise(alpha)
+		assert.Equal(t, forestMap{{0, ""}: {0, ""}}, got)
+
+		got = halves.compose(halves.inverse())
+		got.minimise(alpha)
+		assert.Equal(t, forestMap{{0, ""}: {0, ""}}, got)
+
+		got = halves.inverse().compose(halves)
+		got.minimise(alpha)
+		assert.Equal(t, forestMap{{1, ""}: {1, ""}, {2, ""}: {2, ""}}, got)
+	})
+}
@@ -0,0 +1,52 @@
+package treepair
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/loeksnokes/prefcode"
+)
+
+// Format implements fmt.Formatter.  %v and %s write tp in DFS notation, %+v writes the full
+// prefix map as FullString does, and %#v writes a Go expression rebuilding tp through
+// MustParseElement.  A width, as in %+4v, lists at most that many leaves of each tree, ending
+// the list with "..." if any are left out; the DFS notation and the Go expression are written
+// whole whatever the width.  tp itself is not modified.
+func (tp treePair) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v', 's':
+		switch {
+		case f.Flag('#') && 'v' == verb:
+			fmt.Fprintf(f, "treepair.MustParseElement(%s, %s)", strconv.Quote(string(tp.alphabet)), strconv.Quote(tp.FullString()))
+		case f.Flag('+'):
+			width, ok := f.Width()
+			if !ok {
+				width = -1
+			}
+			fmt.Fprint(f, "{D: "+codeListing(tp.CodeDomain(), width)+" || R: "+codeListing(tp.CodeRange(), width)+"}")
+		default:
+			fmt.Fprint(f, tp.DFSString())
+		}
+	default:
+		fmt.Fprintf(f, "%%!%c(treePair=%s)", verb, tp.DFSString())
+	}
+}
+
+// codeListing writes the leaves of pc with their labels in dictionary order, as pc.String()
+// does, but only the first limit of them when limit is not negative.
+func codeListing(pc prefcode.PrefCode, limit int) string {
+	leaves := sortedLeaves(pc)
+	truncated := limit >= 0 && limit < len(leaves)
+	if truncated {
+		leaves = leaves[:limit]
+	}
+	items := make([]string, 0, len(leaves)+1)
+	for _, w := range leaves {
+		items = append(items, "["+w+" "+strconv.Itoa(pc.LabelAtLeaf(w))+"]")
+	}
+	if truncated {
+		items = append(items, "...")
+	}
+	return strings.Join(items, ", ")
+}
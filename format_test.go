@@ -0,0 +1,36 @@
+package treepair
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	x0, _ := GeneratorX(0)
+
+	t.Run("Format verbs test", func(t *testing.T) {
+		assert.Equal(t, x0.DFSString(), fmt.Sprintf("%v", x0))
+		assert.Equal(t, x0.DFSString(), fmt.Sprintf("%s", x0))
+		assert.Equal(t, x0.FullString(), fmt.Sprintf("%+v", x0))
+		assert.Equal(t, "%!d(treePair="+x0.DFSString()+")", fmt.Sprintf("%d", x0))
+	})
+
+	t.Run("Format width test", func(t *testing.T) {
+		assert.Equal(t, "{D: [0 0], [10 1], ... || R: [00 0], [01 1], ...}", fmt.Sprintf("%+2v", x0))
+		assert.Equal(t, x0.FullString(), fmt.Sprintf("%+3v", x0))
+		assert.Equal(t, "{D: [0 0], ... || R: [00 0], ...}", fmt.Sprintf("%+1v", x0))
+		assert.Equal(t, x0.DFSString(), fmt.Sprintf("%2v", x0))
+	})
+
+	t.Run("Format Go syntax test", func(t *testing.T) {
+		got := fmt.Sprintf("%#v", x0)
+		assert.Equal(t, "treepair.MustParseElement(\"01\", \""+x0.FullString()+"\")", got)
+		for _, tp := range []TreePair{x0, identityOver([]rune("01"))} {
+			back := MustParseElement(string(tp.Alphabet()), tp.FullString())
+			assert.Equal(t, tp.FullString(), back.FullString())
+		}
+		assert.Panics(t, func() { MustParseElement("01", "{D: [0 0] || R: [1 0]}") })
+	})
+}
@@ -0,0 +1,74 @@
+package treepair
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// GeneratorX returns the standard generator x_n of R. Thompson's group F as a tree pair
+// over the binary alphabet "01".  The domain tree is the right vine with n+2 carets and the
+// range tree agrees with it except at 1^n, where the caret hangs to the left, so that
+//
+//	1^n0 -> 1^n00,  1^n10 -> 1^n01,  1^n11 -> 1^n1
+//
+// and every other leaf is fixed.  x_0 is "{10100,11000,0 1 2}".  As Multiply composes left
+// to right, these satisfy x_k x_n x_k^-1 = x_(n+1) for k < n.
+func GeneratorX(n int) (*treePair, error) {
+	if n < 0 {
+		return nil, errors.New("GeneratorX(): index " + strconv.Itoa(n) + " is negative")
+	}
+
+	domDFS := strings.Repeat("10", n+2) + "0"
+	ranDFS := strings.Repeat("10", n) + "11000"
+	perm := make([]string, n+3)
+	for k := range perm {
+		perm[k] = strconv.Itoa(k)
+	}
+
+	return binaryFromDFS("{" + domDFS + "," + ranDFS + "," + strings.Join(perm, " ") + "}")
+}
+
+// GeneratorA returns the generator A of R. Thompson's group T in the notation of Cannon, Floyd
+// and Parry.  A agrees with x_0: "{10100,11000,0 1 2}".
+func GeneratorA() (*treePair, error) {
+	return GeneratorX(0)
+}
+
+// GeneratorB returns the generator B of R. Thompson's group T in the notation of Cannon, Floyd
+// and Parry.  B agrees with x_1: "{1010100,1011000,0 1 2 3}".
+func GeneratorB() (*treePair, error) {
+	return GeneratorX(1)
+}
+
+// GeneratorC returns the generator C of R. Thompson's group T in the notation of Cannon, Floyd
+// and Parry: the rotation of order three
+//
+//	0 -> 11,  10 -> 0,  11 -> 10
+//
+// given by "{10100,10100,1 2 0}".
+func GeneratorC() (*treePair, error) {
+	return binaryFromDFS("{10100,10100,1 2 0}")
+}
+
+// GeneratorPi0 returns the generator pi_0 of R. Thompson's group V in the notation of Cannon,
+// Floyd and Parry: the transposition
+//
+//	0 -> 0,  10 -> 11,  11 -> 10
+//
+// given by "{10100,10100,0 2 1}".  Together with A, B and C it generates V.
+func GeneratorPi0() (*treePair, error) {
+	return binaryFromDFS("{10100,10100,0 2 1}")
+}
+
+// binaryFromDFS builds a tree pair over the alphabet "01" from a DFS string.
+func binaryFromDFS(DFS string) (*treePair, error) {
+	tp, err := NewTreePairAlpha("01")
+	if nil != err {
+		return nil, err
+	}
+	if !EncodeDFS(tp, DFS) {
+		return nil, errors.New("binaryFromDFS(): failed to encode " + DFS)
+	}
+	return tp, nil
+}
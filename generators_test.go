@@ -0,0 +1,70 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerators(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	t.Run("GeneratorX test", func(t *testing.T) {
+		x0, err := GeneratorX(0)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, x0.FullString(), "{D: [0 0], [10 1], [11 2] || R: [00 0], [01 1], [1 2]}")
+
+		x2, err := GeneratorX(2)
+		assert.Nil(t, err)
+		got := x2.FullString()
+		want := "{D: [0 0], [10 1], [110 2], [1110 3], [1111 4] || R: [0 0], [10 1], [1100 2], [1101 3], [111 4]}"
+		assertCorrectMessage(t, got, want)
+		assert.True(t, x2.InF(), "x2 should be in F.")
+
+		_, err = GeneratorX(-1)
+		assert.NotNil(t, err, "GeneratorX(-1) should fail.")
+	})
+
+	// Multiply composes left to right, so x_k x_n x_k^-1 = x_(n+1) for k < n.
+	t.Run("GeneratorX relation test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		x1, _ := GeneratorX(1)
+		x2, _ := GeneratorX(2)
+		got := Multiply(Multiply(x0, x1), Power(x0, -1)).FullString()
+		assertCorrectMessage(t, got, x2.FullString())
+	})
+
+	t.Run("T generators test", func(t *testing.T) {
+		a, _ := GeneratorA()
+		b, _ := GeneratorB()
+		c, err := GeneratorC()
+		assert.Nil(t, err)
+		x0, _ := GeneratorX(0)
+		x1, _ := GeneratorX(1)
+		assertCorrectMessage(t, a.FullString(), x0.FullString())
+		assertCorrectMessage(t, b.FullString(), x1.FullString())
+		assertCorrectMessage(t, c.FullString(), "{D: [0 0], [10 1], [11 2] || R: [0 1], [10 2], [11 0]}")
+		assert.True(t, c.InT(), "C should be in T.")
+		assert.False(t, c.InF(), "C should not be in F.")
+
+		// C^3 = 1, and CFP's C = B A^-1 C B reads C = B C A^-1 B left to right.
+		assertCorrectMessage(t, Power(c, 3).FullString(), "{D: [𝛆 0] || R: [𝛆 0]}")
+		got := Multiply(Multiply(Multiply(b, c), Power(a, -1)), b).FullString()
+		assertCorrectMessage(t, got, c.FullString())
+	})
+
+	t.Run("V generators test", func(t *testing.T) {
+		p, err := GeneratorPi0()
+		assert.Nil(t, err)
+		assertCorrectMessage(t, p.FullString(), "{D: [0 0], [10 1], [11 2] || R: [0 0], [10 2], [11 1]}")
+		assert.True(t, p.InV(), "pi0 should be in V.")
+		assert.False(t, p.InT(), "pi0 should not be in T.")
+		assertCorrectMessage(t, Power(p, 2).FullString(), "{D: [𝛆 0] || R: [𝛆 0]}")
+	})
+}
@@ -0,0 +1,58 @@
+package treepair
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+// Germ describes an element near the cone at an address: on that cone it is the prefix
+// substitution From s -> To s.
+type Germ struct {
+	// Address is the address given, and Image its image.
+	Address, Image string
+	// From is the domain leaf of the minimised pair above Address, and To the range leaf it
+	// is carried to.
+	From, To string
+	// DepthChange is the length of To less the length of From, in letters.
+	DepthChange int
+	alphaSize   int
+}
+
+// Slope returns the slope of the element on the cone as a map of the unit interval, the
+// alphabet size to the power -DepthChange.
+func (g Germ) Slope() *big.Rat {
+	if g.DepthChange > 0 {
+		power := new(big.Int).Exp(big.NewInt(int64(g.alphaSize)), big.NewInt(int64(g.DepthChange)), nil)
+		return new(big.Rat).SetFrac(big.NewInt(1), power)
+	}
+	power := new(big.Int).Exp(big.NewInt(int64(g.alphaSize)), big.NewInt(int64(-g.DepthChange)), nil)
+	return new(big.Rat).SetInt(power)
+}
+
+// GermAt returns the germ of tp at the cone of addr.  Addresses above the leaves of the
+// minimised domain tree lie across several substitutions and give an error, as in
+// ImageOfWord.  The root may be given as "" or prefcode.EmptyString, and is written as
+// prefcode.EmptyString.  tp itself is not modified.
+func GermAt(tp TreePair, addr string) (Germ, error) {
+	image, err := ImageOfWord(tp, addr)
+	if nil != err {
+		return Germ{}, errors.New("GermAt(): " + strings.TrimPrefix(err.Error(), "ImageOfWord(): "))
+	}
+	addr = rootAsEmpty(addr)
+	min := canonicalCopy(tp)
+	for d, r := range newLeafMap(min) {
+		if strings.HasPrefix(addr, d) {
+			return Germ{
+				Address:     emptyAsRoot(addr),
+				Image:       image,
+				From:        emptyAsRoot(d),
+				To:          emptyAsRoot(r),
+				DepthChange: utf8.RuneCountInString(r) - utf8.RuneCountInString(d),
+				alphaSize:   len(min.Alphabet()),
+			}, nil
+		}
+	}
+	return Germ{}, errors.New("GermAt(): " + emptyAsRoot(addr) + " lies above the leaves of the domain tree")
+}
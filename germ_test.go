@@ -0,0 +1,49 @@
+package treepair
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGermAt(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	// x0 is 0 -> 00, 10 -> 01, 11 -> 1.
+	t.Run("GermAt test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		germ, err := GermAt(x0, "010")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, germ.From+" "+germ.To+" "+germ.Image, "0 00 0010")
+		assert.Equal(t, 1, germ.DepthChange)
+		assert.Equal(t, 0, germ.Slope().Cmp(big.NewRat(1, 2)))
+
+		germ, _ = GermAt(x0, "11")
+		assertCorrectMessage(t, germ.Image, "1")
+		assert.Equal(t, -1, germ.DepthChange)
+		assert.Equal(t, 0, germ.Slope().Cmp(big.NewRat(2, 1)))
+
+		germ, _ = GermAt(x0, "10")
+		assert.Equal(t, 0, germ.Slope().Cmp(big.NewRat(1, 1)))
+
+		_, err = GermAt(x0, "1")
+		assert.NotNil(t, err)
+		_, err = GermAt(x0, "12")
+		assert.NotNil(t, err)
+
+		ternary, _ := ParseElement("012", "{1100000,1001000,0 1 2 3 4}")
+		germ, _ = GermAt(ternary, "001")
+		assertCorrectMessage(t, germ.From+" "+germ.To, "00 0")
+		assert.Equal(t, 0, germ.Slope().Cmp(big.NewRat(3, 1)))
+		germ, _ = GermAt(ternary, "2")
+		assertCorrectMessage(t, germ.Image, "22")
+		assert.Equal(t, 0, germ.Slope().Cmp(big.NewRat(1, 3)))
+	})
+}
@@ -0,0 +1,72 @@
+package treepair
+
+// history keeps the states of an element before each change made to it, for Undo, and the
+// states undone, for Redo.
+type history struct {
+	undo, redo []*treePair
+}
+
+// record starts a change to tp, returning the function to call once it is made.  If tp keeps
+// a history, the state before the change is saved for Undo, provided the change alters the
+// element or its labels, and the states saved for Redo are dropped.  The history is set aside
+// while the change is made, so changes made along the way, as ReduceRangeAt reduces the
+// domain of the inverse, are part of the one step.
+func record(tp *treePair) func() {
+	h := tp.history
+	if nil == h {
+		return func() {}
+	}
+	before := clone(tp)
+	tp.history = nil
+	return func() {
+		tp.history = h
+		if before.FullString() != tp.FullString() {
+			h.undo = append(h.undo, before)
+			h.redo = nil
+		}
+	}
+}
+
+// SetHistory turns the history of tp on or off.  While it is on, each expansion, reduction,
+// permutation, relabelling, minimisation and inversion of tp is one step that Undo takes back
+// and Redo makes again.  Turning it off forgets the steps saved.  Copies made by clone do not
+// inherit the history, and Undo and Redo are not reported to an Observer.
+func (tp *treePair) SetHistory(on bool) {
+	switch {
+	case !on:
+		tp.history = nil
+	case nil == tp.history:
+		tp.history = &history{}
+	}
+}
+
+// Undo takes back the last step in the history of tp, and reports whether there was one.
+func (tp *treePair) Undo() bool {
+	if nil == tp.history || 0 == len(tp.history.undo) {
+		return false
+	}
+	h := tp.history
+	h.redo = append(h.redo, clone(tp))
+	tp.restore(h.undo[len(h.undo)-1])
+	h.undo = h.undo[:len(h.undo)-1]
+	return true
+}
+
+// Redo makes again the last step taken back by Undo, and reports whether there was one.  Any
+// other change to tp drops the steps Redo could make.
+func (tp *treePair) Redo() bool {
+	if nil == tp.history || 0 == len(tp.history.redo) {
+		return false
+	}
+	h := tp.history
+	h.undo = append(h.undo, clone(tp))
+	tp.restore(h.redo[len(h.redo)-1])
+	h.redo = h.redo[:len(h.redo)-1]
+	return true
+}
+
+// restore makes tp the element saved as state, taking over its codes.
+func (tp *treePair) restore(state *treePair) {
+	tp.codePair = state.codePair
+	tp.changed()
+}
@@ -0,0 +1,65 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+
+	t.Run("Undo and Redo test", func(t *testing.T) {
+		a, _ := GeneratorA()
+		assert.False(t, a.Undo(), "no history is kept.")
+		a.SetHistory(true)
+		states := []string{a.FullString()}
+		a.ExpandDomainAt("11")
+		states = append(states, a.FullString())
+		a.ApplyPermRange(map[int]int{0: 1, 1: 0, 2: 2, 3: 3})
+		states = append(states, a.FullString())
+		assert.False(t, a.ReduceDomainAt("0"), "a failed reduction is no step.")
+		a.Invert()
+		states = append(states, a.FullString())
+
+		for k := len(states) - 2; k >= 0; k-- {
+			assert.True(t, a.Undo())
+			assert.Equal(t, states[k], a.FullString())
+		}
+		assert.False(t, a.Undo())
+		for k := 1; k < len(states); k++ {
+			assert.True(t, a.Redo())
+			assert.Equal(t, states[k], a.FullString())
+		}
+		assert.False(t, a.Redo())
+
+		a.Undo()
+		a.ExpandRangeAt("11")
+		assert.False(t, a.Redo(), "a new step drops the steps undone.")
+		a.Undo()
+		assert.Equal(t, states[2], a.FullString())
+	})
+
+	t.Run("History steps test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		c.SetHistory(true)
+		c.SetAutoMinimise(true)
+		before := c.FullString()
+		c.ExpandDomainAt("0")
+		assert.Equal(t, before, c.FullString())
+		assert.False(t, c.Undo(), "expanding and minimising again changes nothing.")
+
+		c.SetAutoMinimise(false)
+		c.ExpandDomainAt("0")
+		assert.True(t, c.ReduceRangeAt("11"))
+		assert.True(t, c.IsMinimal())
+		assert.True(t, c.Undo(), "reducing the range is one step.")
+		assert.False(t, c.IsMinimal())
+		assert.True(t, c.Undo())
+		assert.Equal(t, before, c.FullString())
+
+		c.SetHistory(false)
+		c.ExpandDomainAt("0")
+		assert.False(t, c.Undo())
+		assert.Nil(t, clone(c).history, "copies do not inherit the history.")
+	})
+}
@@ -0,0 +1,158 @@
+package treepair
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/loeksnokes/prefcode"
+)
+
+// ImageOfWord returns the image of the finite word w under the prefix replacement map of tp:
+// if the minimised domain tree has a leaf d with w = d s then the image is r s, where r is the
+// range leaf matched with d.  Words above the leaves (proper prefixes of domain leaves) have
+// no single image cone and give an error, as do letters outside the alphabet.  The empty word
+// may be given as "" or prefcode.EmptyString, and is returned as prefcode.EmptyString.  tp
+// itself is not modified.
+func ImageOfWord(tp TreePair, w string) (string, error) {
+	if prefcode.EmptyString == w {
+		w = ""
+	}
+	alpha := string(tp.Alphabet())
+	for _, r := range w {
+		if !strings.ContainsRune(alpha, r) {
+			return "", errors.New("ImageOfWord(): letter " + string(r) + " of " + w + " is not in the alphabet " + alpha)
+		}
+	}
+
+	min := clone(tp)
+	min.Minimise()
+	for _, v := range leafPairs(min) {
+		d, r := v[0], v[1]
+		if prefcode.EmptyString == d {
+			d = ""
+		}
+		if prefcode.EmptyString == r {
+			r = ""
+		}
+		if strings.HasPrefix(w, d) {
+			if image := r + strings.TrimPrefix(w, d); "" != image {
+				return image, nil
+			}
+			return prefcode.EmptyString, nil
+		}
+	}
+	return "", errors.New("ImageOfWord(): " + w + " lies above the leaves of the domain tree")
+}
+
+// FixesCone reports whether tp carries the cone at w onto itself pointwise.  Each domain leaf
+// of the minimised pair meeting the cone, above or below w, must then be carried to itself.
+// Letters outside the alphabet give false.  tp itself is not modified.
+func FixesCone(tp TreePair, w string) bool {
+	w = rootAsEmpty(w)
+	alpha := string(tp.Alphabet())
+	for _, r := range w {
+		if !strings.ContainsRune(alpha, r) {
+			return false
+		}
+	}
+	for d, r := range newLeafMap(canonicalCopy(tp)) {
+		if (strings.HasPrefix(w, d) || strings.HasPrefix(d, w)) && d != r {
+			return false
+		}
+	}
+	return true
+}
+
+// OrbitOfWord returns the orbit w, g(w), g²(w), ... of the cone at w under the element g = tp,
+// stopping before the first repeat or after maxSteps images, whichever is sooner.  As g is a
+// bijection the first repeat is the first word itself, and periodic reports whether the orbit
+// closed up.  Each word is carried by the prefix substitution of the minimised pair at the
+// domain leaf above it, so the words grow or shrink as the orbit is attracted or repelled.  A
+// word of the orbit lying above the leaves of the domain tree has no single image cone, so the
+// address is refined: the word is followed down the first letter of the alphabet to the leaf
+// below it, and the letters added are appended to every word of the orbit so far, which the
+// substitutions before it carry along unchanged.  The orbit returned is then that of a cone
+// inside the cone at w, beginning with w followed by all the letters added.  Letters outside
+// the alphabet give an error.  Words are written as by ImageOfWord, and tp itself is not
+// modified.
+func OrbitOfWord(tp TreePair, w string, maxSteps int) (orbit []string, periodic bool, err error) {
+	w = rootAsEmpty(w)
+	alpha := string(tp.Alphabet())
+	for _, r := range w {
+		if !strings.ContainsRune(alpha, r) {
+			return nil, false, errors.New("OrbitOfWord(): letter " + string(r) + " of " + w + " is not in the alphabet " + alpha)
+		}
+	}
+
+	m := newLeafMap(canonicalCopy(tp))
+	first := string(tp.Alphabet()[0])
+	words := []string{w}
+	for u := w; len(words) <= maxSteps; {
+		image, ok := "", false
+		for d, r := range m {
+			if strings.HasPrefix(u, d) {
+				image, ok = r+u[len(d):], true
+				break
+			}
+		}
+		if !ok {
+			refinement := ""
+			for _, leaf := m[u+refinement]; !leaf; _, leaf = m[u+refinement] {
+				refinement += first
+			}
+			for k := range words {
+				words[k] += refinement
+			}
+			u += refinement
+			continue
+		}
+		if image == words[0] {
+			periodic = true
+			break
+		}
+		words = append(words, image)
+		u = image
+	}
+	orbit = make([]string, len(words))
+	for k, v := range words {
+		orbit[k] = emptyAsRoot(v)
+	}
+	return orbit, periodic, nil
+}
+
+// ImagesOfWords returns the images of the words ws under tp, in order, as ImageOfWord does
+// for each.  The words are sorted and matched with the leaves of the minimised domain tree in
+// one pass: the leaf that is a prefix of a word is the greatest leaf not after it in
+// dictionary order, so both move forward together.  The first word giving an error stops the
+// pass.  tp itself is not modified.
+func ImagesOfWords(tp TreePair, ws []string) ([]string, error) {
+	alpha := string(tp.Alphabet())
+	words := make([]string, len(ws))
+	order := make([]int, len(ws))
+	for k, w := range ws {
+		words[k], order[k] = rootAsEmpty(w), k
+		for _, r := range words[k] {
+			if !strings.ContainsRune(alpha, r) {
+				return nil, errors.New("ImagesOfWords(): letter " + string(r) + " of " + w + " is not in the alphabet " + alpha)
+			}
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool { return words[order[i]] < words[order[j]] })
+
+	pairs := leafPairs(canonicalCopy(tp))
+	images := make([]string, len(ws))
+	leaf := 0
+	for _, k := range order {
+		w := words[k]
+		for leaf+1 < len(pairs) && rootAsEmpty(pairs[leaf+1][0]) <= w {
+			leaf++
+		}
+		d, r := rootAsEmpty(pairs[leaf][0]), rootAsEmpty(pairs[leaf][1])
+		if !strings.HasPrefix(w, d) {
+			return nil, errors.New("ImagesOfWords(): " + emptyAsRoot(w) + " lies above the leaves of the domain tree")
+		}
+		images[k] = emptyAsRoot(r + w[len(d):])
+	}
+	return images, nil
+}
@@ -0,0 +1,148 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImage(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	t.Run("ImageOfWord test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		for w, want := range map[string]string{
+			"0":     "00",
+			"0110":  "00110",
+			"10":    "01",
+			"101":   "011",
+			"11":    "1",
+			"11011": "1011",
+		} {
+			got, err := ImageOfWord(x0, w)
+			assert.Nil(t, err)
+			assertCorrectMessage(t, got, want)
+		}
+
+		c, _ := GeneratorC()
+		got, err := ImageOfWord(c, "01")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got, "111")
+	})
+
+	t.Run("ImageOfWord unreduced test", func(t *testing.T) {
+		// the identity on a big tree still maps the root to the root.
+		tp, _ := binaryFromDFS("{1110000,1110000,0 1 2 3}")
+		got, err := ImageOfWord(tp, "")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got, "𝛆")
+		got, err = ImageOfWord(tp, "0")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got, "0")
+	})
+
+	t.Run("ImageOfWord error test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		_, err := ImageOfWord(x0, "1")
+		assert.NotNil(t, err, "1 is above the leaves 10 and 11.")
+		_, err = ImageOfWord(x0, "")
+		assert.NotNil(t, err, "the root is above the leaves.")
+		_, err = ImageOfWord(x0, "012")
+		assert.NotNil(t, err, "2 is not a letter.")
+	})
+
+	// x1 is 0 -> 0, 10 -> 100, 110 -> 101, 111 -> 11.
+	t.Run("FixesCone test", func(t *testing.T) {
+		x1, _ := GeneratorX(1)
+		for w, want := range map[string]bool{"0": true, "01": true, "": false, "𝛆": false, "1": false, "10": false, "2": false} {
+			assert.Equal(t, want, FixesCone(x1, w), w)
+		}
+		c, _ := GeneratorC()
+		sum, _ := DirectSum("10", c, "11", identityOver([]rune("01")))
+		assert.True(t, FixesCone(sum, "0"))
+		assert.True(t, FixesCone(sum, "11"))
+		assert.False(t, FixesCone(sum, "1"))
+		assert.True(t, FixesCone(identityOver([]rune("01")), ""))
+	})
+
+	t.Run("OrbitOfWord test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		orbit, periodic, err := OrbitOfWord(x0, "0", 3)
+		assert.Nil(t, err)
+		assert.False(t, periodic)
+		assert.Equal(t, []string{"0", "00", "000", "0000"}, orbit)
+
+		// 11 -> 1, which lies above the leaves 10 and 11, so 11 is refined to 110.
+		orbit, periodic, err = OrbitOfWord(x0, "11", 3)
+		assert.Nil(t, err)
+		assert.False(t, periodic)
+		assert.Equal(t, []string{"110", "10", "01", "001"}, orbit)
+		for k := 1; k < len(orbit); k++ {
+			image, err := ImageOfWord(x0, orbit[k-1])
+			assert.Nil(t, err)
+			assert.Equal(t, orbit[k], image)
+		}
+
+		// the swap of 0 and 1 carries the root onto itself only as a whole.
+		swap, _ := ParseElement("01", "{100,100,1 0}")
+		orbit, periodic, err = OrbitOfWord(swap, "", 5)
+		assert.Nil(t, err)
+		assert.True(t, periodic)
+		assert.Equal(t, []string{"0", "1"}, orbit)
+
+		c, _ := GeneratorC()
+		orbit, periodic, err = OrbitOfWord(c, "01", 10)
+		assert.Nil(t, err)
+		assert.True(t, periodic)
+		assert.Equal(t, 3, len(orbit))
+		assert.Equal(t, "111", orbit[1])
+
+		orbit, periodic, err = OrbitOfWord(identityOver([]rune("01")), "", 5)
+		assert.Nil(t, err)
+		assert.True(t, periodic)
+		assert.Equal(t, []string{"𝛆"}, orbit)
+
+		_, _, err = OrbitOfWord(x0, "2", 1)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ImagesOfWords test", func(t *testing.T) {
+		var ws []string
+		for length := 4; length <= 6; length++ {
+			for k := 0; k < 1<<length; k += 3 {
+				w := ""
+				for bit := length - 1; bit >= 0; bit-- {
+					w += string("01"[k>>bit&1])
+				}
+				ws = append([]string{w}, ws...)
+			}
+		}
+		ws = append(ws, ws[0])
+		for _, word := range []string{"", "C", "x0 x1^-1 x2", "pi0 A^2"} {
+			tp, _ := EvaluateWord("01", word)
+			images, err := ImagesOfWords(tp, ws)
+			assert.Nil(t, err, word)
+			for k, w := range ws {
+				image, _ := ImageOfWord(tp, w)
+				assert.Equal(t, image, images[k], word+" at "+w)
+			}
+		}
+
+		id := identityOver([]rune("01"))
+		images, err := ImagesOfWords(id, []string{"1", "", "𝛆", "0"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"1", "𝛆", "𝛆", "0"}, images)
+
+		x0, _ := GeneratorX(0)
+		_, err = ImagesOfWords(x0, []string{"0", "1"})
+		assert.NotNil(t, err, "1 lies above the leaves 10 and 11.")
+		_, err = ImagesOfWords(x0, []string{"0", "2"})
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,45 @@
+package treepair
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ElementJSON is the JSON encoding of an element: its alphabet and its DFS string, as in
+// {"alphabet":"01","dfs":"{11000,10100,1 2 0}"}.
+type ElementJSON struct {
+	Alphabet string `json:"alphabet"`
+	DFS      string `json:"dfs"`
+}
+
+// EncodeJSON returns the JSON encoding of tp.
+func EncodeJSON(tp TreePair) ElementJSON {
+	return ElementJSON{Alphabet: string(tp.Alphabet()), DFS: tp.DFSString()}
+}
+
+// TreePair decodes e.
+func (e ElementJSON) TreePair() (TreePair, error) {
+	if "" == e.Alphabet {
+		return nil, errors.New("ElementJSON.TreePair(): no alphabet")
+	}
+	return ParseElement(e.Alphabet, e.DFS)
+}
+
+// MarshalJSON writes tp as its ElementJSON.
+func (tp *treePair) MarshalJSON() ([]byte, error) {
+	return json.Marshal(EncodeJSON(tp))
+}
+
+// UnmarshalJSON reads tp from an ElementJSON.
+func (tp *treePair) UnmarshalJSON(data []byte) error {
+	var e ElementJSON
+	if err := json.Unmarshal(data, &e); nil != err {
+		return err
+	}
+	decoded, err := e.TreePair()
+	if nil != err {
+		return err
+	}
+	*tp = *clone(decoded)
+	return nil
+}
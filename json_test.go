@@ -0,0 +1,41 @@
+package treepair
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSON(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	t.Run("MarshalJSON test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		data, err := json.Marshal(c)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, string(data), `{"alphabet":"01","dfs":"{10100,10100,1 2 0}"}`)
+
+		tp, _ := NewTreePairAlpha("01")
+		assert.Nil(t, json.Unmarshal(data, tp))
+		assert.True(t, EqualsAsElements(tp, c))
+		assert.NotNil(t, json.Unmarshal([]byte(`{"alphabet":"01","dfs":"{1010,10100,1 2 0}"}`), tp))
+		assert.NotNil(t, json.Unmarshal([]byte(`{"dfs":"{10100,10100,1 2 0}"}`), tp))
+		assert.NotNil(t, json.Unmarshal([]byte(`[]`), tp))
+	})
+
+	t.Run("ElementJSON test", func(t *testing.T) {
+		e := EncodeJSON(identityOver([]rune("012")))
+		assert.Equal(t, ElementJSON{Alphabet: "012", DFS: "{0,0,0}"}, e)
+		tp, err := e.TreePair()
+		assert.Nil(t, err)
+		assert.True(t, tp.IsIdentity())
+		assertCorrectMessage(t, string(tp.Alphabet()), "012")
+	})
+}
@@ -0,0 +1,195 @@
+package treepair
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/loeksnokes/prefcode"
+)
+
+// leafMap records a tree pair as the map from each leaf of the domain tree to the leaf of the
+// range tree it is carried to, writing the root as "".
+type leafMap map[string]string
+
+// newLeafMap reads off the leaf map of tp.
+func newLeafMap(tp TreePair) leafMap {
+	m := make(leafMap, tp.Size())
+	m.read(tp)
+	return m
+}
+
+// read adds the pairs of the leaf map of tp to m.
+func (m leafMap) read(tp TreePair) {
+	dom, ran := tp.CodeDomain(), tp.CodeRange()
+	leafOfLabel := labelPool.Get().(map[int]string)
+	for k, v := range ran.Code() {
+		leafOfLabel[v] = k
+	}
+	for k, v := range dom.Code() {
+		m[rootAsEmpty(k)] = rootAsEmpty(leafOfLabel[v])
+	}
+	for k := range leafOfLabel {
+		delete(leafOfLabel, k)
+	}
+	labelPool.Put(leafOfLabel)
+}
+
+// treePair builds the tree pair over alpha with leaf map m.
+func (m leafMap) treePair(alpha []rune) *treePair {
+	if 1 == len(m) {
+		return identityOver(alpha)
+	}
+	domain := sortedKeys(m)
+	label := make(map[string]int, len(m))
+	domSet := make(map[string]bool, len(m))
+	ranSet := make(map[string]bool, len(m))
+	for k, v := range domain {
+		label[m[v]] = k
+		domSet[v] = true
+		ranSet[m[v]] = true
+	}
+	rangeLeaves := make([]string, 0, len(m))
+	for v := range ranSet {
+		rangeLeaves = append(rangeLeaves, v)
+	}
+	sort.Strings(rangeLeaves)
+	perm := make([]string, len(rangeLeaves))
+	for k, v := range rangeLeaves {
+		perm[k] = strconv.Itoa(label[v])
+	}
+
+	tp := identityOver(alpha)
+	if !EncodeDFS(tp, "{"+codeDFS(domSet, alpha, "")+","+codeDFS(ranSet, alpha, "")+","+strings.Join(perm, " ")+"}") {
+		panic("leafMap.treePair(): leaf map does not describe a tree pair")
+	}
+	return tp
+}
+
+// leafPairs lists the prefix replacements of tp as (domain leaf, range leaf) pairs in
+// dictionary order of the domain leaves.
+func leafPairs(tp TreePair) [][2]string {
+	dom := sortedLeaves(tp.CodeDomain())
+	pairs := make([][2]string, len(dom))
+	for k, v := range dom {
+		pairs[k] = [2]string{v, tp.CodeRange().LeafAtLabel(tp.CodeDomain().LabelAtLeaf(v))}
+	}
+	return pairs
+}
+
+// key returns a string determining m.
+func (m leafMap) key() string {
+	leaves := make([]string, 0, len(m))
+	for k, v := range m {
+		leaves = append(leaves, k+">"+v)
+	}
+	sort.Strings(leaves)
+	return strings.Join(leaves, " ")
+}
+
+// expandAt returns the leaf map with the domain leaf a and its image both expanded.
+func (m leafMap) expandAt(alpha []rune, a string) leafMap {
+	expanded := make(leafMap, len(m)+len(alpha)-1)
+	for k, v := range m {
+		expanded[k] = v
+	}
+	image := expanded[a]
+	delete(expanded, a)
+	for _, c := range alpha {
+		expanded[a+string(c)] = image + string(c)
+	}
+	return expanded
+}
+
+// minimise reduces m as in PartialTreePair.Minimise, where caret gives the letters of the
+// caret containing a letter.  It keeps a worklist of carets, each given by its root and one
+// of its letters.  At the start these are the carets whose first leaf is a domain word, and
+// each reduction puts back only the caret just above it, so every caret is checked about once
+// and nothing recurses, however deep the trees.
+func (m leafMap) minimise(caret func(c rune) []rune) {
+	m.minimiseReporting(caret, nil)
+}
+
+// minimiseReporting reduces m as minimise does, calling reduced, unless it is nil, with the
+// roots of the domain caret and the range caret of each reduction as it is made.
+func (m leafMap) minimiseReporting(caret func(c rune) []rune, reduced func(root, image string)) {
+	type caretAt struct {
+		root   string
+		letter rune
+	}
+	var pending []caretAt
+	for d := range m {
+		if "" == d {
+			continue
+		}
+		c := lastRune(d)
+		if caret(c)[0] == c {
+			pending = append(pending, caretAt{d[:len(d)-utf8.RuneLen(c)], c})
+		}
+	}
+	for 0 != len(pending) {
+		top := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		letters := caret(top.letter)
+		first := string(letters[0])
+		r, ok := m[top.root+first]
+		if !ok || !strings.HasSuffix(r, first) {
+			continue
+		}
+		image := strings.TrimSuffix(r, first)
+		family := true
+		for _, c := range letters[1:] {
+			if v, ok := m[top.root+string(c)]; !ok || v != image+string(c) {
+				family = false
+				break
+			}
+		}
+		if !family {
+			continue
+		}
+		for _, c := range letters {
+			delete(m, top.root+string(c))
+		}
+		m[top.root] = image
+		if nil != reduced {
+			reduced(top.root, image)
+		}
+		if "" != top.root {
+			c := lastRune(top.root)
+			pending = append(pending, caretAt{top.root[:len(top.root)-utf8.RuneLen(c)], c})
+		}
+	}
+}
+
+// lastRune returns the last rune of the nonempty word w.
+func lastRune(w string) rune {
+	r, _ := utf8.DecodeLastRuneInString(w)
+	return r
+}
+
+// sortedKeys returns the keys of a string map in dictionary order.
+func sortedKeys(set map[string]string) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// rootAsEmpty writes prefcode.EmptyString as "".
+func rootAsEmpty(w string) string {
+	if prefcode.EmptyString == w {
+		return ""
+	}
+	return w
+}
+
+// emptyAsRoot writes "" as prefcode.EmptyString.
+func emptyAsRoot(w string) string {
+	if "" == w {
+		return prefcode.EmptyString
+	}
+	return w
+}
@@ -0,0 +1,104 @@
+package treepair
+
+import "errors"
+
+// MarkedTreePair is a tree pair with a marked cone, the basepoint, which it carries to its
+// target cone.  The mark is a word rather than a leaf, so it is kept as the trees are
+// expanded and reduced, and a product of marked tree pairs is marked at the mark of its first
+// factor, its target found by following the mark through both: composing marked tree pairs
+// whose targets and marks agree tracks one cone through a groupoid-style argument.
+type MarkedTreePair struct {
+	tp   *treePair
+	mark string
+}
+
+// NewMarkedTreePair returns a copy of tp marked at the cone mark, which must lie at or below a
+// leaf of the minimised domain tree of tp, so that tp carries it to a single cone.  The root
+// may be given as "" or prefcode.EmptyString.  tp itself is not modified.
+func NewMarkedTreePair(tp TreePair, mark string) (*MarkedTreePair, error) {
+	if _, err := ImageOfWord(tp, mark); nil != err {
+		return nil, errors.New("NewMarkedTreePair(): cannot mark " + mark + ": " + err.Error())
+	}
+	return &MarkedTreePair{tp: clone(tp), mark: emptyAsRoot(rootAsEmpty(mark))}, nil
+}
+
+// TreePair returns a copy of the tree pair of mp, without its mark.
+func (mp *MarkedTreePair) TreePair() TreePair {
+	return clone(mp.tp)
+}
+
+// Mark returns the marked cone of mp, the root written as prefcode.EmptyString.
+func (mp *MarkedTreePair) Mark() string {
+	return mp.mark
+}
+
+// Target returns the cone mp carries its mark to, the root written as prefcode.EmptyString.
+func (mp *MarkedTreePair) Target() string {
+	target, _ := ImageOfWord(mp.tp, mp.mark)
+	return target
+}
+
+// MarkedLeaf returns the leaf of the domain tree of mp at or above the mark, and whether
+// there is one: expanding the domain tree below the mark leaves it above the leaves.
+func (mp *MarkedTreePair) MarkedLeaf() (string, bool) {
+	mark := rootAsEmpty(mp.mark)
+	found := ""
+	ok := false
+	mp.tp.Leaves(func(leaf string, label int) bool {
+		if w := rootAsEmpty(leaf); len(w) <= len(mark) && mark[:len(w)] == w {
+			found, ok = leaf, true
+			return false
+		}
+		return true
+	})
+	return found, ok
+}
+
+// ExpandDomainAt expands the trees of mp as TreePair's ExpandDomainAt does, keeping its mark.
+func (mp *MarkedTreePair) ExpandDomainAt(s string) {
+	mp.tp.ExpandDomainAt(s)
+}
+
+// Minimise minimises the tree pair of mp, keeping its mark.
+func (mp *MarkedTreePair) Minimise() {
+	mp.tp.Minimise()
+}
+
+// Invert replaces mp by its inverse, marked at the target of mp, so that its target is the
+// old mark.
+func (mp *MarkedTreePair) Invert() {
+	target := mp.Target()
+	mp.tp.Invert()
+	mp.mark = target
+}
+
+// FixesMark reports whether mp carries its mark to itself, as the elements of the pointed
+// group at the mark do.
+func (mp *MarkedTreePair) FixesMark() bool {
+	return mp.Target() == mp.mark
+}
+
+// MultiplyMarked returns the product of first and second, as Multiply composes, marked at the
+// mark of first; its target is the image under second of the target of first.  Neither first
+// nor second is modified.
+func MultiplyMarked(first, second *MarkedTreePair) (*MarkedTreePair, error) {
+	if string(first.tp.Alphabet()) != string(second.tp.Alphabet()) {
+		return nil, errors.New("MultiplyMarked(): elements are over different alphabets")
+	}
+	return &MarkedTreePair{tp: Multiply(clone(first.tp), clone(second.tp)), mark: first.mark}, nil
+}
+
+// ComposeMarked returns the product of first and second as MultiplyMarked does, but only when
+// second is marked at the target of first, as arrows of a groupoid between cones compose.
+func ComposeMarked(first, second *MarkedTreePair) (*MarkedTreePair, error) {
+	if first.Target() != second.mark {
+		return nil, errors.New("ComposeMarked(): " + second.mark + " is not the target " + first.Target() + " of the first element")
+	}
+	return MultiplyMarked(first, second)
+}
+
+// String returns the Full notation of the tree pair of mp followed by its mark and target,
+// e.g. "{D: [0 0], [10 1], [11 2] || R: [00 0], [01 1], [1 2]} @ 0 -> 00".
+func (mp *MarkedTreePair) String() string {
+	return mp.tp.FullString() + " @ " + mp.mark + " -> " + mp.Target()
+}
@@ -0,0 +1,65 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarked(t *testing.T) {
+
+	t.Run("MarkedTreePair test", func(t *testing.T) {
+		a, _ := GeneratorA()
+		mp, err := NewMarkedTreePair(a, "01")
+		assert.Nil(t, err)
+		assert.Equal(t, "01", mp.Mark())
+		assert.Equal(t, "001", mp.Target())
+		assert.Equal(t, "{D: [0 0], [10 1], [11 2] || R: [00 0], [01 1], [1 2]} @ 01 -> 001", mp.String())
+		leaf, ok := mp.MarkedLeaf()
+		assert.True(t, ok)
+		assert.Equal(t, "0", leaf)
+
+		mp.ExpandDomainAt("011")
+		assert.Equal(t, "001", mp.Target(), "the mark is kept as the trees are expanded.")
+		_, ok = mp.MarkedLeaf()
+		assert.False(t, ok)
+		mp.Minimise()
+		assert.Equal(t, "001", mp.Target(), "and reduced.")
+
+		mp.Invert()
+		assert.Equal(t, "001", mp.Mark())
+		assert.Equal(t, "01", mp.Target())
+		assert.False(t, mp.FixesMark())
+
+		_, err = NewMarkedTreePair(a, "1")
+		assert.NotNil(t, err, "1 lies above the leaves 10 and 11.")
+		id, err := NewMarkedTreePair(identityOver([]rune("01")), "")
+		assert.Nil(t, err)
+		assert.Equal(t, "𝛆", id.Mark())
+		assert.True(t, id.FixesMark())
+	})
+
+	t.Run("MultiplyMarked test", func(t *testing.T) {
+		a, _ := GeneratorA()
+		c, _ := GeneratorC()
+		first, _ := NewMarkedTreePair(a, "10")
+		second, _ := NewMarkedTreePair(c, "0")
+		product, err := MultiplyMarked(first, second)
+		assert.Nil(t, err)
+		assert.Equal(t, "10", product.Mark())
+		image, _ := ImageOfWord(c, first.Target())
+		assert.Equal(t, image, product.Target())
+		assert.True(t, EqualsAsElements(Multiply(clone(a), clone(c)), product.TreePair()))
+
+		_, err = ComposeMarked(first, second)
+		assert.NotNil(t, err, "second is not marked at the target 01 of first.")
+		second, _ = NewMarkedTreePair(c, first.Target())
+		composed, err := ComposeMarked(first, second)
+		assert.Nil(t, err)
+		assert.Equal(t, product.String(), composed.String())
+
+		ternary, _ := NewMarkedTreePair(identityOver([]rune("012")), "0")
+		_, err = MultiplyMarked(first, ternary)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,77 @@
+package treepair
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// NormalForm holds the exponent sequences of the normal form of an element of F.  The element is
+//
+//	x_0^-Negative[0] ... x_n^-Negative[n]  x_n^Positive[n] ... x_0^Positive[0]
+//
+// with Multiply's left to right product, i.e. q^-1 p where p and q are the positive elements
+// with exponents Positive and Negative.  Both slices have the same length and the last index
+// carries a nonzero exponent on at least one side (both are empty for the identity).
+type NormalForm struct {
+	Positive []int
+	Negative []int
+}
+
+// NormalFormF returns the unique normal form of the element of F represented by tp, read from the
+// leaf exponents of the minimised range tree (Positive) and domain tree (Negative).  tp itself is
+// not modified.
+func NormalFormF(tp TreePair) (NormalForm, error) {
+	domExp, ranExp, err := leafExponentsF(tp)
+	if nil != err {
+		return NormalForm{}, err
+	}
+	length := 0
+	for k := range domExp {
+		if 0 != domExp[k] || 0 != ranExp[k] {
+			length = k + 1
+		}
+	}
+	nf := NormalForm{Positive: ranExp[:length], Negative: domExp[:length]}
+	if !nf.IsValid() {
+		return NormalForm{}, errors.New("NormalFormF(): leaf exponents failed the normal form conditions")
+	}
+	return nf, nil
+}
+
+// IsValid checks the normal form conditions: the exponents are non-negative, the two sequences have
+// the same length with a nonzero exponent at the last index, and whenever x_k occurs in both p and
+// q, so does x_(k+1) in at least one of them.
+func (nf NormalForm) IsValid() bool {
+	length := len(nf.Positive)
+	if length != len(nf.Negative) {
+		return false
+	}
+	if length > 0 && 0 == nf.Positive[length-1] && 0 == nf.Negative[length-1] {
+		return false
+	}
+	for k := 0; k < length; k++ {
+		if nf.Positive[k] < 0 || nf.Negative[k] < 0 {
+			return false
+		}
+		if 0 == nf.Positive[k] || 0 == nf.Negative[k] {
+			continue
+		}
+		if k+1 == length || (0 == nf.Positive[k+1] && 0 == nf.Negative[k+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// String writes the normal form as a word that EvaluateWord reads, e.g. "x0^-1 x2 x0".
+func (nf NormalForm) String() string {
+	var letters []string
+	for k := 0; k < len(nf.Negative); k++ {
+		letters = appendPower(letters, "x"+strconv.Itoa(k), -nf.Negative[k])
+	}
+	for k := len(nf.Positive) - 1; k >= 0; k-- {
+		letters = appendPower(letters, "x"+strconv.Itoa(k), nf.Positive[k])
+	}
+	return strings.Join(letters, " ")
+}
@@ -0,0 +1,40 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalForm(t *testing.T) {
+
+	t.Run("NormalFormF test", func(t *testing.T) {
+		g, _ := EvaluateWord("01", "x0 x1 x0^-1 x2^-1 x1^-1")
+		nf, err := NormalFormF(g)
+		assert.Nil(t, err)
+		assert.True(t, nf.IsValid(), "NormalFormF result should satisfy the normal form conditions.")
+		back, _ := EvaluateWord("01", nf.String())
+		assert.Equal(t, g.FullString(), back.FullString())
+
+		g, _ = EvaluateWord("01", "x1^-1 x0^2 x3 x0^-1")
+		nf, err = NormalFormF(g)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 0, 0, 0, 0, 1}, nf.Positive)
+		assert.Equal(t, []int{0, 1, 0, 0, 0, 0}, nf.Negative)
+
+		g, _ = EvaluateWord("01", "")
+		nf, err = NormalFormF(g)
+		assert.Nil(t, err)
+		assert.Empty(t, nf.Positive)
+		assert.Empty(t, nf.Negative)
+	})
+
+	t.Run("NormalForm IsValid test", func(t *testing.T) {
+		assert.True(t, NormalForm{Positive: []int{1, 1}, Negative: []int{1, 0}}.IsValid())
+		// x_0 on both sides with nothing at index 1 cancels.
+		assert.False(t, NormalForm{Positive: []int{1}, Negative: []int{1}}.IsValid())
+		assert.False(t, NormalForm{Positive: []int{1, 0}, Negative: []int{0, 0}}.IsValid())
+		assert.False(t, NormalForm{Positive: []int{-1}, Negative: []int{0}}.IsValid())
+		assert.False(t, NormalForm{Positive: []int{1}, Negative: []int{}}.IsValid())
+	})
+}
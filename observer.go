@@ -0,0 +1,48 @@
+package treepair
+
+// Observer is told of each change made to a tree pair it watches, as set by SetObserver, after
+// the change is made.  Carets are given by their roots, the root of a tree written as "".
+type Observer interface {
+	// OnExpand reports that the domain tree was expanded at domain, and correspondingly the
+	// range tree at rng.  Carets added on the way down to either are not reported apart.
+	OnExpand(domain, rng string)
+	// OnReduce reports that the caret at domain in the domain tree, carried onto the caret at
+	// rng in the range tree, was reduced.
+	OnReduce(domain, rng string)
+	// OnPermute reports that perm was applied to the labels of the domain tree, if domain is
+	// true, and of the range tree otherwise.
+	OnPermute(domain bool, perm map[int]int)
+}
+
+// SetObserver makes o watch tp, so that every expansion, reduction (including those made by
+// Minimise) and permutation of tp is reported to o; nil stops reporting.  Relabelling by
+// PermuteLabels and ResetLabels and swapping the trees by Invert do not change the prefix map
+// as read leaf by leaf through the labels, and are not reported.  Copies made by clone do not
+// inherit the observer.
+func (tp *treePair) SetObserver(o Observer) {
+	tp.observer = o
+}
+
+// invertedObserver passes reports on to an Observer with the domain and range exchanged, for
+// changes made to the range of an element through the domain of its inverse.
+type invertedObserver struct {
+	o Observer
+}
+
+// inverted returns the observer to tell of changes made to the inverse of an element o
+// watches, or nil if o is nil.
+func inverted(o Observer) Observer {
+	if nil == o {
+		return nil
+	}
+	if i, ok := o.(invertedObserver); ok {
+		return i.o
+	}
+	return invertedObserver{o}
+}
+
+func (i invertedObserver) OnExpand(domain, rng string) { i.o.OnExpand(rng, domain) }
+func (i invertedObserver) OnReduce(domain, rng string) { i.o.OnReduce(rng, domain) }
+func (i invertedObserver) OnPermute(domain bool, perm map[int]int) {
+	i.o.OnPermute(!domain, perm)
+}
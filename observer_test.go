@@ -0,0 +1,70 @@
+package treepair
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder is an Observer writing down what it is told.
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) OnExpand(domain, rng string) {
+	r.events = append(r.events, "expand "+domain+" "+rng)
+}
+
+func (r *recorder) OnReduce(domain, rng string) {
+	r.events = append(r.events, "reduce "+domain+" "+rng)
+}
+
+func (r *recorder) OnPermute(domain bool, perm map[int]int) {
+	r.events = append(r.events, "permute "+strconv.FormatBool(domain)+" "+strconv.Itoa(len(perm)))
+}
+
+func TestObserver(t *testing.T) {
+
+	t.Run("Observer test", func(t *testing.T) {
+		a, _ := GeneratorA()
+		r := &recorder{}
+		a.SetObserver(r)
+		a.ExpandDomainAt("11")
+		a.ExpandRangeAt("00")
+		assert.True(t, a.ReduceDomainAt("11"))
+		a.ApplyPermRange(map[int]int{0: 1, 1: 0, 2: 2, 3: 3})
+		a.ApplyPermRange(map[int]int{0: 1, 1: 0, 2: 2, 3: 3})
+		a.Minimise()
+		assert.Equal(t, []string{
+			"expand 11 1",
+			"expand 0 00",
+			"reduce 11 1",
+			"permute false 4",
+			"permute false 4",
+			"reduce 0 00",
+		}, r.events)
+
+		r.events = nil
+		assert.False(t, a.ReduceRangeAt("0"))
+		a.ExpandRangeAt("1")
+		assert.True(t, a.ReduceRangeAt("1"))
+		assert.Equal(t, []string{"expand 11 1", "reduce 11 1"}, r.events)
+
+		r.events = nil
+		a.SetObserver(nil)
+		a.ExpandDomainAt("0")
+		a.Minimise()
+		assert.Empty(t, r.events)
+	})
+
+	t.Run("Observer with SetAutoMinimise test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		r := &recorder{}
+		c.SetObserver(r)
+		c.SetAutoMinimise(true)
+		c.ExpandRangeAt("0")
+		assert.Equal(t, []string{"expand 10 0", "reduce 10 0"}, r.events)
+		assert.Empty(t, clone(c).observer, "copies do not inherit the observer.")
+	})
+}
@@ -0,0 +1,74 @@
+package treepair
+
+import (
+	"context"
+	"strings"
+)
+
+// Order returns the order of the element tp and true, or 0 and false if tp has infinite
+// order.  It runs through the powers of tp until one is the identity or has a domain leaf d
+// whose range leaf is a proper prefix or a proper extension of d.  The cone at d is then
+// carried into itself or onto a cone containing it, so no further power is trivial.  Every
+// element of infinite order has an attracting periodic orbit, and the power fixing it shows
+// such a leaf, so the search ends.
+func Order(tp TreePair) (int, bool) {
+	order, finite, _ := orderContext(context.Background(), tp)
+	return order, finite
+}
+
+// orderContext is Order, giving up with ctx.Err() once ctx is done.
+func orderContext(ctx context.Context, tp TreePair) (int, bool, error) {
+	power := clone(tp)
+	power.Minimise()
+	for k := 1; ; k++ {
+		if err := ctx.Err(); nil != err {
+			return 0, false, err
+		}
+		if power.IsIdentity() {
+			return k, true, nil
+		}
+		for _, v := range leafPairs(power) {
+			if v[0] != v[1] && (strings.HasPrefix(v[1], v[0]) || strings.HasPrefix(v[0], v[1])) {
+				return 0, false, nil
+			}
+		}
+		power = Multiply(power, clone(tp))
+	}
+}
+
+// IsPowerOf reports whether h = g^k for some k, and returns k.  If g has finite order n the
+// powers g^0, ..., g^(n-1) are all compared with h, whatever bound is, and k lies in [0, n).
+// Otherwise the powers are distinct and only those with |k| <= bound are compared, in order
+// of |k| and positive before negative.  Elements over different alphabets give false.
+// Neither h nor g is modified.
+func IsPowerOf(h, g TreePair, bound int) (int, bool) {
+	if string(h.Alphabet()) != string(g.Alphabet()) {
+		return 0, false
+	}
+	if h.IsIdentity() {
+		return 0, true
+	}
+	order, finite := Order(g)
+	if finite {
+		bound = order - 1
+	}
+
+	forward, backward := clone(g), clone(g)
+	forward.Minimise()
+	backward.Invert()
+	backward.Minimise()
+	up, down := clone(forward), clone(backward)
+	for k := 1; k <= bound; k++ {
+		if EqualsAsElements(h, up) {
+			return k, true
+		}
+		if !finite && EqualsAsElements(h, down) {
+			return -k, true
+		}
+		up = Multiply(up, clone(forward))
+		if !finite {
+			down = Multiply(down, clone(backward))
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,67 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrder(t *testing.T) {
+
+	t.Run("Order test", func(t *testing.T) {
+		for word, want := range map[string]int{
+			"":           1,
+			"C":          3,
+			"C^2":        3,
+			"pi0":        2,
+			"B C":        5,
+			"A^-1 C A":   3,
+			"C pi0":      2,
+			"A":          0,
+			"A pi0":      0,
+			"x3^2 x1^-1": 0,
+		} {
+			tp, err := EvaluateWord("01", word)
+			assert.Nil(t, err)
+			order, finite := Order(tp)
+			assert.Equal(t, want, order, word)
+			assert.Equal(t, 0 != want, finite, word)
+		}
+	})
+
+	t.Run("Order leaves its argument alone test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		before := c.FullString()
+		Order(c)
+		assert.Equal(t, before, c.FullString())
+	})
+
+	t.Run("IsPowerOf test", func(t *testing.T) {
+		a, _ := GeneratorA()
+		for word, want := range map[string]int{"A^3": 3, "A^-2": -2, "": 0, "A": 1} {
+			h, _ := EvaluateWord("01", word)
+			k, ok := IsPowerOf(h, a, 5)
+			assert.True(t, ok, word)
+			assert.Equal(t, want, k, word)
+		}
+		h, _ := EvaluateWord("01", "A^6")
+		_, ok := IsPowerOf(h, a, 5)
+		assert.False(t, ok, "beyond the bound.")
+		b, _ := GeneratorB()
+		_, ok = IsPowerOf(b, a, 5)
+		assert.False(t, ok)
+
+		// C has order 3, so C^-1 = C^2 is found whatever the bound.
+		c, _ := GeneratorC()
+		h, _ = EvaluateWord("01", "C^-1")
+		k, ok := IsPowerOf(h, c, 0)
+		assert.True(t, ok)
+		assert.Equal(t, 2, k)
+		_, ok = IsPowerOf(a, c, 10)
+		assert.False(t, ok)
+
+		ternary, _ := NewTreePairAlpha("012")
+		_, ok = IsPowerOf(ternary, a, 1)
+		assert.False(t, ok)
+	})
+}
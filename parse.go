@@ -0,0 +1,116 @@
+package treepair
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseElement reads an element over the alphabet alphaStr written either in DFS notation, as
+// "{11000,10100,1 2 0}", or in the Full notation of FullString, as
+// "{D: [00 0], [01 1], [1 2] || R: [0 1], [10 2], [11 0]}".
+func ParseElement(alphaStr, s string) (TreePair, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "{") || !strings.HasSuffix(s, "}") {
+		return nil, errors.New("ParseElement(): " + s + " is not enclosed in braces")
+	}
+	if strings.Contains(s, "||") {
+		return parseFull(alphaStr, s)
+	}
+	tp, err := NewTreePairAlpha(alphaStr)
+	if nil != err {
+		return nil, err
+	}
+	if "{0,0,0}" == strings.ReplaceAll(s, " ", "") {
+		return tp, nil
+	}
+	if !EncodeDFS(tp, s) {
+		return nil, errors.New("ParseElement(): " + s + " is not a DFS string over " + alphaStr)
+	}
+	if tp.CodeDomain().Size() != tp.CodeRange().Size() || !validLabels(tp.CodeRange().Code()) {
+		return nil, errors.New("ParseElement(): " + s + " has a bad permutation")
+	}
+	return tp, nil
+}
+
+// MustParseElement is like ParseElement but panics if s cannot be read, for elements written
+// into programs, as %#v writes them.
+func MustParseElement(alphaStr, s string) TreePair {
+	tp, err := ParseElement(alphaStr, s)
+	if nil != err {
+		panic(err)
+	}
+	return tp
+}
+
+// validLabels reports whether the labels of a code are 0, 1, ..., n-1 in some order.
+func validLabels(code map[string]int) bool {
+	seen := make([]bool, len(code))
+	for _, v := range code {
+		if v < 0 || v >= len(code) || seen[v] {
+			return false
+		}
+		seen[v] = true
+	}
+	return true
+}
+
+// parseFull reads the Full notation "{D: [00 0], [01 1], [1 2] || R: [0 1], [10 2], [11 0]}",
+// carrying each domain leaf to the range leaf with the same label.
+func parseFull(alphaStr, s string) (TreePair, error) {
+	sides := strings.Split(strings.TrimSuffix(strings.TrimPrefix(s, "{"), "}"), "||")
+	if 2 != len(sides) {
+		return nil, errors.New("parseFull(): " + s + " does not have two sides")
+	}
+	if _, err := NewTreePairAlpha(alphaStr); nil != err {
+		return nil, err
+	}
+	alpha := []rune(alphaStr)
+	var leaves [2]map[int]string
+	for k, prefix := range []string{"D:", "R:"} {
+		side := strings.TrimSpace(sides[k])
+		if !strings.HasPrefix(side, prefix) {
+			return nil, errors.New("parseFull(): side " + side + " does not start with " + prefix)
+		}
+		leaves[k] = map[int]string{}
+		var words []string
+		for _, pair := range strings.Split(strings.TrimPrefix(side, prefix), ",") {
+			fields := strings.Fields(strings.Trim(strings.TrimSpace(pair), "[]"))
+			if 2 != len(fields) {
+				return nil, errors.New("parseFull(): " + pair + " is not of the form [leaf label]")
+			}
+			label, err := strconv.Atoi(fields[1])
+			if nil != err {
+				return nil, errors.New("parseFull(): bad label in " + pair)
+			}
+			if _, repeated := leaves[k][label]; repeated {
+				return nil, errors.New("parseFull(): label " + fields[1] + " is repeated")
+			}
+			w := rootAsEmpty(fields[0])
+			for _, c := range w {
+				if !strings.ContainsRune(alphaStr, c) {
+					return nil, errors.New("parseFull(): letter " + string(c) + " of " + w + " is not in the alphabet " + alphaStr)
+				}
+			}
+			leaves[k][label] = w
+			words = append(words, w)
+		}
+		sort.Strings(words)
+		if !isAntichain(words) || !coversCantorSet(alpha, words) {
+			return nil, errors.New("parseFull(): " + side + " is not a complete prefix code")
+		}
+	}
+	if len(leaves[0]) != len(leaves[1]) {
+		return nil, errors.New("parseFull(): the two sides have different sizes")
+	}
+	m := leafMap{}
+	for label, d := range leaves[0] {
+		r, ok := leaves[1][label]
+		if !ok {
+			return nil, errors.New("parseFull(): labels of the two sides do not match")
+		}
+		m[d] = r
+	}
+	return m.treePair(alpha), nil
+}
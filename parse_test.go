@@ -0,0 +1,55 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	t.Run("ParseElement DFS test", func(t *testing.T) {
+		tp, err := ParseElement("01", "{11000,10100,1 2 0}")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, tp.DFSString(), "{11000,10100,1 2 0}")
+		tp, err = ParseElement("01", " {0,0,0} ")
+		assert.Nil(t, err)
+		assert.True(t, tp.IsIdentity())
+
+		for _, s := range []string{"11000,10100,1 2 0", "{11000,10100}", "{1100,10100,1 2 0}", "{11000,10100,1 1 0}",
+			"{11000,10100,1 0}", "{11000,10100,1 2 0 3}", "{11000,10100,1 x 0}"} {
+			_, err = ParseElement("01", s)
+			assert.NotNil(t, err, s)
+		}
+	})
+
+	t.Run("ParseElement Full test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		for _, tp := range []TreePair{c, Power(c, 2), identityOver([]rune("01"))} {
+			parsed, err := ParseElement("01", tp.FullString())
+			assert.Nil(t, err)
+			assert.True(t, EqualsAsElements(parsed, tp), tp.FullString())
+		}
+		tp, err := ParseElement("01", "{D: [00 0], [01 1], [1 2] || R: [0 1], [10 2], [11 0]}")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, tp.DFSString(), "{11000,10100,1 2 0}")
+
+		for _, s := range []string{
+			"{D: [00 0], [01 1] || R: [0 1], [10 2], [11 0]}",
+			"{D: [00 0], [01 1], [1 2] || R: [0 1], [10 1], [11 0]}",
+			"{D: [00 0], [01 1], [1 2] || R: [0 1], [10 2], [12 0]}",
+			"{D: [00 0], [01 1], [1 2] | R: [0 1], [10 2], [11 0]}",
+			"{[00 0], [01 1], [1 2] || R: [0 1], [10 2], [11 0]}",
+		} {
+			_, err = ParseElement("01", s)
+			assert.NotNil(t, err, s)
+		}
+	})
+}
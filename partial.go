@@ -0,0 +1,246 @@
+package treepair
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/loeksnokes/prefcode"
+)
+
+/*
+PartialTreePair is an element of the Thompson groupoid over an alphabet: a prefix replacement
+map carrying the union of the cones at the words of a finite antichain (the domain) onto the
+union of the cones at the words of another (the range).  Unlike a TreePair, the domain and
+range need not cover the whole Cantor set, nor each other.  Words are written with
+prefcode.EmptyString for the root.
+
+Can:
+ 1. Return its domain and range antichains and the image of a word.
+ 2. Minimise, by reducing families of sibling cones carried onto families of sibling cones.
+ 3. Invert.
+ 4. Compose with another partial tree pair covering the matching set (see ComposePartial).
+ 5. Convert to and from a TreePair when the domain and range are complete prefix codes.
+*/
+type PartialTreePair interface {
+	Alphabet() []rune
+	Domain() []string
+	Range() []string
+	Image(w string) (string, error)
+	Invert()
+	Minimise()
+	Size() int
+	FullString() string
+	ToTreePair() (TreePair, error)
+}
+
+type partialTreePair struct {
+	alphabet []rune
+	pairs    leafMap
+}
+
+// NewPartialTreePair returns the partial tree pair over the alphabet alphaStr carrying the cone
+// at domain[k] onto the cone at ran[k], for each k.  The two lists must be antichains of the
+// same length over the alphabet.
+func NewPartialTreePair(alphaStr string, domain, ran []string) (*partialTreePair, error) {
+	if len(domain) != len(ran) {
+		return nil, errors.New("NewPartialTreePair(): domain and range have different sizes")
+	}
+	if 0 == len(domain) {
+		return nil, errors.New("NewPartialTreePair(): domain is empty")
+	}
+	alpha := prefcode.StringToRuneSlice(alphaStr)
+	p := &partialTreePair{alphabet: alpha, pairs: leafMap{}}
+	for k := range domain {
+		d, r := rootAsEmpty(domain[k]), rootAsEmpty(ran[k])
+		for _, w := range []string{d, r} {
+			for _, c := range w {
+				if !strings.ContainsRune(alphaStr, c) {
+					return nil, errors.New("NewPartialTreePair(): letter " + string(c) + " of " + w + " is not in the alphabet " + alphaStr)
+				}
+			}
+		}
+		p.pairs[d] = r
+	}
+	if !isAntichain(sortedKeys(p.pairs)) || !isAntichain(p.rangeWords()) {
+		return nil, errors.New("NewPartialTreePair(): domain or range is not an antichain")
+	}
+	return p, nil
+}
+
+// PartialFromTreePair returns the partial tree pair with the same prefix replacement map as the
+// minimised tp.  tp itself is not modified.
+func PartialFromTreePair(tp TreePair) *partialTreePair {
+	min := clone(tp)
+	min.Minimise()
+	return &partialTreePair{alphabet: min.Alphabet(), pairs: newLeafMap(min)}
+}
+
+// isAntichain reports whether no word of the sorted list words is a prefix of another.  In
+// dictionary order a word is immediately followed by its extensions, if any.
+func isAntichain(words []string) bool {
+	for k := 0; k+1 < len(words); k++ {
+		if strings.HasPrefix(words[k+1], words[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// rangeWords returns the range words of p in dictionary order.
+func (p *partialTreePair) rangeWords() []string {
+	words := make([]string, 0, len(p.pairs))
+	for _, v := range p.pairs {
+		words = append(words, v)
+	}
+	sort.Strings(words)
+	return words
+}
+
+// Alphabet returns a copy of the alphabet of p.
+func (p *partialTreePair) Alphabet() []rune {
+	return append([]rune(nil), p.alphabet...)
+}
+
+// Domain returns the domain antichain in dictionary order.
+func (p *partialTreePair) Domain() []string {
+	words := sortedKeys(p.pairs)
+	for k, v := range words {
+		words[k] = emptyAsRoot(v)
+	}
+	return words
+}
+
+// Range returns the range antichain in dictionary order.
+func (p *partialTreePair) Range() []string {
+	words := p.rangeWords()
+	for k, v := range words {
+		words[k] = emptyAsRoot(v)
+	}
+	return words
+}
+
+// Size returns the number of cones in the domain.
+func (p *partialTreePair) Size() int {
+	return len(p.pairs)
+}
+
+// Image returns the image of the finite word w, which must lie in a domain cone.
+func (p *partialTreePair) Image(w string) (string, error) {
+	w = rootAsEmpty(w)
+	for d, r := range p.pairs {
+		if strings.HasPrefix(w, d) {
+			return emptyAsRoot(r + strings.TrimPrefix(w, d)), nil
+		}
+	}
+	return "", errors.New("PartialTreePair.Image(): " + w + " does not lie in the domain")
+}
+
+// Invert replaces p by its inverse.
+func (p *partialTreePair) Invert() {
+	inverse := make(leafMap, len(p.pairs))
+	for k, v := range p.pairs {
+		inverse[v] = k
+	}
+	p.pairs = inverse
+}
+
+// Minimise replaces each family of sibling domain cones w c carried onto a family of sibling
+// range cones v c by the single pair w -> v, until there are none left.
+func (p *partialTreePair) Minimise() {
+	alpha := p.alphabet
+	p.pairs.minimise(func(rune) []rune { return alpha })
+}
+
+// FullString returns the pairs of p in dictionary order of the domain words, e.g.
+// "[00 1], [01 𝛆]".
+func (p *partialTreePair) FullString() string {
+	parts := make([]string, 0, len(p.pairs))
+	for _, d := range sortedKeys(p.pairs) {
+		parts = append(parts, "["+emptyAsRoot(d)+" "+emptyAsRoot(p.pairs[d])+"]")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ToTreePair returns the tree pair with the same prefix replacement map, if the domain and
+// range are complete prefix codes.
+func (p *partialTreePair) ToTreePair() (TreePair, error) {
+	if !coversCantorSet(p.alphabet, sortedKeys(p.pairs)) || !coversCantorSet(p.alphabet, p.rangeWords()) {
+		return nil, errors.New("PartialTreePair.ToTreePair(): domain or range is not a complete prefix code")
+	}
+	return p.pairs.treePair(p.alphabet), nil
+}
+
+// coversCantorSet reports whether the cones at the antichain words cover the whole Cantor set,
+// i.e. whether their measures add up to 1.
+func coversCantorSet(alpha []rune, words []string) bool {
+	total := new(big.Rat)
+	for _, w := range words {
+		_, width := wordInterval(alpha, emptyAsRoot(w))
+		total.Add(total, width)
+	}
+	return total.Cmp(big.NewRat(1, 1)) == 0
+}
+
+// ComposePartial returns the partial tree pair applying first and then second, following the
+// convention of Multiply.  It is defined when the range cones of first cover exactly the same
+// set as the domain cones of second.  Both are expanded until these two antichains agree, and
+// the result is minimised.  first and second are not modified.
+func ComposePartial(first, second PartialTreePair) (*partialTreePair, error) {
+	if string(first.Alphabet()) != string(second.Alphabet()) {
+		return nil, errors.New("ComposePartial(): partial tree pairs are over different alphabets")
+	}
+	alpha := first.Alphabet()
+	f, s := toLeafMap(first), toLeafMap(second)
+	inverse := make(leafMap, len(f))
+	for k, v := range f {
+		inverse[v] = k
+	}
+
+	// expand each side below the cones of the other until the range of f is the domain of s.
+	for changed := true; changed; {
+		changed = false
+		for _, r := range sortedKeys(inverse) {
+			if hasLeafBelow(s, r) {
+				a := inverse[r]
+				f = f.expandAt(alpha, a)
+				delete(inverse, r)
+				for _, c := range alpha {
+					inverse[r+string(c)] = a + string(c)
+				}
+				changed = true
+			}
+		}
+		for _, d := range sortedKeys(s) {
+			if hasLeafBelow(inverse, d) {
+				s = s.expandAt(alpha, d)
+				changed = true
+			}
+		}
+	}
+	if len(inverse) != len(s) {
+		return nil, errors.New("ComposePartial(): range of first and domain of second cover different sets")
+	}
+	composite := make(leafMap, len(f))
+	for a, r := range f {
+		v, ok := s[r]
+		if !ok {
+			return nil, errors.New("ComposePartial(): range of first and domain of second cover different sets")
+		}
+		composite[a] = v
+	}
+	p := &partialTreePair{alphabet: alpha, pairs: composite}
+	p.Minimise()
+	return p, nil
+}
+
+// toLeafMap returns a copy of the pairs of p, writing the root as "".
+func toLeafMap(p PartialTreePair) leafMap {
+	m := leafMap{}
+	for _, d := range p.Domain() {
+		image, _ := p.Image(d)
+		m[rootAsEmpty(d)] = rootAsEmpty(image)
+	}
+	return m
+}
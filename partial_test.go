@@ -0,0 +1,85 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartialTreePair(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	t.Run("NewPartialTreePair test", func(t *testing.T) {
+		p, err := NewPartialTreePair("01", []string{"00", "01"}, []string{"1", "01"})
+		assert.Nil(t, err)
+		assertCorrectMessage(t, p.FullString(), "[00 1], [01 01]")
+		assert.Equal(t, []string{"01", "1"}, p.Range())
+		assert.Equal(t, 2, p.Size())
+
+		image, err := p.Image("0010")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, image, "110")
+		_, err = p.Image("1")
+		assert.NotNil(t, err)
+
+		_, err = NewPartialTreePair("01", []string{"0", "01"}, []string{"1", "00"})
+		assert.NotNil(t, err)
+		_, err = NewPartialTreePair("01", []string{"0"}, []string{"1", "00"})
+		assert.NotNil(t, err)
+		_, err = NewPartialTreePair("01", []string{"2"}, []string{"1"})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("PartialTreePair Minimise test", func(t *testing.T) {
+		p, _ := NewPartialTreePair("01", []string{"100", "101", "11"}, []string{"00", "01", "1"})
+		p.Minimise()
+		assertCorrectMessage(t, p.FullString(), "[1 𝛆]")
+		p, _ = NewPartialTreePair("01", []string{"0", "1"}, []string{"0", "1"})
+		p.Minimise()
+		assertCorrectMessage(t, p.FullString(), "[𝛆 𝛆]")
+	})
+
+	t.Run("ComposePartial test", func(t *testing.T) {
+		// carry the cone at 0 onto the cone at 10, then the cone at 10 onto 11 by halves.
+		p, _ := NewPartialTreePair("01", []string{"0"}, []string{"10"})
+		q, _ := NewPartialTreePair("01", []string{"100", "101"}, []string{"110", "111"})
+		pq, err := ComposePartial(p, q)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, pq.FullString(), "[0 11]")
+
+		p.Invert()
+		_, err = ComposePartial(p, q)
+		assert.NotNil(t, err)
+		p.Invert()
+		r, _ := NewPartialTreePair("01", []string{"10", "11"}, []string{"0", "1"})
+		_, err = ComposePartial(p, r)
+		assert.NotNil(t, err)
+
+		inverse, _ := NewPartialTreePair("01", []string{"0"}, []string{"10"})
+		inverse.Invert()
+		id, err := ComposePartial(p, inverse)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, id.FullString(), "[0 0]")
+	})
+
+	t.Run("PartialTreePair agrees with TreePair test", func(t *testing.T) {
+		for _, words := range [][2]string{{"x0", "x1"}, {"C", "A pi0"}, {"B C x2^2", "C^-1 x0"}} {
+			a, _ := EvaluateWord("01", words[0])
+			b, _ := EvaluateWord("01", words[1])
+			ab, err := ComposePartial(PartialFromTreePair(a), PartialFromTreePair(b))
+			assert.Nil(t, err)
+			tp, err := ab.ToTreePair()
+			assert.Nil(t, err)
+			assert.True(t, EqualsAsElements(tp, Multiply(clone(a), clone(b))), words[0]+" "+words[1])
+		}
+		p, _ := NewPartialTreePair("01", []string{"0"}, []string{"10"})
+		_, err := p.ToTreePair()
+		assert.NotNil(t, err)
+	})
+}
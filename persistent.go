@@ -0,0 +1,172 @@
+package treepair
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/loeksnokes/prefcode"
+)
+
+// caretNode is a node of a tree kept in a caretStore: a leaf, with no children, or a caret
+// with a child for each letter of the alphabet.  Nodes are hash-consed, so equal subtrees are
+// the same node and are never changed.
+type caretNode struct {
+	id       int
+	children []*caretNode
+	leaves   int
+}
+
+// caretStore hash-conses the trees of the codes of tree pairs over one alphabet: every subtree
+// is built once, and trees differing by a single caret share all the subtrees off the path
+// down to it.  A caretStore is safe for use by several goroutines.
+type caretStore struct {
+	alphabet []rune
+	mu       sync.Mutex
+	nodes    map[string]*caretNode
+	leaf     *caretNode
+	next     int
+}
+
+// maxStoreNodes bounds the nodes a caretStore holds.  Once the store of an alphabet holds
+// more, storeFor starts a fresh one: trees built in the old store keep their nodes, which are
+// no longer shared with the trees built from then on, and go once those trees do.
+const maxStoreNodes = 1 << 18
+
+// caretStores holds the store in use for each alphabet.
+var caretStores = struct {
+	sync.Mutex
+	byAlphabet map[string]*caretStore
+}{byAlphabet: make(map[string]*caretStore)}
+
+// newCaretStore returns an empty store of trees over the alphabet alpha.
+func newCaretStore(alpha []rune) *caretStore {
+	leaf := &caretNode{leaves: 1}
+	return &caretStore{alphabet: alpha, nodes: map[string]*caretNode{"": leaf}, leaf: leaf, next: 1}
+}
+
+// storeFor returns the store in use for the alphabet alpha.
+func storeFor(alpha []rune) *caretStore {
+	caretStores.Lock()
+	defer caretStores.Unlock()
+	s := caretStores.byAlphabet[string(alpha)]
+	if nil == s || s.size() > maxStoreNodes {
+		s = newCaretStore(alpha)
+		caretStores.byAlphabet[string(alpha)] = s
+	}
+	return s
+}
+
+// size returns the number of distinct subtrees held in s.
+func (s *caretStore) size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.nodes)
+}
+
+// nodeKey returns the key of the caret with the given children in the nodes of a store.
+func nodeKey(children []*caretNode) string {
+	key := make([]byte, 0, 8*len(children))
+	for _, c := range children {
+		key = strconv.AppendInt(key, int64(c.id), 10)
+		key = append(key, ',')
+	}
+	return string(key)
+}
+
+// caret returns the node of the caret with the given children, building it only if it is not
+// in s already.
+func (s *caretStore) caret(children []*caretNode) *caretNode {
+	leaves := 0
+	for _, c := range children {
+		leaves += c.leaves
+	}
+	key := nodeKey(children)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n, ok := s.nodes[key]; ok {
+		return n
+	}
+	n := &caretNode{id: s.next, children: children, leaves: leaves}
+	s.next++
+	s.nodes[key] = n
+	return n
+}
+
+// tree returns the tree of the prefix code pc, and the labels of its leaves in the order of
+// the alphabet.
+func (s *caretStore) tree(pc prefcode.PrefCode) (*caretNode, []int) {
+	code := pc.Code()
+	labels := make([]int, 0, len(code))
+	var build func(prefix string) *caretNode
+	build = func(prefix string) *caretNode {
+		key := prefix
+		if "" == key {
+			key = prefcode.EmptyString
+		}
+		if label, ok := code[key]; ok {
+			labels = append(labels, label)
+			return s.leaf
+		}
+		children := make([]*caretNode, len(s.alphabet))
+		for k, c := range s.alphabet {
+			children[k] = build(prefix + string(c))
+		}
+		return s.caret(children)
+	}
+	return build(""), labels
+}
+
+// code returns the prefix code with the tree n, labelling its leaves in the order of the
+// alphabet by labels.
+func (s *caretStore) code(n *caretNode, labels []int) prefcode.PrefCode {
+	pc, err := prefcode.NewPrefCodeAlphaRunes(s.alphabet)
+	if nil != err {
+		panic("code(): could not build code over alphabet " + string(s.alphabet))
+	}
+	code := pc.Code()
+	delete(code, prefcode.EmptyString)
+	next := 0
+	var fill func(n *caretNode, prefix string)
+	fill = func(n *caretNode, prefix string) {
+		if nil == n.children {
+			key := prefix
+			if "" == key {
+				key = prefcode.EmptyString
+			}
+			code[key] = labels[next]
+			next++
+			return
+		}
+		for k, c := range n.children {
+			fill(c, prefix+string(s.alphabet[k]))
+		}
+	}
+	fill(n, "")
+	return pc
+}
+
+// codeTrees holds the codes of a tree pair as trees in a caretStore, with the labels of their
+// leaves in the order of the alphabet.  It is never changed, so that tree pairs may share it.
+type codeTrees struct {
+	store                *caretStore
+	dom, ran             *caretNode
+	domLabels, ranLabels []int
+}
+
+// newCodeTrees returns the codes dom and ran as trees in the store of their alphabet.
+func newCodeTrees(dom, ran prefcode.PrefCode) *codeTrees {
+	t := &codeTrees{store: storeFor(dom.Alphabet())}
+	t.dom, t.domLabels = t.store.tree(dom)
+	t.ran, t.ranLabels = t.store.tree(ran)
+	return t
+}
+
+// codes returns the codes of t as prefix codes of their own.
+func (t *codeTrees) codes() (dom, ran prefcode.PrefCode) {
+	return t.store.code(t.dom, t.domLabels), t.store.code(t.ran, t.ranLabels)
+}
+
+// inverse returns t with its domain and range swapped.
+func (t *codeTrees) inverse() *codeTrees {
+	return &codeTrees{store: t.store, dom: t.ran, ran: t.dom, domLabels: t.ranLabels, ranLabels: t.domLabels}
+}
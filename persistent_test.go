@@ -0,0 +1,66 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersistent(t *testing.T) {
+
+	t.Run("Code trees round trip test", func(t *testing.T) {
+		for _, word := range []string{"", "C", "x0 x1^-1 x2", "pi0 C^2", "A B^-1"} {
+			tp, _ := EvaluateWord("01", word)
+			c := clone(tp)
+			assert.NotNil(t, c.trees, word)
+			assert.Equal(t, tp.Size(), c.trees.dom.leaves, word)
+			assert.Equal(t, tp.FullString(), c.FullString(), word)
+			c.Invert()
+			tp.Invert()
+			assert.Equal(t, tp.FullString(), c.FullString(), word)
+		}
+		tp, _ := NewTreePairAlpha("ba3")
+		EncodeDFS(tp, "{1000,1000,2 0 1}")
+		tp.ApplyPermDomain(map[int]int{0: 1, 1: 2, 2: 0})
+		assert.Equal(t, tp.FullString(), clone(tp).FullString())
+		assert.Equal(t, tp.Alphabet(), clone(tp).Alphabet())
+	})
+
+	// equal trees are the same nodes, whichever tree pairs they were built for.
+	t.Run("Hash-consing test", func(t *testing.T) {
+		a, _ := EvaluateWord("01", "x0 x1")
+		b, _ := EvaluateWord("01", "x0 x1")
+		ca, cb := clone(a), clone(b)
+		assert.Same(t, ca.trees.dom, cb.trees.dom)
+		assert.Same(t, ca.trees.ran, cb.trees.ran)
+		assert.Same(t, ca.trees, clone(ca).trees)
+	})
+
+	// expanding a big element at each of its leaves in turn builds only the nodes on the
+	// paths down to the new carets, sharing the rest with the element expanded.
+	t.Run("Structural sharing test", func(t *testing.T) {
+		id, _ := NewTreePairAlpha("ab")
+		id.ExpandToDepth(8)
+		snapshot := clone(id)
+		s := snapshot.trees.store
+		before := s.size()
+		assert.LessOrEqual(t, before, 9, "one node for the full tree of each height up to 8.")
+		var leaves []string
+		id.Leaves(func(leaf string, label int) bool {
+			leaves = append(leaves, leaf)
+			return true
+		})
+		for _, w := range leaves {
+			tp := clone(snapshot)
+			tp.ExpandDomainAt(w)
+			expanded := clone(tp)
+			assert.Equal(t, 257, expanded.trees.dom.leaves, w)
+			off := 1
+			if 'b' == rune(w[0]) {
+				off = 0
+			}
+			assert.Same(t, snapshot.trees.dom.children[off], expanded.trees.dom.children[off], w)
+		}
+		assert.LessOrEqual(t, s.size(), before+len(leaves)*9)
+	})
+}
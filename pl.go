@@ -0,0 +1,365 @@
+package treepair
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Breakpoint is a point (X, Y) on the graph of a piecewise-linear map, with the slope of the
+// map on the piece starting at X.
+type Breakpoint struct {
+	X, Y, Slope *big.Rat
+}
+
+// String writes the breakpoint as "(X, Y) slope Slope".
+func (bp Breakpoint) String() string {
+	return "(" + bp.X.RatString() + ", " + bp.Y.RatString() + ") slope " + bp.Slope.RatString()
+}
+
+// EvalDyadic returns the image of p under the element tp of T, read as a piecewise-linear
+// homeomorphism with each leaf w of the trees standing for the interval of [0,1) whose
+// base len(alphabet) expansions start with w (over "01" these are the standard dyadic
+// intervals).  Elements of F act on [0,1], so p may be 1; other elements of T act on the
+// circle [0,1) and the image is taken in [0,1).  Dyadic rationals go to dyadic rationals,
+// but any rational p is evaluated exactly.  tp and p are not modified.
+func EvalDyadic(tp TreePair, p *big.Rat) (*big.Rat, error) {
+	min := clone(tp)
+	min.Minimise()
+	if !min.InT() {
+		return nil, errors.New("EvalDyadic(): element is not in T, so does not act continuously")
+	}
+	inF := min.InF()
+	one := big.NewRat(1, 1)
+	if p.Sign() < 0 || p.Cmp(one) > 0 || (!inF && p.Cmp(one) == 0) {
+		return nil, errors.New("EvalDyadic(): " + p.RatString() + " is outside the domain of the element")
+	}
+
+	image := newCircleLift(min).eval(p)
+	if !inF {
+		image.Sub(image, new(big.Rat).SetInt(floorRat(image)))
+	}
+	return image, nil
+}
+
+// ToPL returns the breakpoints of the element tp of F as a piecewise-linear homeomorphism of
+// [0,1], with leaves read as in EvalDyadic.  The first breakpoint is (0, 0), the slope changes
+// at each later one, and the last piece runs to (1, 1).  tp itself is not modified.
+func ToPL(tp TreePair) ([]Breakpoint, error) {
+	min := clone(tp)
+	min.Minimise()
+	if !min.InF() {
+		return nil, errors.New("ToPL(): element is not in F")
+	}
+	return newCircleLift(min).breakpoints(), nil
+}
+
+// ToPLCircle returns the breakpoints of the element tp of T as a piecewise-linear homeomorphism
+// of the circle [0,1), given on its lift to the real line: the first breakpoint has X = 0 and
+// Y the image of 0 in [0,1), later values of Y increase past 1 where the map wraps around, and
+// the last piece runs to (1, Y + 1).  The slope changes at each breakpoint after the first.
+// tp itself is not modified.
+func ToPLCircle(tp TreePair) ([]Breakpoint, error) {
+	min := clone(tp)
+	min.Minimise()
+	if !min.InT() {
+		return nil, errors.New("ToPLCircle(): element is not in T")
+	}
+	return newCircleLift(min).breakpoints(), nil
+}
+
+// breakpoints lists the start of the first piece of the lift and each point where the slope
+// changes.
+func (l circleLift) breakpoints() []Breakpoint {
+	var breaks []Breakpoint
+	for _, p := range l.pieces {
+		slope := p.slope()
+		if last := len(breaks) - 1; last >= 0 && breaks[last].Slope.Cmp(slope) == 0 {
+			continue
+		}
+		breaks = append(breaks, Breakpoint{X: p.domLeft, Y: p.ranLeft, Slope: slope})
+	}
+	return breaks
+}
+
+// FromPL builds the minimal tree pair over "01" of the element of F with the given breakpoints,
+// laid out as ToPL returns them: the first at (0, 0), X increasing below 1, and the last piece
+// running to (1, 1).  Breakpoints must be dyadic and slopes powers of 2.  The slope need not
+// change at every breakpoint.
+func FromPL(breaks []Breakpoint) (TreePair, error) {
+	if err := checkPL(breaks); nil != err {
+		return nil, err
+	}
+
+	// split [0,1) into standard dyadic intervals, each lying in one piece and carried onto a
+	// standard dyadic interval.
+	dom := map[string]bool{}
+	ran := map[string]bool{}
+	var split func(w string, left, width *big.Rat)
+	split = func(w string, left, width *big.Rat) {
+		k := pieceAt(breaks, left)
+		right := new(big.Rat).Add(left, width)
+		inPiece := k+1 == len(breaks) || breaks[k+1].X.Cmp(right) >= 0
+		if inPiece {
+			image := new(big.Rat).Sub(left, breaks[k].X)
+			image.Mul(image, breaks[k].Slope)
+			image.Add(image, breaks[k].Y)
+			if v, ok := dyadicWord(image, new(big.Rat).Mul(width, breaks[k].Slope)); ok {
+				dom[w] = true
+				ran[v] = true
+				return
+			}
+		}
+		half := new(big.Rat).Quo(width, big.NewRat(2, 1))
+		split(w+"0", left, half)
+		split(w+"1", new(big.Rat).Add(left, half), half)
+	}
+	split("", new(big.Rat), big.NewRat(1, 1))
+	if dom[""] {
+		return identityOver([]rune("01")), nil
+	}
+
+	perm := make([]string, len(dom))
+	for k := range perm {
+		perm[k] = strconv.Itoa(k)
+	}
+	tp, err := binaryFromDFS("{" + codeDFS(dom, []rune("01"), "") + "," + codeDFS(ran, []rune("01"), "") + "," + strings.Join(perm, " ") + "}")
+	if nil != err {
+		return nil, err
+	}
+	tp.Minimise()
+	return tp, nil
+}
+
+// checkPL verifies that breaks describe an element of F as FromPL expects.
+func checkPL(breaks []Breakpoint) error {
+	if 0 == len(breaks) {
+		return errors.New("checkPL(): no breakpoints")
+	}
+	for k, v := range breaks {
+		if nil == v.X || nil == v.Y || nil == v.Slope {
+			return errors.New("checkPL(): breakpoint " + strconv.Itoa(k) + " has no X, Y or Slope")
+		}
+	}
+	if 0 != breaks[0].X.Sign() || 0 != breaks[0].Y.Sign() {
+		return errors.New("checkPL(): the first breakpoint must be (0, 0)")
+	}
+	one := big.NewRat(1, 1)
+	for k, v := range breaks {
+		if !isDyadic(v.X) || !isDyadic(v.Y) {
+			return errors.New("checkPL(): breakpoint " + v.String() + " is not dyadic")
+		}
+		if v.Slope.Sign() <= 0 || !isPowerOfTwo(v.Slope.Num()) || !isPowerOfTwo(v.Slope.Denom()) {
+			return errors.New("checkPL(): slope at breakpoint " + v.String() + " is not a power of 2")
+		}
+		next := Breakpoint{X: one, Y: one}
+		if k+1 < len(breaks) {
+			next = breaks[k+1]
+		}
+		if v.X.Cmp(next.X) >= 0 {
+			return errors.New("checkPL(): breakpoints must increase in [0,1)")
+		}
+		reached := new(big.Rat).Sub(next.X, v.X)
+		reached.Mul(reached, v.Slope)
+		reached.Add(reached, v.Y)
+		if reached.Cmp(next.Y) != 0 {
+			return errors.New("checkPL(): the piece from " + v.String() + " misses (" + next.X.RatString() + ", " + next.Y.RatString() + ")")
+		}
+	}
+	return nil
+}
+
+// pieceAt returns the index of the last breakpoint at or before x.
+func pieceAt(breaks []Breakpoint, x *big.Rat) int {
+	return sort.Search(len(breaks), func(i int) bool { return breaks[i].X.Cmp(x) > 0 }) - 1
+}
+
+// dyadicWord returns the binary word addressing [left, left+width) if that is a standard
+// dyadic interval.
+func dyadicWord(left, width *big.Rat) (string, bool) {
+	one := big.NewRat(1, 1)
+	if width.Cmp(one) > 0 || !isPowerOfTwo(width.Denom()) || !width.Num().IsInt64() || 1 != width.Num().Int64() {
+		return "", false
+	}
+	if position := new(big.Rat).Quo(left, width); !position.IsInt() {
+		return "", false
+	}
+	// read off the binary digits of left to the depth of width.
+	var w strings.Builder
+	x := new(big.Rat).Set(left)
+	half := big.NewRat(1, 2)
+	for size := big.NewRat(1, 1); size.Cmp(width) > 0; size.Mul(size, half) {
+		x.Mul(x, big.NewRat(2, 1))
+		if x.Cmp(one) >= 0 {
+			w.WriteString("1")
+			x.Sub(x, one)
+		} else {
+			w.WriteString("0")
+		}
+	}
+	return w.String(), true
+}
+
+// isDyadic reports whether x has a power of 2 as denominator.
+func isDyadic(x *big.Rat) bool {
+	return isPowerOfTwo(x.Denom())
+}
+
+// isPowerOfTwo reports whether n is a positive power of 2 (including 1).
+func isPowerOfTwo(n *big.Int) bool {
+	return n.Sign() > 0 && uint(n.BitLen()-1) == n.TrailingZeroBits()
+}
+
+// codeDFS returns the DFS string of the subtree at w of the tree over alpha whose leaves are
+// the words of code.
+func codeDFS(code map[string]bool, alpha []rune, w string) string {
+	if code[w] {
+		return "0"
+	}
+	dfs := "1"
+	for _, a := range alpha {
+		dfs += codeDFS(code, alpha, w+string(a))
+	}
+	return dfs
+}
+
+// plPiece is the affine map x -> y0 + slope (x - x0) on [x0, x1].
+type plPiece struct {
+	x0, x1, y0, slope *big.Rat
+}
+
+// at evaluates the piece at x.
+func (p plPiece) at(x *big.Rat) *big.Rat {
+	y := new(big.Rat).Sub(x, p.x0)
+	y.Mul(y, p.slope)
+	return y.Add(y, p.y0)
+}
+
+// plFunc is an increasing piecewise-linear map given by contiguous pieces in increasing order.
+type plFunc []plPiece
+
+// newPLFunc returns the map of [0,1] with the breakpoints of ToPL.
+func newPLFunc(breaks []Breakpoint) plFunc {
+	f := make(plFunc, len(breaks))
+	for k, v := range breaks {
+		x1 := big.NewRat(1, 1)
+		if k+1 < len(breaks) {
+			x1 = breaks[k+1].X
+		}
+		f[k] = plPiece{x0: v.X, x1: x1, y0: v.Y, slope: v.Slope}
+	}
+	return f
+}
+
+// pieceAt returns the index of the piece containing x, taking the left one at a breakpoint.
+func (f plFunc) pieceAt(x *big.Rat) int {
+	k := sort.Search(len(f), func(i int) bool { return f[i].x1.Cmp(x) >= 0 })
+	if k == len(f) {
+		k--
+	}
+	return k
+}
+
+// eval applies f to x.
+func (f plFunc) eval(x *big.Rat) *big.Rat {
+	return f[f.pieceAt(x)].at(x)
+}
+
+// inverse returns the inverse map.
+func (f plFunc) inverse() plFunc {
+	g := make(plFunc, len(f))
+	for k, p := range f {
+		g[k] = plPiece{x0: p.y0, x1: p.at(p.x1), y0: p.x0, slope: new(big.Rat).Inv(p.slope)}
+	}
+	return g
+}
+
+// then returns the composite of f followed by g on [lo, hi], which f must carry into the
+// domain of g.
+func (f plFunc) then(g plFunc, lo, hi *big.Rat) plFunc {
+	cuts := []*big.Rat{lo, hi}
+	for _, p := range f {
+		if p.x0.Cmp(lo) > 0 && p.x0.Cmp(hi) < 0 {
+			cuts = append(cuts, p.x0)
+		}
+	}
+	fInv := f.inverse()
+	for _, p := range g {
+		if x := fInv.eval(p.x0); x.Cmp(lo) > 0 && x.Cmp(hi) < 0 {
+			cuts = append(cuts, x)
+		}
+	}
+	sort.Slice(cuts, func(i, j int) bool { return cuts[i].Cmp(cuts[j]) < 0 })
+
+	var h plFunc
+	for k := 0; k+1 < len(cuts); k++ {
+		u, v := cuts[k], cuts[k+1]
+		if u.Cmp(v) == 0 {
+			continue
+		}
+		mid := new(big.Rat).Add(u, v)
+		mid.Quo(mid, big.NewRat(2, 1))
+		slope := new(big.Rat).Mul(f[f.pieceAt(mid)].slope, g[g.pieceAt(f.eval(mid))].slope)
+		h = append(h, plPiece{x0: u, x1: v, y0: g.eval(f.eval(u)), slope: slope})
+	}
+	return h.simplify()
+}
+
+// simplify merges neighbouring pieces with the same slope.
+func (f plFunc) simplify() plFunc {
+	var g plFunc
+	for _, p := range f {
+		if last := len(g) - 1; last >= 0 && g[last].slope.Cmp(p.slope) == 0 {
+			g[last].x1 = p.x1
+			continue
+		}
+		g = append(g, p)
+	}
+	return g
+}
+
+// breakpoints returns f, a map of [0,1], in the layout of ToPL.
+func (f plFunc) breakpoints() []Breakpoint {
+	breaks := make([]Breakpoint, len(f))
+	for k, p := range f {
+		breaks[k] = Breakpoint{X: p.x0, Y: p.y0, Slope: p.slope}
+	}
+	return breaks
+}
+
+// IntervalPiece is one affine piece of an element acting on [0,1): it carries the interval
+// [Left, Right) onto [ImageLeft, ImageRight) by x -> ImageLeft + Slope (x - Left).
+type IntervalPiece struct {
+	Left, Right, ImageLeft, ImageRight, Slope *big.Rat
+}
+
+// String writes the piece as "[Left, Right) -> [ImageLeft, ImageRight) slope Slope".
+func (p IntervalPiece) String() string {
+	return "[" + p.Left.RatString() + ", " + p.Right.RatString() + ") -> [" + p.ImageLeft.RatString() + ", " +
+		p.ImageRight.RatString() + ") slope " + p.Slope.RatString()
+}
+
+// ToIntervalMap returns tp as an interval exchange of [0,1): one piece for each pair of leaves
+// of the minimised tree pair, read as in EvalDyadic, in increasing order of Left.  The pieces
+// of an element of F keep their order, those of T keep it up to rotation, and those of V may
+// be rearranged.  tp itself is not modified.
+func ToIntervalMap(tp TreePair) []IntervalPiece {
+	min := canonicalCopy(tp)
+	alpha := min.Alphabet()
+	pieces := make([]IntervalPiece, 0, min.Size())
+	for _, pair := range leafPairs(min) {
+		left, width := wordInterval(alpha, pair[0])
+		imageLeft, imageWidth := wordInterval(alpha, pair[1])
+		pieces = append(pieces, IntervalPiece{
+			Left:       left,
+			Right:      new(big.Rat).Add(left, width),
+			ImageLeft:  imageLeft,
+			ImageRight: new(big.Rat).Add(imageLeft, imageWidth),
+			Slope:      new(big.Rat).Quo(imageWidth, width),
+		})
+	}
+	sort.Slice(pieces, func(i, j int) bool { return pieces[i].Left.Cmp(pieces[j].Left) < 0 })
+	return pieces
+}
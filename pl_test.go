@@ -0,0 +1,187 @@
+package treepair
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPL(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	evalAt := func(t *testing.T, word, p string) string {
+		t.Helper()
+		tp, err := EvaluateWord("01", word)
+		assert.Nil(t, err)
+		x, _ := new(big.Rat).SetString(p)
+		image, err := EvalDyadic(tp, x)
+		assert.Nil(t, err)
+		if nil == image {
+			return ""
+		}
+		return image.RatString()
+	}
+
+	t.Run("EvalDyadic test", func(t *testing.T) {
+		assertCorrectMessage(t, evalAt(t, "x0", "0"), "0")
+		assertCorrectMessage(t, evalAt(t, "x0", "1/2"), "1/4")
+		assertCorrectMessage(t, evalAt(t, "x0", "5/8"), "3/8")
+		assertCorrectMessage(t, evalAt(t, "x0", "7/8"), "3/4")
+		assertCorrectMessage(t, evalAt(t, "x0", "1"), "1")
+		assertCorrectMessage(t, evalAt(t, "x1", "1/4"), "1/4")
+		assertCorrectMessage(t, evalAt(t, "x0^-1 x1^2", "7/12"), "7/12")
+		assertCorrectMessage(t, evalAt(t, "", "3/16"), "3/16")
+	})
+
+	t.Run("EvalDyadic circle test", func(t *testing.T) {
+		// C carries [0,1/2) to [3/4,1), [1/2,3/4) to [0,1/2) and [3/4,1) to [1/2,3/4).
+		assertCorrectMessage(t, evalAt(t, "C", "0"), "3/4")
+		assertCorrectMessage(t, evalAt(t, "C", "1/4"), "7/8")
+		assertCorrectMessage(t, evalAt(t, "C", "1/2"), "0")
+		assertCorrectMessage(t, evalAt(t, "C", "7/8"), "5/8")
+		assertCorrectMessage(t, evalAt(t, "C^3", "5/16"), "5/16")
+	})
+
+	t.Run("EvalDyadic error test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		_, err := EvalDyadic(c, big.NewRat(1, 1))
+		assert.NotNil(t, err, "1 is 0 on the circle.")
+		x0, _ := GeneratorX(0)
+		_, err = EvalDyadic(x0, big.NewRat(3, 2))
+		assert.NotNil(t, err)
+		p, _ := GeneratorPi0()
+		_, err = EvalDyadic(p, big.NewRat(1, 2))
+		assert.NotNil(t, err, "pi0 is not in T.")
+	})
+
+	plString := func(breaks []Breakpoint) string {
+		parts := make([]string, len(breaks))
+		for k, v := range breaks {
+			parts[k] = v.String()
+		}
+		return strings.Join(parts, "; ")
+	}
+
+	t.Run("ToPL test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		breaks, err := ToPL(x0)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, plString(breaks), "(0, 0) slope 1/2; (1/2, 1/4) slope 1; (3/4, 1/2) slope 2")
+
+		x1, _ := GeneratorX(1)
+		breaks, err = ToPL(x1)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, plString(breaks), "(0, 0) slope 1; (1/2, 1/2) slope 1/2; (3/4, 5/8) slope 1; (7/8, 3/4) slope 2")
+
+		id, _ := EvaluateWord("01", "x2 x2^-1")
+		breaks, err = ToPL(id)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, plString(breaks), "(0, 0) slope 1")
+
+		c, _ := GeneratorC()
+		_, err = ToPL(c)
+		assert.NotNil(t, err, "C is not in F.")
+	})
+
+	t.Run("ToPLCircle test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		breaks, err := ToPLCircle(c)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, plString(breaks), "(0, 3/4) slope 1/2; (1/2, 1) slope 2; (3/4, 3/2) slope 1")
+
+		p, _ := GeneratorPi0()
+		_, err = ToPLCircle(p)
+		assert.NotNil(t, err, "pi0 is not in T.")
+	})
+
+	t.Run("FromPL test", func(t *testing.T) {
+		for _, word := range []string{"", "x0", "x1^-1", "x0^2 x1 x3^-2", "x0^-1 x1^2 x0 x4"} {
+			tp, _ := EvaluateWord("01", word)
+			breaks, err := ToPL(tp)
+			assert.Nil(t, err)
+			back, err := FromPL(breaks)
+			assert.Nil(t, err)
+			assert.True(t, EqualsAsElements(tp, back), word)
+		}
+
+		// the slope need not change at a breakpoint.
+		breaks := []Breakpoint{
+			{X: big.NewRat(0, 1), Y: big.NewRat(0, 1), Slope: big.NewRat(1, 2)},
+			{X: big.NewRat(1, 4), Y: big.NewRat(1, 8), Slope: big.NewRat(1, 2)},
+			{X: big.NewRat(1, 2), Y: big.NewRat(1, 4), Slope: big.NewRat(1, 1)},
+			{X: big.NewRat(3, 4), Y: big.NewRat(1, 2), Slope: big.NewRat(2, 1)},
+		}
+		tp, err := FromPL(breaks)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, tp.FullString(), "{D: [0 0], [10 1], [11 2] || R: [00 0], [01 1], [1 2]}")
+	})
+
+	t.Run("FromPL error test", func(t *testing.T) {
+		_, err := FromPL(nil)
+		assert.NotNil(t, err)
+
+		// does not reach (1, 1).
+		_, err = FromPL([]Breakpoint{{X: big.NewRat(0, 1), Y: big.NewRat(0, 1), Slope: big.NewRat(1, 2)}})
+		assert.NotNil(t, err)
+
+		// slope 3 is not a power of 2.
+		_, err = FromPL([]Breakpoint{
+			{X: big.NewRat(0, 1), Y: big.NewRat(0, 1), Slope: big.NewRat(1, 3)},
+			{X: big.NewRat(3, 4), Y: big.NewRat(1, 4), Slope: big.NewRat(3, 1)},
+		})
+		assert.NotNil(t, err)
+
+		// 1/3 is not dyadic.
+		_, err = FromPL([]Breakpoint{
+			{X: big.NewRat(0, 1), Y: big.NewRat(0, 1), Slope: big.NewRat(1, 1)},
+			{X: big.NewRat(1, 3), Y: big.NewRat(1, 3), Slope: big.NewRat(1, 1)},
+		})
+		assert.NotNil(t, err)
+
+		// missing fields are refused rather than read.
+		for _, bp := range []Breakpoint{
+			{Y: big.NewRat(0, 1), Slope: big.NewRat(1, 1)},
+			{X: big.NewRat(0, 1), Slope: big.NewRat(1, 1)},
+			{X: big.NewRat(0, 1), Y: big.NewRat(0, 1)},
+		} {
+			assert.NotPanics(t, func() {
+				_, err = FromPL([]Breakpoint{bp})
+			})
+			assert.NotNil(t, err)
+		}
+	})
+
+	t.Run("ToIntervalMap test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		var written []string
+		for _, p := range ToIntervalMap(x0) {
+			written = append(written, p.String())
+		}
+		assert.Equal(t, []string{
+			"[0, 1/2) -> [0, 1/4) slope 1/2",
+			"[1/2, 3/4) -> [1/4, 1/2) slope 1",
+			"[3/4, 1) -> [1/2, 1) slope 2",
+		}, written)
+
+		pi0, _ := GeneratorPi0()
+		pieces := ToIntervalMap(pi0)
+		assert.Equal(t, 3, len(pieces))
+		total := new(big.Rat)
+		for _, p := range pieces {
+			total.Add(total, new(big.Rat).Sub(p.ImageRight, p.ImageLeft))
+		}
+		assert.Equal(t, "1", total.RatString(), "the images fill [0,1).")
+
+		ternary, _ := NewTreePairAlpha("012")
+		pieces = ToIntervalMap(ternary)
+		assert.Equal(t, "[0, 1) -> [0, 1) slope 1", pieces[0].String())
+	})
+}
@@ -0,0 +1,33 @@
+package treepair
+
+import "sync"
+
+// Multiply and Minimise build leaf maps only to write them straight back into prefix codes.
+// Long chains of products would allocate and discard such maps at every step, so they are
+// taken from and given back to pools instead.
+var (
+	leafMapPool = sync.Pool{New: func() interface{} { return make(leafMap) }}
+	labelPool   = sync.Pool{New: func() interface{} { return make(map[int]string) }}
+)
+
+// pooledLeafMap returns the leaf map of tp in a map from the pool.  It must be handed back
+// with releaseLeafMap once it is no longer needed, and not kept anywhere.
+func pooledLeafMap(tp TreePair) leafMap {
+	m := leafMapPool.Get().(leafMap)
+	m.read(tp)
+	return m
+}
+
+// emptyPooledLeafMap returns an empty map from the pool, to be handed back as for
+// pooledLeafMap.
+func emptyPooledLeafMap() leafMap {
+	return leafMapPool.Get().(leafMap)
+}
+
+// releaseLeafMap empties m and gives it back to the pool.
+func releaseLeafMap(m leafMap) {
+	for k := range m {
+		delete(m, k)
+	}
+	leafMapPool.Put(m)
+}
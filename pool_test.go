@@ -0,0 +1,26 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPool(t *testing.T) {
+
+	t.Run("pooledLeafMap test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		c, _ := GeneratorC()
+		for k := 0; k < 4; k++ {
+			for _, tp := range []TreePair{x0, c, identityOver([]rune("01"))} {
+				m := pooledLeafMap(tp)
+				assert.Equal(t, newLeafMap(tp), m)
+				releaseLeafMap(m)
+				assert.Equal(t, 0, len(m))
+			}
+		}
+		product := Multiply(x0, c)
+		assert.Equal(t, "{10100,10100,1 2 0}", c.DFSString())
+		assert.Equal(t, product.DFSString(), Multiply(x0, c).DFSString())
+	})
+}
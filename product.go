@@ -0,0 +1,115 @@
+package treepair
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// checkFactors reports an error, naming the function caller, unless elems is a non-empty list
+// of elements over one alphabet.
+func checkFactors(caller string, elems []TreePair) error {
+	if 0 == len(elems) {
+		return errors.New(caller + "(): no elements to multiply")
+	}
+	alpha := string(elems[0].Alphabet())
+	for _, tp := range elems[1:] {
+		if alpha != string(tp.Alphabet()) {
+			return errors.New(caller + "(): elements are over different alphabets")
+		}
+	}
+	return nil
+}
+
+// MultiplyAllParallel returns the minimised product of elems, in order, as repeated Multiply
+// would.  The product is taken as a balanced tree: neighbouring elements are multiplied in
+// pairs, then neighbouring products, and so on, each round sharing its products among as many
+// goroutines as Go runs at once.  Multiplication is associative, so the result does not
+// depend on the bracketing, while the factors multiplied together stay of similar sizes.
+// elems itself is not modified.
+func MultiplyAllParallel(elems []TreePair) (TreePair, error) {
+	if err := checkFactors("MultiplyAllParallel", elems); nil != err {
+		return nil, err
+	}
+	products := make([]*treePair, len(elems))
+	for k, tp := range elems {
+		products[k] = clone(tp)
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	for len(products) > 1 {
+		next := make([]*treePair, (len(products)+1)/2)
+		pairs := make(chan int)
+		var wg sync.WaitGroup
+		for w := 0; w < workers && w < len(products)/2; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for k := range pairs {
+					next[k] = Multiply(products[2*k], products[2*k+1])
+				}
+			}()
+		}
+		for k := 0; k < len(products)/2; k++ {
+			pairs <- k
+		}
+		close(pairs)
+		wg.Wait()
+		if 1 == len(products)%2 {
+			next[len(next)-1] = products[len(products)-1]
+		}
+		products = next
+	}
+	products[0].Minimise()
+	return products[0], nil
+}
+
+// MultiplyAll returns the minimised product of elems, multiplied from left to right as
+// repeated Multiply would, minimising after each step.  Unlike Multiply it works on copies, so
+// elems are not modified.
+func MultiplyAll(elems ...TreePair) (TreePair, error) {
+	if err := checkFactors("MultiplyAll", elems); nil != err {
+		return nil, err
+	}
+	return multiplyAll(elems, 0)
+}
+
+// MultiplyAllWithBudget is MultiplyAll, but gives up with ErrBudgetExceeded, as
+// MultiplyWithBudget does, as soon as a factor or a partial product, before it is minimised,
+// has more than maxLeaves leaves.
+func MultiplyAllWithBudget(maxLeaves int, elems ...TreePair) (TreePair, error) {
+	if maxLeaves < 1 {
+		return nil, errors.New("MultiplyAllWithBudget(): budget of " + strconv.Itoa(maxLeaves) + " leaves is not positive")
+	}
+	if err := checkFactors("MultiplyAllWithBudget", elems); nil != err {
+		return nil, err
+	}
+	return multiplyAll(elems, maxLeaves)
+}
+
+// multiplyAll multiplies copies of elems from left to right, with a budget of maxLeaves leaves
+// if it is not 0.
+func multiplyAll(elems []TreePair, maxLeaves int) (TreePair, error) {
+	product := clone(elems[0])
+	if 0 != maxLeaves && product.Size() > maxLeaves {
+		return nil, ErrBudgetExceeded
+	}
+	product.Minimise()
+	for _, tp := range elems[1:] {
+		next, err := multiply(product, clone(tp), maxLeaves)
+		if nil != err {
+			return nil, err
+		}
+		product = next
+	}
+	return product, nil
+}
+
+// Divide returns the minimised product a b^-1, as Multiply composes, computed on copies so
+// that neither a nor b is modified.  a and b must be over the same alphabet.
+func Divide(a, b TreePair) TreePair {
+	inverse := clone(b)
+	inverse.Invert()
+	return Multiply(clone(a), inverse)
+}
@@ -0,0 +1,97 @@
+package treepair
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProduct(t *testing.T) {
+
+	t.Run("MultiplyAllParallel test", func(t *testing.T) {
+		src := rand.NewSource(7)
+		var elems []TreePair
+		for k := 0; k < 41; k++ {
+			tp, _ := RandomT(4, src)
+			elems = append(elems, tp)
+		}
+		before := make([]string, len(elems))
+		expected := identityOver([]rune("01"))
+		for k, tp := range elems {
+			before[k] = tp.FullString()
+			expected = Multiply(expected, clone(tp))
+		}
+		product, err := MultiplyAllParallel(elems)
+		assert.Nil(t, err)
+		assert.True(t, EqualsAsElements(expected, product))
+		for k, tp := range elems {
+			assert.Equal(t, before[k], tp.FullString(), "the elements are left alone.")
+		}
+
+		c, _ := GeneratorC()
+		product, err = MultiplyAllParallel([]TreePair{c})
+		assert.Nil(t, err)
+		assert.True(t, EqualsAsElements(c, product))
+		assert.NotSame(t, c, product)
+
+		_, err = MultiplyAllParallel(nil)
+		assert.NotNil(t, err)
+		ternary, _ := NewTreePairAlpha("012")
+		_, err = MultiplyAllParallel([]TreePair{c, ternary})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("MultiplyAll test", func(t *testing.T) {
+		a, _ := GeneratorA()
+		b, _ := GeneratorB()
+		c, _ := GeneratorC()
+		before := a.FullString()
+		product, err := MultiplyAll(a, b, a, c)
+		assert.Nil(t, err)
+		expected, _ := EvaluateWord("01", "A B A C")
+		assert.True(t, EqualsAsElements(expected, product))
+		assert.Equal(t, before, a.FullString(), "the elements are left alone.")
+		assert.True(t, product.IsMinimal())
+
+		a.ExpandDomainAt("0")
+		product, err = MultiplyAll(a)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, product.Size())
+
+		_, err = MultiplyAll()
+		assert.NotNil(t, err)
+		ternary, _ := NewTreePairAlpha("012")
+		_, err = MultiplyAll(a, ternary)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("MultiplyAllWithBudget test", func(t *testing.T) {
+		a, _ := GeneratorA()
+		inverse := clone(a)
+		inverse.Invert()
+		product, err := MultiplyAllWithBudget(4, a, a, inverse, inverse)
+		assert.Nil(t, err)
+		assert.True(t, product.IsIdentity())
+
+		_, err = MultiplyAllWithBudget(4, a, a, a, a)
+		assert.Equal(t, ErrBudgetExceeded, err)
+		_, err = MultiplyAllWithBudget(2, a)
+		assert.Equal(t, ErrBudgetExceeded, err)
+		_, err = MultiplyAllWithBudget(0, a)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Divide test", func(t *testing.T) {
+		a, _ := GeneratorA()
+		b, _ := GeneratorB()
+		beforeA, beforeB := a.FullString(), b.FullString()
+		quotient := Divide(a, b)
+		expected, _ := EvaluateWord("01", "A B^-1")
+		assert.True(t, EqualsAsElements(expected, quotient))
+		assert.Equal(t, beforeA, a.FullString(), "a is left alone.")
+		assert.Equal(t, beforeB, b.FullString(), "b is left alone.")
+		assert.True(t, Divide(b, b).IsIdentity())
+		assert.True(t, EqualsAsElements(a, Multiply(Divide(a, b), clone(b))))
+	})
+}
@@ -0,0 +1,78 @@
+package treepair
+
+import (
+	"errors"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// RandomF returns a random element of R. Thompson's group F over "01": a pair of binary trees
+// with leaves leaves, each drawn uniformly from all such trees, with the trivial permutation.
+// The pair need not be reduced.
+func RandomF(leaves int, src rand.Source) (TreePair, error) {
+	if leaves < 1 {
+		return nil, errors.New("RandomF(): trees need at least one leaf, not " + strconv.Itoa(leaves))
+	}
+	r := rand.New(src)
+	labels := make([]int, leaves)
+	for k := range labels {
+		labels[k] = k
+	}
+	return randomPair(randomTreeShape(leaves, r), randomTreeShape(leaves, r), labels)
+}
+
+// RandomT returns a random element of R. Thompson's group T over "01": a pair of binary trees
+// with leaves leaves, each drawn uniformly from all such trees, with the leaves carried round
+// by a rotation drawn uniformly from the leaves rotations.  The pair need not be reduced.
+func RandomT(leaves int, src rand.Source) (TreePair, error) {
+	if leaves < 1 {
+		return nil, errors.New("RandomT(): trees need at least one leaf, not " + strconv.Itoa(leaves))
+	}
+	r := rand.New(src)
+	dom, ran := randomTreeShape(leaves, r), randomTreeShape(leaves, r)
+	shift := r.Intn(leaves)
+	labels := make([]int, leaves)
+	for k := range labels {
+		labels[k] = (k + shift) % leaves
+	}
+	return randomPair(dom, ran, labels)
+}
+
+// randomTreeShape returns the DFS string of a binary tree with leaves leaves drawn uniformly
+// at random.  A shuffle of leaves-1 carets "1" and leaves leaves "0" has exactly one cyclic
+// rotation which is a DFS string (the cycle lemma): the one starting just after the first
+// place where the number of leaves read most exceeds the number of carets.
+func randomTreeShape(leaves int, r *rand.Rand) string {
+	symbols := []byte(strings.Repeat("1", leaves-1) + strings.Repeat("0", leaves))
+	r.Shuffle(len(symbols), func(i, j int) { symbols[i], symbols[j] = symbols[j], symbols[i] })
+	start, height, lowest := 0, 0, 0
+	for k, v := range symbols {
+		if '1' == v {
+			height++
+		} else {
+			height--
+		}
+		if height < lowest {
+			start, lowest = k+1, height
+		}
+	}
+	return string(symbols[start:]) + string(symbols[:start])
+}
+
+// randomPair builds the tree pair over "01" with the given DFS strings, carrying the k-th
+// domain leaf to the range leaf labelled labels[k].
+func randomPair(dom, ran string, labels []int) (TreePair, error) {
+	if "0" == dom {
+		return identityOver([]rune("01")), nil
+	}
+	perm := make([]string, len(labels))
+	for k, v := range labels {
+		perm[k] = strconv.Itoa(v)
+	}
+	tp := identityOver([]rune("01"))
+	if !EncodeDFS(tp, "{"+dom+","+ran+","+strings.Join(perm, " ")+"}") {
+		return nil, errors.New("randomPair(): could not encode " + dom + " and " + ran)
+	}
+	return tp, nil
+}
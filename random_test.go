@@ -0,0 +1,62 @@
+package treepair
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/loeksnokes/prefcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandom(t *testing.T) {
+
+	t.Run("randomTreeShape test", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		count := map[string]int{}
+		for k := 0; k < 1400; k++ {
+			shape := randomTreeShape(4, r)
+			assert.True(t, prefcode.ValidDFSForPrefC(2, shape), shape)
+			count[shape]++
+		}
+		// the five binary trees with four leaves turn up about equally often.
+		assert.Equal(t, 5, len(count))
+		for shape, n := range count {
+			assert.True(t, n > 200 && n < 360, shape)
+		}
+	})
+
+	t.Run("RandomF test", func(t *testing.T) {
+		src := rand.NewSource(7)
+		for leaves := 1; leaves < 12; leaves++ {
+			tp, err := RandomF(leaves, src)
+			assert.Nil(t, err)
+			assert.Equal(t, leaves, tp.Size())
+			assert.True(t, tp.InF())
+		}
+		_, err := RandomF(0, src)
+		assert.NotNil(t, err)
+
+		a, _ := RandomF(20, rand.NewSource(3))
+		b, _ := RandomF(20, rand.NewSource(3))
+		assert.Equal(t, a.FullString(), b.FullString())
+	})
+
+	t.Run("RandomT test", func(t *testing.T) {
+		src := rand.NewSource(11)
+		rotations := map[int]int{}
+		for k := 0; k < 400; k++ {
+			tp, err := RandomT(4, src)
+			assert.Nil(t, err)
+			assert.Equal(t, 4, tp.Size())
+			assert.True(t, tp.InT())
+			rotations[tp.CodeRange().LabelAtLeaf(sortedLeaves(tp.CodeRange())[0])]++
+		}
+		// the leftmost range leaf is the image of each domain leaf about equally often.
+		assert.Equal(t, 4, len(rotations))
+		for k, n := range rotations {
+			assert.True(t, n > 60 && n < 140, k)
+		}
+		_, err := RandomT(0, src)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,44 @@
+package treepair
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ScanElements reads elements over the alphabet alphaStr from r, one per line in DFS or Full
+// notation (see ParseElement), calling visit on each in turn until it returns false.  Blank
+// lines and lines starting with # are skipped.  Errors name the offending line.
+func ScanElements(alphaStr string, r io.Reader, visit func(tp TreePair) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<24)
+	for line := 1; scanner.Scan(); line++ {
+		s := strings.TrimSpace(scanner.Text())
+		if "" == s || strings.HasPrefix(s, "#") {
+			continue
+		}
+		tp, err := ParseElement(alphaStr, s)
+		if nil != err {
+			return errors.New("line " + strconv.Itoa(line) + ": " + err.Error())
+		}
+		if !visit(tp) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// ReadElements reads all the elements over the alphabet alphaStr from r, as ScanElements does.
+func ReadElements(alphaStr string, r io.Reader) ([]TreePair, error) {
+	var elements []TreePair
+	err := ScanElements(alphaStr, r, func(tp TreePair) bool {
+		elements = append(elements, tp)
+		return true
+	})
+	if nil != err {
+		return nil, err
+	}
+	return elements, nil
+}
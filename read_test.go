@@ -0,0 +1,42 @@
+package treepair
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRead(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	input := "# C and x0\n{10100,10100,1 2 0}\n\n  {D: [0 0], [10 1], [11 2] || R: [00 0], [01 1], [1 2]}  \n{0,0,0}\n"
+
+	t.Run("ReadElements test", func(t *testing.T) {
+		elements, err := ReadElements("01", strings.NewReader(input))
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(elements))
+		assertCorrectMessage(t, elements[0].DFSString(), "{10100,10100,1 2 0}")
+		assertCorrectMessage(t, elements[1].DFSString(), "{10100,11000,0 1 2}")
+		assert.True(t, elements[2].IsIdentity())
+
+		_, err = ReadElements("01", strings.NewReader("{0,0,0}\n{10100,10100,1 2}\n"))
+		assertCorrectMessage(t, err.Error(), "line 2: ParseElement(): {10100,10100,1 2} is not a DFS string over 01")
+	})
+
+	t.Run("ScanElements test", func(t *testing.T) {
+		visited := 0
+		err := ScanElements("01", strings.NewReader(input+"not an element\n"), func(tp TreePair) bool {
+			visited++
+			return visited < 2
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, visited)
+	})
+}
@@ -0,0 +1,109 @@
+package treepair
+
+import (
+	"errors"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Reencode returns the image of tp under the standard identification of Cantor sets which
+// writes each block of k letters over an alphabet of size n as one letter over an alphabet of
+// size n^k, in dictionary order.  newAlphaSize is either n^k, blocking the letters of tp, or
+// the k-th root of n, unblocking them.  The result is over the first newAlphaSize of the
+// letters "0", ..., "9", "A", ..., "Z", "a", ..., "z".
+//
+// Unblocking always succeeds.  Blocking succeeds exactly when each pair d -> r of the minimal
+// tree pair changes the depth by a multiple of k, as expanding a pair never changes that;
+// the pairs are then expanded until every depth is a multiple of k.  tp is not modified.
+func Reencode(tp TreePair, newAlphaSize int) (*treePair, error) {
+	alpha := tp.Alphabet()
+	if newAlphaSize < 2 || newAlphaSize > len(standInLetters) {
+		return nil, errors.New("Reencode(): alphabet size " + strconv.Itoa(newAlphaSize) + " is not between 2 and " + strconv.Itoa(len(standInLetters)))
+	}
+	newAlpha := []rune(standInLetters[:newAlphaSize])
+	index := make(map[rune]int, len(alpha))
+	for k, v := range alpha {
+		index[v] = k
+	}
+	m := newLeafMap(canonicalCopy(tp))
+	recoded := make(leafMap, len(m))
+
+	if k := blockLength(len(alpha), newAlphaSize); k > 0 {
+		for d, r := range m {
+			dLen, rLen := utf8.RuneCountInString(d), utf8.RuneCountInString(r)
+			if 0 != (dLen-rLen)%k {
+				return nil, errors.New("Reencode(): " + emptyAsRoot(d) + " -> " + emptyAsRoot(r) + " changes the depth by " + strconv.Itoa(rLen-dLen) + ", not a multiple of " + strconv.Itoa(k))
+			}
+			for _, w := range wordsOfLength(alpha, (k-dLen%k)%k) {
+				recoded[block(index, newAlpha, k, d+w)] = block(index, newAlpha, k, r+w)
+			}
+		}
+	} else if k := blockLength(newAlphaSize, len(alpha)); k > 0 {
+		for d, r := range m {
+			recoded[unblock(index, newAlpha, k, d)] = unblock(index, newAlpha, k, r)
+		}
+	} else {
+		return nil, errors.New("Reencode(): alphabet sizes " + strconv.Itoa(len(alpha)) + " and " + strconv.Itoa(newAlphaSize) + " are not powers of one another")
+	}
+
+	answer := identityOver(newAlpha)
+	answer.setLeafMap(recoded)
+	return answer, nil
+}
+
+// blockLength returns the k >= 1 with n^k == size, or 0 if there is none.
+func blockLength(n, size int) int {
+	power := n
+	for k := 1; power <= size; k++ {
+		if power == size {
+			return k
+		}
+		power *= n
+	}
+	return 0
+}
+
+// wordsOfLength lists the words of length j over alpha in dictionary order.
+func wordsOfLength(alpha []rune, j int) []string {
+	words := []string{""}
+	for ; j > 0; j-- {
+		longer := make([]string, 0, len(words)*len(alpha))
+		for _, w := range words {
+			for _, c := range alpha {
+				longer = append(longer, w+string(c))
+			}
+		}
+		words = longer
+	}
+	return words
+}
+
+// block writes w, whose length is a multiple of k, with a letter of newAlpha for each block
+// of k letters, read as the digits of its position in newAlpha.
+func block(index map[rune]int, newAlpha []rune, k int, w string) string {
+	var letters []rune
+	digit, position := 0, 0
+	for _, c := range w {
+		position = position*len(index) + index[c]
+		if digit++; k == digit {
+			letters = append(letters, newAlpha[position])
+			digit, position = 0, 0
+		}
+	}
+	return string(letters)
+}
+
+// unblock writes each letter of w as the k letters of newAlpha giving its position as digits.
+func unblock(index map[rune]int, newAlpha []rune, k int, w string) string {
+	letters := make([]rune, 0, k*utf8.RuneCountInString(w))
+	digits := make([]rune, k)
+	for _, c := range w {
+		position := index[c]
+		for j := k - 1; j >= 0; j-- {
+			digits[j] = newAlpha[position%len(newAlpha)]
+			position /= len(newAlpha)
+		}
+		letters = append(letters, digits...)
+	}
+	return string(letters)
+}
@@ -0,0 +1,59 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReencode(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	// the swap of 0 and 1 is the quaternary 0 -> 2, 1 -> 3, 2 -> 0, 3 -> 1.
+	t.Run("Reencode blocking test", func(t *testing.T) {
+		swap, _ := ParseElement("01", "{100,100,1 0}")
+		four, err := Reencode(swap, 4)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, four.FullString(), "{D: [0 0], [1 1], [2 2], [3 3] || R: [0 2], [1 3], [2 0], [3 1]}")
+		eight, err := Reencode(swap, 8)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, eight.DFSString(), "{100000000,100000000,4 5 6 7 0 1 2 3}")
+
+		back, err := Reencode(four, 2)
+		assert.Nil(t, err)
+		assert.True(t, EqualsAsElements(back, swap))
+		assertCorrectMessage(t, swap.DFSString(), "{100,100,1 0}")
+
+		x0, _ := GeneratorX(0)
+		_, err = Reencode(x0, 4)
+		assert.NotNil(t, err)
+		_, err = Reencode(x0, 6)
+		assert.NotNil(t, err)
+		_, err = Reencode(x0, 1)
+		assert.NotNil(t, err)
+	})
+
+	// unblocking then blocking again gives back the minimal tree pair, and unblocking is a
+	// homomorphism.
+	t.Run("Reencode unblocking test", func(t *testing.T) {
+		c, _ := ParseElement("0123", "{10000,10000,1 2 3 0}")
+		x0, _ := ParseElement("0123", "{110000000,100010000,0 1 2 3 4 5 6}")
+		for _, g := range []TreePair{c, x0, Multiply(Power(c, 1), Power(x0, 1))} {
+			binary, err := Reencode(g, 2)
+			assert.Nil(t, err)
+			again, err := Reencode(binary, 4)
+			assert.Nil(t, err)
+			assertCorrectMessage(t, again.DFSString(), canonicalCopy(g).DFSString())
+		}
+		cx, _ := Reencode(Multiply(Power(c, 1), Power(x0, 1)), 2)
+		c2, _ := Reencode(c, 2)
+		x2, _ := Reencode(x0, 2)
+		assert.True(t, EqualsAsElements(cx, Multiply(c2, x2)))
+	})
+}
@@ -0,0 +1,247 @@
+package treepair
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/loeksnokes/prefcode"
+)
+
+// renderNode is a vertex of a drawn tree: its word, its place in a grid with the leaves one
+// unit apart on the bottom row and the root at depth 0, and, for a leaf, its label.
+type renderNode struct {
+	word  string
+	x     float64
+	depth int
+	label string
+}
+
+// renderTree lays out the tree whose leaves are the keys of labels, returning its vertices in
+// depth first order and the edges between them as indices.  Each internal vertex sits above
+// the middle of its first and last children.
+func renderTree(alpha []rune, labels map[string]string) (nodes []renderNode, edges [][2]int) {
+	next := 0.0
+	var place func(w string, depth int) int
+	place = func(w string, depth int) int {
+		k := len(nodes)
+		nodes = append(nodes, renderNode{word: w, depth: depth})
+		if label, leaf := labels[w]; leaf {
+			nodes[k].x, nodes[k].label = next, label
+			next++
+			return k
+		}
+		var first, last int
+		for i, c := range alpha {
+			edges = append(edges, [2]int{k, len(nodes)})
+			child := place(w+string(c), depth+1)
+			if 0 == i {
+				first = child
+			}
+			last = child
+		}
+		nodes[k].x = (nodes[first].x + nodes[last].x) / 2
+		return k
+	}
+	place("", 0)
+	return nodes, edges
+}
+
+// renderTrees lays out the domain and range trees of the minimised tp, labelling the k-th
+// domain leaf and its image by k.
+func renderTrees(tp TreePair) (dom, ran []renderNode, domEdges, ranEdges [][2]int) {
+	min := clone(tp)
+	min.Minimise()
+	domLabels, ranLabels := map[string]string{}, map[string]string{}
+	for k, v := range leafPairs(min) {
+		domLabels[rootAsEmpty(v[0])] = strconv.Itoa(k)
+		ranLabels[rootAsEmpty(v[1])] = strconv.Itoa(k)
+	}
+	dom, domEdges = renderTree(min.Alphabet(), domLabels)
+	ran, ranEdges = renderTree(min.Alphabet(), ranLabels)
+	return
+}
+
+// formatFloat writes x briefly.
+func formatFloat(x float64) string {
+	return strconv.FormatFloat(x, 'f', -1, 64)
+}
+
+// RenderDOT draws the minimised tp in the Graphviz DOT language, as the domain and range trees
+// side by side with matching leaf labels.  tp itself is not modified.
+func RenderDOT(tp TreePair) string {
+	dom, ran, domEdges, ranEdges := renderTrees(tp)
+	var b strings.Builder
+	b.WriteString("digraph treepair {\n\tnode [shape=point];\n\tedge [arrowhead=none];\n")
+	for _, side := range []struct {
+		name  string
+		nodes []renderNode
+		edges [][2]int
+	}{{"D", dom, domEdges}, {"R", ran, ranEdges}} {
+		b.WriteString("\tsubgraph cluster_" + side.name + " {\n\t\tlabel=" + strconv.Quote(side.name) + ";\n")
+		for k, v := range side.nodes {
+			b.WriteString("\t\t" + side.name + strconv.Itoa(k))
+			if "" != v.label {
+				b.WriteString(" [shape=plaintext, label=" + strconv.Quote(v.label) + "]")
+			}
+			b.WriteString(";\n")
+		}
+		for _, e := range side.edges {
+			b.WriteString("\t\t" + side.name + strconv.Itoa(e[0]) + " -> " + side.name + strconv.Itoa(e[1]) + ";\n")
+		}
+		b.WriteString("\t}\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// RenderSVG draws the minimised tp as an SVG image, the domain tree on the left, an arrow, and
+// the range tree on the right, with matching leaf labels.  tp itself is not modified.
+func RenderSVG(tp TreePair) string {
+	dom, ran, domEdges, ranEdges := renderTrees(tp)
+	const unit, margin = 30.0, 20.0
+	width := func(nodes []renderNode) float64 {
+		most := 0.0
+		for _, v := range nodes {
+			if v.x > most {
+				most = v.x
+			}
+		}
+		return most * unit
+	}
+	height := 0
+	for _, v := range append(append([]renderNode(nil), dom...), ran...) {
+		if v.depth > height {
+			height = v.depth
+		}
+	}
+	ranLeft := margin + width(dom) + 3*unit
+	totalWidth := ranLeft + width(ran) + margin
+	totalHeight := 2*margin + float64(height)*unit + unit/2
+
+	var b strings.Builder
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="` + formatFloat(totalWidth) + `" height="` + formatFloat(totalHeight) + `">` + "\n")
+	draw := func(nodes []renderNode, edges [][2]int, left float64) {
+		at := func(v renderNode) (string, string) {
+			return formatFloat(left + v.x*unit), formatFloat(margin + float64(v.depth)*unit)
+		}
+		for _, e := range edges {
+			x1, y1 := at(nodes[e[0]])
+			x2, y2 := at(nodes[e[1]])
+			b.WriteString(`  <line x1="` + x1 + `" y1="` + y1 + `" x2="` + x2 + `" y2="` + y2 + `" stroke="black"/>` + "\n")
+		}
+		for _, v := range nodes {
+			if "" == v.label {
+				continue
+			}
+			x, _ := at(v)
+			y := formatFloat(margin + float64(v.depth)*unit + unit/2)
+			b.WriteString(`  <text x="` + x + `" y="` + y + `" text-anchor="middle" font-size="12">` + v.label + `</text>` + "\n")
+		}
+	}
+	draw(dom, domEdges, margin)
+	arrowY := formatFloat(margin + float64(height)*unit/2)
+	b.WriteString(`  <text x="` + formatFloat(ranLeft-1.5*unit) + `" y="` + arrowY + `" text-anchor="middle" font-size="16">&#8594;</text>` + "\n")
+	draw(ran, ranEdges, ranLeft)
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// RenderTikZ draws the minimised tp as a TikZ picture for LaTeX, the domain tree on the left
+// and the range tree on the right, with matching leaf labels.  tp itself is not modified.
+func RenderTikZ(tp TreePair) string {
+	dom, ran, domEdges, ranEdges := renderTrees(tp)
+	ranLeft := 0.0
+	for _, v := range dom {
+		if v.x+3 > ranLeft {
+			ranLeft = v.x + 3
+		}
+	}
+	var b strings.Builder
+	b.WriteString("\\begin{tikzpicture}[scale=0.5]\n")
+	draw := func(nodes []renderNode, edges [][2]int, left float64) {
+		at := func(v renderNode) string {
+			return "(" + formatFloat(left+v.x) + "," + strconv.Itoa(-v.depth) + ")"
+		}
+		for _, e := range edges {
+			b.WriteString("  \\draw " + at(nodes[e[0]]) + " -- " + at(nodes[e[1]]) + ";\n")
+		}
+		for _, v := range nodes {
+			if "" != v.label {
+				b.WriteString("  \\node[below] at " + at(v) + " {$" + v.label + "$};\n")
+			}
+		}
+	}
+	draw(dom, domEdges, 0)
+	b.WriteString("  \\node at (" + formatFloat(ranLeft-1.5) + ",-1) {$\\longrightarrow$};\n")
+	draw(ran, ranEdges, ranLeft)
+	b.WriteString("\\end{tikzpicture}\n")
+	return b.String()
+}
+
+// ToLaTeXTable writes the prefix map of tp as a LaTeX tabular, for inclusion in a paper: a
+// header row and then a row for each domain leaf in dictionary order, with its label and the
+// range leaf it is carried to, as WriteCSV lists them.  The root is written $\varepsilon$.
+// tp itself is not modified, nor minimised, so that the table shows the trees as given.
+func ToLaTeXTable(tp TreePair) string {
+	leaf := func(w string) string {
+		if w = rootAsEmpty(w); "" == w {
+			return "$\\varepsilon$"
+		}
+		return "$" + w + "$"
+	}
+	var b strings.Builder
+	b.WriteString("\\begin{tabular}{lcl}\n")
+	b.WriteString("  domain leaf & label & range leaf \\\\\n")
+	b.WriteString("  \\hline\n")
+	tp.Pairs(func(domainLeaf, rangeLeaf string) bool {
+		b.WriteString("  " + leaf(domainLeaf) + " & " + strconv.Itoa(tp.CodeDomain().LabelAtLeaf(domainLeaf)) + " & " + leaf(rangeLeaf) + " \\\\\n")
+		return true
+	})
+	b.WriteString("\\end{tabular}\n")
+	return b.String()
+}
+
+// RenderASCII draws the minimised tp as plain text: the domain tree and then the range tree,
+// each as an outline with one vertex per line and the labels of the leaves in brackets.  tp
+// itself is not modified.
+func RenderASCII(tp TreePair) string {
+	dom, ran, _, _ := renderTrees(tp)
+	var b strings.Builder
+	for _, side := range []struct {
+		name  string
+		nodes []renderNode
+	}{{"D", dom}, {"R", ran}} {
+		b.WriteString(side.name + ":\n")
+		for _, v := range side.nodes {
+			word := v.word
+			if "" == word {
+				word = prefcode.EmptyString
+			}
+			b.WriteString(strings.Repeat("|  ", v.depth) + "+-- " + word)
+			if "" != v.label {
+				b.WriteString(" [" + v.label + "]")
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// RenderFormats lists the formats accepted by Render.
+var RenderFormats = []string{"ascii", "dot", "svg", "tikz"}
+
+// Render draws tp in the named format, one of RenderFormats.
+func Render(tp TreePair, format string) (string, error) {
+	switch format {
+	case "ascii":
+		return RenderASCII(tp), nil
+	case "dot":
+		return RenderDOT(tp), nil
+	case "svg":
+		return RenderSVG(tp), nil
+	case "tikz":
+		return RenderTikZ(tp), nil
+	}
+	return "", errors.New("Render(): unknown format " + format + ", not one of " + strings.Join(RenderFormats, " "))
+}
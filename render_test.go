@@ -0,0 +1,71 @@
+package treepair
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	x0, _ := GeneratorX(0)
+
+	t.Run("RenderASCII test", func(t *testing.T) {
+		want := "D:\n+-- 𝛆\n|  +-- 0 [0]\n|  +-- 1\n|  |  +-- 10 [1]\n|  |  +-- 11 [2]\n" +
+			"R:\n+-- 𝛆\n|  +-- 0\n|  |  +-- 00 [0]\n|  |  +-- 01 [1]\n|  +-- 1 [2]\n"
+		assertCorrectMessage(t, RenderASCII(x0), want)
+		assertCorrectMessage(t, RenderASCII(identityOver([]rune("01"))), "D:\n+-- 𝛆 [0]\nR:\n+-- 𝛆 [0]\n")
+	})
+
+	t.Run("RenderDOT test", func(t *testing.T) {
+		got := RenderDOT(x0)
+		assert.True(t, strings.HasPrefix(got, "digraph treepair {\n"))
+		assert.Equal(t, 8, strings.Count(got, " -> "))
+		assert.Contains(t, got, "\t\tD0 -> D1;\n\t\tD0 -> D2;\n\t\tD2 -> D3;\n\t\tD2 -> D4;\n")
+		assert.Contains(t, got, "\t\tR4 [shape=plaintext, label=\"2\"];\n")
+	})
+
+	t.Run("RenderSVG test", func(t *testing.T) {
+		got := RenderSVG(x0)
+		assert.True(t, strings.HasPrefix(got, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"250\" height=\"115\">\n"))
+		assert.Equal(t, 8, strings.Count(got, "<line "))
+		assert.Equal(t, 7, strings.Count(got, "<text "))
+		assert.Contains(t, got, "  <line x1=\"42.5\" y1=\"20\" x2=\"20\" y2=\"50\" stroke=\"black\"/>\n")
+	})
+
+	t.Run("RenderTikZ test", func(t *testing.T) {
+		got := RenderTikZ(x0)
+		assert.True(t, strings.HasPrefix(got, "\\begin{tikzpicture}[scale=0.5]\n  \\draw (0.75,0) -- (0,-1);\n"))
+		assert.Equal(t, 8, strings.Count(got, "\\draw "))
+		assert.Contains(t, got, "  \\node[below] at (7,-1) {$2$};\n")
+	})
+
+	t.Run("ToLaTeXTable test", func(t *testing.T) {
+		want := "\\begin{tabular}{lcl}\n  domain leaf & label & range leaf \\\\\n  \\hline\n" +
+			"  $0$ & 0 & $00$ \\\\\n  $10$ & 1 & $01$ \\\\\n  $11$ & 2 & $1$ \\\\\n\\end{tabular}\n"
+		assertCorrectMessage(t, ToLaTeXTable(x0), want)
+		assert.Contains(t, ToLaTeXTable(identityOver([]rune("01"))), "  $\\varepsilon$ & 0 & $\\varepsilon$ \\\\\n")
+	})
+
+	// the pictures are of the minimised element.
+	t.Run("Render test", func(t *testing.T) {
+		big := clone(x0)
+		big.ExpandDomainAt("0")
+		for _, format := range RenderFormats {
+			got, err := Render(big, format)
+			assert.Nil(t, err)
+			want, _ := Render(x0, format)
+			assertCorrectMessage(t, got, want)
+		}
+		_, err := Render(x0, "png")
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,144 @@
+package treepair
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// RevealingSearchLimit bounds the number of expansions of the minimised pair examined by
+// Revealing.
+var RevealingSearchLimit = 4096
+
+// RevealingOrbit describes the repeller or attractor in one component of a revealing pair.
+// Root is the root of the component and Leaf the leaf of the component ending the iterated
+// augmentation chain from Root, Period steps later.  Leaf is Root u for a nonempty word u,
+// and the repelling or attracting periodic point is Root u u u ....
+type RevealingOrbit struct {
+	Root, Leaf string
+	Period     int
+}
+
+// RevealingData is the dynamical information carried by a revealing pair (A, B).
+type RevealingData struct {
+	// Repellers has one orbit per component of A-B, following the chain backwards.
+	Repellers []RevealingOrbit
+	// Attractors has one orbit per component of B-A, following the chain forwards.
+	Attractors []RevealingOrbit
+	// Periodic lists the cycles of leaves common to A and B that the element carries round
+	// and round, so that a power of the element fixes their cones pointwise.
+	Periodic [][]string
+}
+
+// Revealing returns a revealing pair for tp, together with its repellers, attractors and
+// periodic cycles, following Brin.  For a pair (A, B), a component of A-B is a subtree of A
+// hanging below a leaf x of B.  Pulling x back through leaves common to A and B gives the
+// iterated augmentation chain of x, which ends at a leaf of A that is not a leaf of B.  The
+// component contains a repeller if the chain ends inside it, and dually a component of B-A
+// contains an attractor if the forward chain from its root ends inside it.  The pair is
+// revealing if every component contains a repeller or an attractor.  Expansions of the
+// minimised pair at the leaves along failing chains are tried in order of size, and an error
+// is returned if none of the first RevealingSearchLimit of them is revealing.  Words are
+// written with "" for the root.  tp itself is not modified.
+func Revealing(tp TreePair) (TreePair, RevealingData, error) {
+	alpha := tp.Alphabet()
+	min := clone(tp)
+	min.Minimise()
+
+	start := newLeafMap(min)
+	queue := []leafMap{start}
+	seen := map[string]bool{start.key(): true}
+	for examined := 0; len(queue) > 0 && examined < RevealingSearchLimit; examined++ {
+		m := queue[0]
+		queue = queue[1:]
+		data, suspects := m.revealingData()
+		if 0 == len(suspects) {
+			return m.treePair(alpha), data, nil
+		}
+		for _, a := range suspects {
+			next := m.expandAt(alpha, a)
+			if k := next.key(); !seen[k] {
+				seen[k] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil, RevealingData{}, errors.New("Revealing(): no revealing pair found within " + strconv.Itoa(RevealingSearchLimit) + " expansions")
+}
+
+// hasLeafBelow reports whether some word of leaves is a proper extension of w.
+func hasLeafBelow(leaves map[string]string, w string) bool {
+	for k := range leaves {
+		if len(k) > len(w) && strings.HasPrefix(k, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// revealingData follows the iterated augmentation chains of the pair.  It returns the data
+// of the pair and, if the pair is not revealing, the domain leaves along the failing chains.
+func (m leafMap) revealingData() (data RevealingData, suspects []string) {
+	inverse := make(map[string]string, len(m))
+	for k, v := range m {
+		inverse[v] = k
+	}
+	domain := sortedKeys(m)
+	rangeLeaves := sortedKeys(inverse)
+
+	// components of A-B hang below leaves x of B which are internal in A.
+	for _, x := range rangeLeaves {
+		if _, ok := m[x]; ok || !hasLeafBelow(m, x) {
+			continue
+		}
+		var chain []string
+		a := inverse[x]
+		chain = append(chain, a)
+		for _, neutral := inverse[a]; neutral; _, neutral = inverse[a] {
+			a = inverse[a]
+			chain = append(chain, a)
+		}
+		if len(a) > len(x) && strings.HasPrefix(a, x) {
+			data.Repellers = append(data.Repellers, RevealingOrbit{Root: x, Leaf: a, Period: len(chain)})
+			continue
+		}
+		suspects = append(suspects, chain...)
+	}
+
+	// components of B-A hang below leaves y of A which are internal in B.
+	for _, y := range domain {
+		if _, ok := inverse[y]; ok || !hasLeafBelow(inverse, y) {
+			continue
+		}
+		chain := []string{y}
+		b := m[y]
+		for _, neutral := m[b]; neutral; _, neutral = m[b] {
+			chain = append(chain, b)
+			b = m[b]
+		}
+		if len(b) > len(y) && strings.HasPrefix(b, y) {
+			data.Attractors = append(data.Attractors, RevealingOrbit{Root: y, Leaf: b, Period: len(chain)})
+			continue
+		}
+		suspects = append(suspects, chain...)
+	}
+
+	// cycles of leaves common to A and B, each listed from its least leaf.
+	for _, n := range domain {
+		if _, ok := inverse[n]; !ok {
+			continue
+		}
+		cycle := []string{n}
+		for b := m[n]; b != n; b = m[b] {
+			if _, neutral := m[b]; !neutral || b < n {
+				cycle = nil
+				break
+			}
+			cycle = append(cycle, b)
+		}
+		if nil != cycle {
+			data.Periodic = append(data.Periodic, cycle)
+		}
+	}
+	return data, suspects
+}
@@ -0,0 +1,72 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevealing(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	t.Run("Revealing test", func(t *testing.T) {
+		// x0 repels from 1 and attracts to 0, and its minimal pair already shows it.
+		x0, _ := GeneratorX(0)
+		tp, data, err := Revealing(x0)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, tp.FullString(), x0.FullString())
+		assert.Equal(t, []RevealingOrbit{{Root: "1", Leaf: "11", Period: 1}}, data.Repellers)
+		assert.Equal(t, []RevealingOrbit{{Root: "0", Leaf: "00", Period: 1}}, data.Attractors)
+		assert.Equal(t, 0, len(data.Periodic))
+
+		b, _ := GeneratorB()
+		_, data, err = Revealing(b)
+		assert.Nil(t, err)
+		assert.Equal(t, [][]string{{"0"}}, data.Periodic)
+	})
+
+	// in the minimal pair of A pi0 B the leaf 10 of the domain is carried out of its
+	// component of B-A, so the pair has to be expanded there.
+	t.Run("Revealing expansion test", func(t *testing.T) {
+		tp, _ := EvaluateWord("01", "A pi0 B")
+		revealing, data, err := Revealing(tp)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, revealing.FullString(),
+			"{D: [0 0], [100 1], [101 2], [1100 3], [1101 4], [111 5] || R: [00 0], [010 1], [011 2], [100 5], [101 3], [11 4]}")
+		assert.True(t, EqualsAsElements(revealing, tp))
+		assert.Equal(t, []RevealingOrbit{{Root: "11", Leaf: "1101", Period: 1}}, data.Repellers)
+		assert.Equal(t, []RevealingOrbit{{Root: "0", Leaf: "00", Period: 1}}, data.Attractors)
+
+		// C B has order 5, which only shows once its five leaves are laid out in one cycle.
+		tp, _ = EvaluateWord("01", "C B")
+		_, data, err = Revealing(tp)
+		assert.Nil(t, err)
+		assert.Equal(t, [][]string{{"00", "101", "01", "11", "100"}}, data.Periodic)
+		assert.Equal(t, 0, len(data.Repellers)+len(data.Attractors))
+	})
+
+	// the chains of a revealing pair are orbits of the element.
+	t.Run("Revealing orbit test", func(t *testing.T) {
+		for _, word := range []string{"x0^2 pi0 x1^-1", "C^-1 A B pi0 B", "A^-2 B C pi0"} {
+			tp, _ := EvaluateWord("01", word)
+			_, data, err := Revealing(tp)
+			assert.Nil(t, err)
+			for _, o := range data.Attractors {
+				image, err := ImageOfWord(Power(tp, o.Period), o.Root)
+				assert.Nil(t, err)
+				assertCorrectMessage(t, image, o.Leaf)
+			}
+			for _, o := range data.Repellers {
+				image, err := ImageOfWord(Power(tp, -o.Period), o.Root)
+				assert.Nil(t, err)
+				assertCorrectMessage(t, image, o.Leaf)
+			}
+		}
+	})
+}
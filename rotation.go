@@ -0,0 +1,249 @@
+package treepair
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/loeksnokes/prefcode"
+)
+
+// wordInterval returns the left end and the width of the subinterval of [0,1) addressed by
+// the word w, reading the letters of alpha (in rune order) as base len(alpha) digits.
+func wordInterval(alpha []rune, w string) (left, width *big.Rat) {
+	left = new(big.Rat)
+	width = big.NewRat(1, 1)
+	if prefcode.EmptyString == w {
+		return
+	}
+	base := big.NewRat(int64(len(alpha)), 1)
+	for _, r := range w {
+		width.Quo(width, base)
+		digit := big.NewRat(int64(sort.Search(len(alpha), func(i int) bool { return alpha[i] >= r })), 1)
+		left.Add(left, digit.Mul(digit, width))
+	}
+	return
+}
+
+// liftPiece is one affine piece of a lift to R of a circle map: [domLeft, domLeft+domWidth)
+// is carried onto [ranLeft, ranLeft+ranWidth).
+type liftPiece struct {
+	domLeft, domWidth, ranLeft, ranWidth *big.Rat
+}
+
+// slope returns the slope of the piece.
+func (p liftPiece) slope() *big.Rat {
+	return new(big.Rat).Quo(p.ranWidth, p.domWidth)
+}
+
+// at evaluates the piece at x, which should lie in (the closure of) its domain.
+func (p liftPiece) at(x *big.Rat) *big.Rat {
+	y := new(big.Rat).Sub(x, p.domLeft)
+	y.Mul(y, p.slope())
+	return y.Add(y, p.ranLeft)
+}
+
+// circleLift is the lift to R of an element of T acting on the circle [0,1)/~, normalised so
+// that it carries 0 into [0,1).
+type circleLift struct {
+	pieces []liftPiece
+}
+
+// newCircleLift builds the lift of the element tp of T.
+func newCircleLift(tp TreePair) circleLift {
+	alpha := tp.Alphabet()
+	pairs := leafPairs(tp)
+	lift := circleLift{pieces: make([]liftPiece, len(pairs))}
+	for k, v := range pairs {
+		dl, dw := wordInterval(alpha, v[0])
+		rl, rw := wordInterval(alpha, v[1])
+		lift.pieces[k] = liftPiece{domLeft: dl, domWidth: dw, ranLeft: rl, ranWidth: rw}
+	}
+	// pieces whose image starts before the image of 0 have wrapped around the circle.
+	start := lift.pieces[0].ranLeft
+	for k := range lift.pieces {
+		if lift.pieces[k].ranLeft.Cmp(start) < 0 {
+			lift.pieces[k].ranLeft = new(big.Rat).Add(lift.pieces[k].ranLeft, big.NewRat(1, 1))
+		}
+	}
+	return lift
+}
+
+// floorRat returns the largest integer not above x.
+func floorRat(x *big.Rat) *big.Int {
+	// big.Int division is Euclidean, which is the floor for a positive denominator.
+	return new(big.Int).Div(x.Num(), x.Denom())
+}
+
+// eval applies the lift to any real x.
+func (l circleLift) eval(x *big.Rat) *big.Rat {
+	whole := new(big.Rat).SetInt(floorRat(x))
+	frac := new(big.Rat).Sub(x, whole)
+	k := sort.Search(len(l.pieces), func(i int) bool {
+		return l.pieces[i].domLeft.Cmp(frac) > 0
+	}) - 1
+	y := l.pieces[k].at(frac)
+	return y.Add(y, whole)
+}
+
+// fixedComponent is a connected component [left, right] of the fixed point set of a circle map,
+// together with the slope of the map just before left and just after right.
+type fixedComponent struct {
+	left, right       *big.Rat
+	slopeIn, slopeOut *big.Rat
+}
+
+// isPoint reports whether the component is an isolated fixed point.
+func (c fixedComponent) isPoint() bool {
+	return c.left.Cmp(c.right) == 0
+}
+
+// fixedSpan is a closed interval [left, right] of fixed points, possibly a single point.
+type fixedSpan struct {
+	left, right *big.Rat
+}
+
+// fixedSpans returns the maximal intervals of points x of [0,1] with L(x) = x + p for some
+// integer p, in increasing order.  Points are only detected in [0,1), so 1 appears only as the
+// right end of an interval.
+func (l circleLift) fixedSpans() []fixedSpan {
+	var spans []fixedSpan
+	one := big.NewRat(1, 1)
+	for _, p := range l.pieces {
+		a := p.domLeft
+		b := new(big.Rat).Add(p.domLeft, p.domWidth)
+		da := new(big.Rat).Sub(p.at(a), a)
+		db := new(big.Rat).Sub(p.at(b), b)
+		if da.Cmp(db) == 0 {
+			if da.IsInt() {
+				spans = append(spans, fixedSpan{a, b})
+			}
+			continue
+		}
+		lo, hi := da, db
+		if lo.Cmp(hi) > 0 {
+			lo, hi = hi, lo
+		}
+		// try each integer displacement in [lo, hi]: x (s - 1) = n - ranLeft + domLeft s.
+		s := p.slope()
+		sMinusOne := new(big.Rat).Sub(s, one)
+		for n := new(big.Int).Neg(floorRat(new(big.Rat).Neg(lo))); new(big.Rat).SetInt(n).Cmp(hi) <= 0; n.Add(n, big.NewInt(1)) {
+			x := new(big.Rat).SetInt(n)
+			x.Sub(x, p.ranLeft)
+			x.Add(x, new(big.Rat).Mul(a, s))
+			x.Quo(x, sMinusOne)
+			if x.Cmp(b) < 0 {
+				spans = append(spans, fixedSpan{x, x})
+			}
+		}
+	}
+	if 0 == len(spans) {
+		return nil
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].left.Cmp(spans[j].left) < 0 })
+
+	// merge touching spans.
+	merged := []fixedSpan{spans[0]}
+	for _, v := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if v.left.Cmp(last.right) <= 0 {
+			if v.right.Cmp(last.right) > 0 {
+				last.right = v.right
+			}
+			continue
+		}
+		merged = append(merged, v)
+	}
+	return merged
+}
+
+// fixedComponents returns the components of the set of fixed points of the circle map with
+// lift L, in increasing order.  A component running over 1 back to 0 is reported once,
+// starting at its left end.
+func (l circleLift) fixedComponents() []fixedComponent {
+	merged := l.fixedSpans()
+	if 0 == len(merged) {
+		return nil
+	}
+	one := big.NewRat(1, 1)
+
+	// merge across 1 ~ 0.
+	if len(merged) > 1 && merged[len(merged)-1].right.Cmp(one) == 0 && 0 == merged[0].left.Sign() {
+		last := merged[len(merged)-1]
+		merged[0].left = last.left
+		merged[0].right = new(big.Rat).Add(merged[0].right, one)
+		merged = merged[:len(merged)-1]
+	}
+	if 1 == len(merged) && merged[0].right.Cmp(one) == 0 && 0 == merged[0].left.Sign() {
+		// everything is fixed.
+		return []fixedComponent{{left: merged[0].left, right: merged[0].right, slopeIn: one, slopeOut: one}}
+	}
+
+	components := make([]fixedComponent, len(merged))
+	for k, v := range merged {
+		components[k] = fixedComponent{left: v.left, right: v.right,
+			slopeIn: l.slopeBefore(v.left), slopeOut: l.slopeAfter(v.right)}
+	}
+	return components
+}
+
+// slopeAfter returns the right derivative of the lift at x.
+func (l circleLift) slopeAfter(x *big.Rat) *big.Rat {
+	frac := new(big.Rat).Sub(x, new(big.Rat).SetInt(floorRat(x)))
+	k := sort.Search(len(l.pieces), func(i int) bool {
+		return l.pieces[i].domLeft.Cmp(frac) > 0
+	}) - 1
+	return l.pieces[k].slope()
+}
+
+// slopeBefore returns the left derivative of the lift at x.
+func (l circleLift) slopeBefore(x *big.Rat) *big.Rat {
+	frac := new(big.Rat).Sub(x, new(big.Rat).SetInt(floorRat(x)))
+	k := sort.Search(len(l.pieces), func(i int) bool {
+		return l.pieces[i].domLeft.Cmp(frac) >= 0
+	}) - 1
+	if k < 0 {
+		k = len(l.pieces) - 1
+	}
+	return l.pieces[k].slope()
+}
+
+// periodicData finds the least q > 0 for which the element tp of T has a periodic point of
+// period q, and returns q, the rotation number p/q in [0,1) and the fixed components of tp^q.
+func periodicData(tp TreePair) (q int, rotation *big.Rat, fixed []fixedComponent) {
+	lift := newCircleLift(tp)
+	power := clone(tp)
+	power.Minimise()
+	for q = 1; ; q++ {
+		fixed = newCircleLift(power).fixedComponents()
+		if len(fixed) > 0 {
+			break
+		}
+		power = Multiply(power, clone(tp))
+	}
+
+	// follow one periodic orbit on the lift of tp to read off how far it turns.
+	x := fixed[0].left
+	y := new(big.Rat).Set(x)
+	for k := 0; k < q; k++ {
+		y = lift.eval(y)
+	}
+	turns := new(big.Rat).Sub(y, x)
+	rotation = new(big.Rat).Quo(turns, big.NewRat(int64(q), 1))
+	rotation.Sub(rotation, new(big.Rat).SetInt(floorRat(rotation)))
+	return
+}
+
+// RotationNumber returns the rotation number in [0,1) of an element of T, viewed as a
+// piecewise-linear homeomorphism of the circle [0,1)/~ with the leaves of the trees read
+// as base len(alphabet) intervals.  Elements of T have rational rotation numbers p/q, and
+// the element then has periodic orbits of period exactly q.
+func RotationNumber(tp TreePair) (*big.Rat, error) {
+	min := clone(tp)
+	min.Minimise()
+	if !min.InT() {
+		return nil, errors.New("RotationNumber(): element is not in T")
+	}
+	_, rotation, _ := periodicData(min)
+	return rotation, nil
+}
@@ -0,0 +1,56 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotation(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	t.Run("RotationNumber test", func(t *testing.T) {
+		words := map[string]string{
+			"":         "0/1",
+			"A":        "0/1",
+			"C":        "2/3",
+			"C^2":      "1/3",
+			"B C":      "3/5",
+			"C A":      "1/2",
+			"A^-1 C A": "2/3",
+		}
+		for word, want := range words {
+			tp, err := EvaluateWord("01", word)
+			assert.Nil(t, err)
+			got, err := RotationNumber(tp)
+			assert.Nil(t, err)
+			assertCorrectMessage(t, got.String(), want)
+		}
+
+		p, _ := GeneratorPi0()
+		_, err := RotationNumber(p)
+		assert.NotNil(t, err, "pi0 is not in T.")
+	})
+
+	t.Run("fixedComponents test", func(t *testing.T) {
+		a, _ := GeneratorA()
+		fixed := newCircleLift(a).fixedComponents()
+		assert.Equal(t, 1, len(fixed))
+		assert.True(t, fixed[0].isPoint())
+		assertCorrectMessage(t, fixed[0].slopeIn.String(), "2/1")
+		assertCorrectMessage(t, fixed[0].slopeOut.String(), "1/2")
+
+		// B fixes [0, 1/2] pointwise.
+		b, _ := GeneratorB()
+		fixed = newCircleLift(b).fixedComponents()
+		assert.Equal(t, 1, len(fixed))
+		assertCorrectMessage(t, fixed[0].left.String(), "0/1")
+		assertCorrectMessage(t, fixed[0].right.String(), "1/2")
+	})
+}
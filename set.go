@@ -0,0 +1,61 @@
+package treepair
+
+// TreePairSet is a set of elements, each stored once whatever tree pair represents it.
+type TreePairSet struct {
+	index    map[string]int
+	elements []TreePair
+}
+
+// NewTreePairSet returns an empty set.
+func NewTreePairSet() *TreePairSet {
+	return &TreePairSet{index: map[string]int{}}
+}
+
+// canonicalString returns a string determining the element tp: the full string of its
+// minimised tree pair with labels reset.  tp itself is not modified.
+func canonicalString(tp TreePair) string {
+	return canonicalCopy(tp).FullString()
+}
+
+// canonicalCopy returns a minimised copy of tp with labels reset.
+func canonicalCopy(tp TreePair) *treePair {
+	min := clone(tp)
+	min.Minimise()
+	min.ResetLabels()
+	return min
+}
+
+// Add puts a minimised copy of tp in the set, reporting whether it was new.
+func (s *TreePairSet) Add(tp TreePair) bool {
+	key := tp.CanonicalString()
+	if _, ok := s.index[key]; ok {
+		return false
+	}
+	min := clone(tp)
+	min.Minimise()
+	min.ResetLabels()
+	s.index[key] = len(s.elements)
+	s.elements = append(s.elements, min)
+	return true
+}
+
+// Contains reports whether the element tp is in the set.
+func (s *TreePairSet) Contains(tp TreePair) bool {
+	_, ok := s.index[tp.CanonicalString()]
+	return ok
+}
+
+// Len returns the number of elements in the set.
+func (s *TreePairSet) Len() int {
+	return len(s.elements)
+}
+
+// Iterate calls visit on the elements of the set in the order they were added, stopping early
+// if visit returns false.  The elements are the set's own copies and must not be modified.
+func (s *TreePairSet) Iterate(visit func(tp TreePair) bool) {
+	for _, v := range s.elements {
+		if !visit(v) {
+			return
+		}
+	}
+}
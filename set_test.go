@@ -0,0 +1,74 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreePairSet(t *testing.T) {
+
+	t.Run("TreePairSet test", func(t *testing.T) {
+		s := NewTreePairSet()
+		assert.Equal(t, 0, s.Len())
+		for _, v := range []struct {
+			word  string
+			isNew bool
+		}{
+			{"x0", true},
+			{"x1", true},
+			{"x0 x1 x0^-1", true},
+			{"x2", false},
+			{"C", true},
+			{"C^4", false},
+			{"x0 x0^-1", true},
+			{"x1 x1^-1 x1", false},
+		} {
+			tp, err := EvaluateWord("01", v.word)
+			assert.Nil(t, err)
+			assert.Equal(t, v.isNew, s.Add(tp), v.word)
+		}
+		assert.Equal(t, 5, s.Len())
+
+		for _, word := range []string{"x2", "x1 x0 x0^-1", "C^-2", ""} {
+			tp, _ := EvaluateWord("01", word)
+			assert.True(t, s.Contains(tp), word)
+		}
+		for _, word := range []string{"x0^2", "pi0", "x1^2"} {
+			tp, _ := EvaluateWord("01", word)
+			assert.False(t, s.Contains(tp), word)
+		}
+	})
+
+	t.Run("TreePairSet Add test", func(t *testing.T) {
+		s := NewTreePairSet()
+		x0, _ := GeneratorX(0)
+		big := clone(x0)
+		big.ExpandDomainAt("1")
+		assert.True(t, s.Add(x0))
+		assert.False(t, s.Add(big))
+		assert.Equal(t, 3, big.Size())
+		assert.Equal(t, 1, s.Len())
+	})
+
+	t.Run("TreePairSet Iterate test", func(t *testing.T) {
+		s := NewTreePairSet()
+		var words []string
+		for _, word := range []string{"x0", "x1", "x2", "C", "x0^-1"} {
+			tp, _ := EvaluateWord("01", word)
+			if s.Add(tp) {
+				words = append(words, word)
+			}
+		}
+		var visited []TreePair
+		s.Iterate(func(tp TreePair) bool {
+			visited = append(visited, tp)
+			return len(visited) < 3
+		})
+		assert.Equal(t, 3, len(visited))
+		for k, v := range visited {
+			tp, _ := EvaluateWord("01", words[k])
+			assert.True(t, EqualsAsElements(v, tp))
+		}
+	})
+}
@@ -0,0 +1,356 @@
+package treepair
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+/*
+SteinTreePair is an element of a Stein-Thompson group: a prefix replacement map between two
+trees whose carets may have different arities.  Each kind of caret has its own letters, e.g.
+the carets "01" and "abc" for the binary and ternary carets of F(2,3), so the letter following
+a vertex in a word records both the kind of caret hanging there and the branch taken.  Words
+are written with prefcode.EmptyString for the root.  Reading the letters of each caret in order
+as equal subdivisions, the element acts on [0,1) by affine maps between the leaf intervals.
+
+Unlike for TreePair, reduced diagrams are not unique: expanding a vertex by a binary caret and
+then ternary carets gives the same six intervals as the other way round.  IsIdentity, InF and
+SteinToPL read the element as a map of intervals and so do not depend on the diagram.
+
+Can:
+ 1. Return its carets and leaf pairs, and the image of a word.
+ 2. Minimise, by reducing carets carried onto carets of the same kind.
+ 3. Invert.
+ 4. Multiply with another element with the same carets (see MultiplyStein).
+ 5. Detect if it is the identity or order preserving, and give its breakpoints if so.
+*/
+type SteinTreePair interface {
+	Carets() []string
+	Size() int
+	Image(w string) (string, error)
+	Invert()
+	IsIdentity() bool
+	InF() bool
+	Minimise()
+	FullString() string
+}
+
+type steinTreePair struct {
+	carets []string
+	pairs  leafMap
+}
+
+// NewSteinTreePair returns the element with the given carets carrying the leaf domain[k] to
+// the leaf ran[k], for each k.  Each caret has at least two letters, no letter is in two
+// carets, and each list must be the leaves of a tree in which every internal vertex carries a
+// full caret of one kind.
+func NewSteinTreePair(carets []string, domain, ran []string) (*steinTreePair, error) {
+	seen := map[rune]bool{}
+	for _, v := range carets {
+		if len([]rune(v)) < 2 {
+			return nil, errors.New("NewSteinTreePair(): caret " + v + " has fewer than two letters")
+		}
+		for _, c := range v {
+			if seen[c] {
+				return nil, errors.New("NewSteinTreePair(): letter " + string(c) + " is in two carets")
+			}
+			seen[c] = true
+		}
+	}
+	if len(domain) != len(ran) {
+		return nil, errors.New("NewSteinTreePair(): domain and range have different sizes")
+	}
+	sp := &steinTreePair{carets: append([]string(nil), carets...), pairs: leafMap{}}
+	for k := range domain {
+		sp.pairs[rootAsEmpty(domain[k])] = rootAsEmpty(ran[k])
+	}
+	if len(sp.pairs) != len(domain) || !sp.isTree(sortedKeys(sp.pairs)) || !sp.isTree(sp.rangeWords()) {
+		return nil, errors.New("NewSteinTreePair(): domain or range is not the set of leaves of a tree with carets " + strings.Join(carets, " "))
+	}
+	return sp, nil
+}
+
+// caret returns the letters of the caret containing c, or nil if there is none.
+func (sp *steinTreePair) caret(c rune) []rune {
+	for _, v := range sp.carets {
+		if strings.ContainsRune(v, c) {
+			return []rune(v)
+		}
+	}
+	return nil
+}
+
+// isTree reports whether the distinct words leaves are the leaves of a tree with the carets of
+// sp: every proper prefix of a leaf is followed, among the leaves, by exactly the letters of
+// one caret, and no leaf is a proper prefix of another.
+func (sp *steinTreePair) isTree(leaves []string) bool {
+	children := map[string]map[rune]bool{}
+	for _, w := range leaves {
+		runes := []rune(w)
+		for k := range runes {
+			v := string(runes[:k])
+			if nil == children[v] {
+				children[v] = map[rune]bool{}
+			}
+			children[v][runes[k]] = true
+		}
+	}
+	for _, w := range leaves {
+		if _, internal := children[w]; internal {
+			return false
+		}
+	}
+	for _, letters := range children {
+		var c rune
+		for c = range letters {
+			break
+		}
+		caret := sp.caret(c)
+		if len(caret) != len(letters) {
+			return false
+		}
+		for _, v := range caret {
+			if !letters[v] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// rangeWords returns the range words of sp in dictionary order.
+func (sp *steinTreePair) rangeWords() []string {
+	words := make([]string, 0, len(sp.pairs))
+	for _, v := range sp.pairs {
+		words = append(words, v)
+	}
+	sort.Strings(words)
+	return words
+}
+
+// Carets returns a copy of the carets of sp.
+func (sp *steinTreePair) Carets() []string {
+	return append([]string(nil), sp.carets...)
+}
+
+// Size returns the number of leaves of the domain tree.
+func (sp *steinTreePair) Size() int {
+	return len(sp.pairs)
+}
+
+// Image returns the image of the finite word w, which must lie below a domain leaf.
+func (sp *steinTreePair) Image(w string) (string, error) {
+	w = rootAsEmpty(w)
+	for d, r := range sp.pairs {
+		if strings.HasPrefix(w, d) {
+			return emptyAsRoot(r + strings.TrimPrefix(w, d)), nil
+		}
+	}
+	return "", errors.New("SteinTreePair.Image(): " + w + " lies above the leaves of the domain tree")
+}
+
+// Invert replaces sp by its inverse.
+func (sp *steinTreePair) Invert() {
+	inverse := make(leafMap, len(sp.pairs))
+	for k, v := range sp.pairs {
+		inverse[v] = k
+	}
+	sp.pairs = inverse
+}
+
+// Minimise replaces each family of sibling domain leaves w c carried onto a family of sibling
+// range leaves v c, for c running through one caret, by the single pair w -> v, until there
+// are none left.  The result need not be the smallest diagram of the element.
+func (sp *steinTreePair) Minimise() {
+	sp.pairs.minimise(sp.caret)
+}
+
+// interval returns the left end and width of the subinterval of [0,1) addressed by w.
+func (sp *steinTreePair) interval(w string) (left, width *big.Rat) {
+	left, width = new(big.Rat), big.NewRat(1, 1)
+	for _, c := range w {
+		caret := sp.caret(c)
+		width.Quo(width, big.NewRat(int64(len(caret)), 1))
+		rank := 0
+		for caret[rank] != c {
+			rank++
+		}
+		left.Add(left, new(big.Rat).Mul(big.NewRat(int64(rank), 1), width))
+	}
+	return
+}
+
+// IsIdentity assesses if sp is the identity: each domain leaf has the same interval as its
+// image.
+func (sp *steinTreePair) IsIdentity() bool {
+	for d, r := range sp.pairs {
+		leftD, widthD := sp.interval(d)
+		leftR, widthR := sp.interval(r)
+		if leftD.Cmp(leftR) != 0 || widthD.Cmp(widthR) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// orderedLeaves returns the domain leaves of sp from left to right.
+func (sp *steinTreePair) orderedLeaves() []string {
+	leaves := sortedKeys(sp.pairs)
+	sort.Slice(leaves, func(i, j int) bool {
+		left, _ := sp.interval(leaves[i])
+		right, _ := sp.interval(leaves[j])
+		return left.Cmp(right) < 0
+	})
+	return leaves
+}
+
+// InF assesses if sp preserves the order of [0,1), i.e. carries the domain leaves from left to
+// right onto the range leaves from left to right.
+func (sp *steinTreePair) InF() bool {
+	var last *big.Rat
+	for _, d := range sp.orderedLeaves() {
+		left, _ := sp.interval(sp.pairs[d])
+		if nil != last && left.Cmp(last) <= 0 {
+			return false
+		}
+		last = left
+	}
+	return true
+}
+
+// FullString returns the leaf pairs of sp in dictionary order of the domain leaves, e.g.
+// "[0 a], [1a b], [1b c]".
+func (sp *steinTreePair) FullString() string {
+	parts := make([]string, 0, len(sp.pairs))
+	for _, d := range sortedKeys(sp.pairs) {
+		parts = append(parts, "["+emptyAsRoot(d)+" "+emptyAsRoot(sp.pairs[d])+"]")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// MultiplyStein returns the element applying first and then second, following the convention
+// of Multiply.  The two must have the same carets.  The result is minimised, and first and
+// second are not modified.
+func MultiplyStein(first, second SteinTreePair) (*steinTreePair, error) {
+	if strings.Join(first.Carets(), " ") != strings.Join(second.Carets(), " ") {
+		return nil, errors.New("MultiplyStein(): elements have different carets")
+	}
+	a, okA := first.(*steinTreePair)
+	b, okB := second.(*steinTreePair)
+	if !okA || !okB {
+		return nil, errors.New("MultiplyStein(): unsupported SteinTreePair implementation")
+	}
+	product := &steinTreePair{carets: a.Carets(), pairs: a.composeWith(b)}
+	product.Minimise()
+	return product, nil
+}
+
+// composeWith returns the leaf map applying sp and then next.  Since diagrams are not unique,
+// the range tree of sp and the domain tree of next are refined until they cut [0,1) into the
+// same intervals, and leaves are then matched by their intervals.  An interval is cut at a
+// point p inside it by the caret whose arity shares the largest factor with the denominator
+// of the relative position of p, which brings p closer to a cut each time.
+func (sp *steinTreePair) composeWith(next *steinTreePair) leafMap {
+	f := make(leafMap, len(sp.pairs))
+	for k, v := range sp.pairs {
+		f[k] = v
+	}
+	s := make(leafMap, len(next.pairs))
+	for k, v := range next.pairs {
+		s[k] = v
+	}
+	inverse := make(leafMap, len(f))
+	for k, v := range f {
+		inverse[v] = k
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, r := range sortedKeys(inverse) {
+			for _, d := range sortedKeys(s) {
+				if p, ok := sp.pointInside(r, d); ok {
+					a := inverse[r]
+					letters := sp.caretToCut(r, p)
+					f = f.expandAt(letters, a)
+					delete(inverse, r)
+					for _, c := range letters {
+						inverse[r+string(c)] = a + string(c)
+					}
+					changed = true
+					break
+				}
+				if p, ok := sp.pointInside(d, r); ok {
+					s = s.expandAt(sp.caretToCut(d, p), d)
+					changed = true
+					break
+				}
+			}
+			if changed {
+				break
+			}
+		}
+	}
+
+	byInterval := make(map[string]string, len(s))
+	for d, v := range s {
+		left, width := sp.interval(d)
+		byInterval[left.RatString()+" "+width.RatString()] = v
+	}
+	composite := make(leafMap, len(f))
+	for a, r := range f {
+		left, width := sp.interval(r)
+		composite[a] = byInterval[left.RatString()+" "+width.RatString()]
+	}
+	return composite
+}
+
+// pointInside returns an end of the interval of v lying strictly inside the interval of u.
+func (sp *steinTreePair) pointInside(u, v string) (*big.Rat, bool) {
+	leftU, widthU := sp.interval(u)
+	rightU := new(big.Rat).Add(leftU, widthU)
+	leftV, widthV := sp.interval(v)
+	for _, p := range []*big.Rat{leftV, new(big.Rat).Add(leftV, widthV)} {
+		if p.Cmp(leftU) > 0 && p.Cmp(rightU) < 0 {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// caretToCut returns the letters of the caret to hang below w to cut its interval near p.
+func (sp *steinTreePair) caretToCut(w string, p *big.Rat) []rune {
+	left, width := sp.interval(w)
+	position := new(big.Rat).Sub(p, left)
+	position.Quo(position, width)
+	best, bestFactor := sp.carets[0], new(big.Int)
+	for _, v := range sp.carets {
+		factor := new(big.Int).GCD(nil, nil, big.NewInt(int64(len([]rune(v)))), position.Denom())
+		if factor.Cmp(bestFactor) > 0 {
+			best, bestFactor = v, factor
+		}
+	}
+	return []rune(best)
+}
+
+// SteinToPL returns the breakpoints of the order preserving element sp as a piecewise-linear
+// homeomorphism of [0,1], laid out as ToPL returns them.  Slopes are products of powers of the
+// caret arities.
+func SteinToPL(sp SteinTreePair) ([]Breakpoint, error) {
+	s, ok := sp.(*steinTreePair)
+	if !ok || !s.InF() {
+		return nil, errors.New("SteinToPL(): element is not order preserving")
+	}
+	var breaks []Breakpoint
+	for _, d := range s.orderedLeaves() {
+		x, widthD := s.interval(d)
+		y, widthR := s.interval(s.pairs[d])
+		slope := new(big.Rat).Quo(widthR, widthD)
+		if last := len(breaks) - 1; last >= 0 && breaks[last].Slope.Cmp(slope) == 0 {
+			continue
+		}
+		breaks = append(breaks, Breakpoint{X: x, Y: y, Slope: slope})
+	}
+	return breaks, nil
+}
@@ -0,0 +1,103 @@
+package treepair
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSteinTreePair(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	carets := []string{"01", "abc"}
+	breakString := func(breaks []Breakpoint) string {
+		parts := make([]string, len(breaks))
+		for k, v := range breaks {
+			parts[k] = v.String()
+		}
+		return strings.Join(parts, " ")
+	}
+
+	t.Run("NewSteinTreePair test", func(t *testing.T) {
+		sp, err := NewSteinTreePair(carets, []string{"0", "1a", "1b", "1c"}, []string{"a", "b", "c0", "c1"})
+		assert.Nil(t, err)
+		assert.Equal(t, 4, sp.Size())
+		assertCorrectMessage(t, sp.FullString(), "[0 a], [1a b], [1b c0], [1c c1]")
+		image, err := sp.Image("1c0b")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, image, "c10b")
+		_, err = sp.Image("1")
+		assert.NotNil(t, err)
+
+		for _, leaves := range [][2][]string{
+			{{"0", "1a", "1b"}, {"a", "b", "c"}},
+			{{"0", "1a", "1b", "10"}, {"a", "b", "c0", "c1"}},
+			{{"0", "1"}, {"a", "b"}},
+			{{"0", "1", "1a"}, {"a", "b", "c"}},
+		} {
+			_, err = NewSteinTreePair(carets, leaves[0], leaves[1])
+			assert.NotNil(t, err)
+		}
+		_, err = NewSteinTreePair([]string{"01", "1ab"}, []string{"0", "1"}, []string{"0", "1"})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("SteinTreePair identity test", func(t *testing.T) {
+		// a binary caret followed by ternary carets cuts [0,1) as a ternary caret followed by
+		// binary carets does.
+		sp, err := NewSteinTreePair(carets,
+			[]string{"0a", "0b", "0c", "1a", "1b", "1c"},
+			[]string{"a0", "a1", "b0", "b1", "c0", "c1"})
+		assert.Nil(t, err)
+		sp.Minimise()
+		assert.Equal(t, 6, sp.Size())
+		assert.True(t, sp.IsIdentity())
+		assert.True(t, sp.InF())
+
+		sp, _ = NewSteinTreePair(carets, []string{"0", "1"}, []string{"1", "0"})
+		assert.False(t, sp.IsIdentity())
+		assert.False(t, sp.InF())
+	})
+
+	t.Run("MultiplyStein test", func(t *testing.T) {
+		sp, _ := NewSteinTreePair(carets, []string{"0", "1a", "1b", "1c"}, []string{"a", "b", "c0", "c1"})
+		inverse, _ := NewSteinTreePair(carets, []string{"0", "1a", "1b", "1c"}, []string{"a", "b", "c0", "c1"})
+		inverse.Invert()
+		product, err := MultiplyStein(sp, inverse)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, product.FullString(), "[𝛆 𝛆]")
+
+		square, err := MultiplyStein(sp, sp)
+		assert.Nil(t, err)
+		assert.True(t, square.InF())
+		breaks, _ := SteinToPL(sp)
+		squareBreaks, err := SteinToPL(square)
+		assert.Nil(t, err)
+		f, f2 := newPLFunc(breaks), newPLFunc(squareBreaks)
+		for _, x := range []*big.Rat{big.NewRat(1, 7), big.NewRat(1, 3), big.NewRat(5, 9), big.NewRat(17, 18)} {
+			assert.Equal(t, f.eval(f.eval(x)).RatString(), f2.eval(x).RatString(), x.RatString())
+		}
+
+		other, _ := NewSteinTreePair([]string{"01"}, []string{"0", "1"}, []string{"0", "1"})
+		_, err = MultiplyStein(sp, other)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("SteinToPL test", func(t *testing.T) {
+		sp, _ := NewSteinTreePair(carets, []string{"0", "1a", "1b", "1c"}, []string{"a", "b", "c0", "c1"})
+		breaks, err := SteinToPL(sp)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, breakString(breaks), "(0, 0) slope 2/3 (1/2, 1/3) slope 2 (2/3, 2/3) slope 1")
+		swap, _ := NewSteinTreePair(carets, []string{"0", "1"}, []string{"1", "0"})
+		_, err = SteinToPL(swap)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,234 @@
+package treepair
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// StrandDiagram is the strand diagram of an element of V: strands run down from a source at
+// the top, splitting as the domain tree does, cross over as the prefix map carries the domain
+// leaves to the range leaves, and merge as the range tree does into a sink at the bottom.
+// Each split sends its k-th output strand, and each merge takes its k-th input strand, along
+// the k-th letter of the alphabet.  Diagrams are reduced by the moves of Reduce, and read
+// back as tree pairs by TreePair.
+type StrandDiagram struct {
+	alphabet     []rune
+	g            *strandGraph
+	source, sink int
+}
+
+// NewStrandDiagram returns the strand diagram of the tree pair tp, unreduced.  tp itself is
+// not modified.
+func NewStrandDiagram(tp TreePair) *StrandDiagram {
+	alpha := tp.Alphabet()
+	g := newStrandGraph(len(alpha))
+	d := &StrandDiagram{alphabet: alpha, g: g, source: g.addVertex(sourceVertex), sink: g.addVertex(sinkVertex)}
+	m := newLeafMap(tp)
+	if 1 == len(m) {
+		g.addEdge(d.source, 0, d.sink, 0, nil)
+		return d
+	}
+
+	letter := make(map[rune]int, len(alpha))
+	for k, c := range alpha {
+		letter[c] = k
+	}
+	parent := func(w string) (string, int) {
+		c, size := utf8.DecodeLastRuneInString(w)
+		return w[:len(w)-size], letter[c]
+	}
+	split := make(map[string]int)
+	merge := make(map[string]int)
+	var splits, merges []string
+	for _, leaf := range sortedKeys(m) {
+		for p := leaf; "" != p; {
+			p, _ = parent(p)
+			if _, ok := split[p]; !ok {
+				split[p] = g.addVertex(splitVertex)
+				splits = append(splits, p)
+			}
+		}
+	}
+	inverse := make(map[string]string, len(m))
+	for k, v := range m {
+		inverse[v] = k
+	}
+	for _, leaf := range sortedKeys(inverse) {
+		for p := leaf; "" != p; {
+			p, _ = parent(p)
+			if _, ok := merge[p]; !ok {
+				merge[p] = g.addVertex(mergeVertex)
+				merges = append(merges, p)
+			}
+		}
+	}
+
+	g.addEdge(d.source, 0, split[""], 0, nil)
+	for _, u := range splits {
+		for k, c := range alpha {
+			child := u + string(c)
+			if v, ok := split[child]; ok {
+				g.addEdge(split[u], k, v, 0, nil)
+				continue
+			}
+			p, port := parent(m[child])
+			g.addEdge(split[u], k, merge[p], port, nil)
+		}
+	}
+	for _, u := range merges {
+		if "" != u {
+			p, port := parent(u)
+			g.addEdge(merge[u], 0, merge[p], port, nil)
+		}
+	}
+	g.addEdge(merge[""], 0, d.sink, 0, nil)
+	return d
+}
+
+// Alphabet returns a copy of the alphabet of d.
+func (d *StrandDiagram) Alphabet() []rune {
+	return append([]rune{}, d.alphabet...)
+}
+
+// Vertices returns the number of splits and merges in d.
+func (d *StrandDiagram) Vertices() int {
+	return len(d.g.vertices) - 2
+}
+
+// Reduce applies reduction moves to d until none applies, and returns the number of moves
+// made.  A split whose k-th output runs into the k-th input of a merge, for every k, is
+// replaced with a single strand (a caret of the domain tree meeting the same caret of the
+// range tree), and a merge running into a split is replaced with parallel strands, joining
+// the k-th input of the merge to the k-th output of the split.  Reducing does not change the
+// element d represents.
+func (d *StrandDiagram) Reduce() int {
+	moves := 0
+	for d.g.reduceOnce(nil) {
+		moves++
+	}
+	return moves
+}
+
+// ComposeStrands returns the diagram of the product of first and second, as Multiply(first,
+// second) is, by stacking first on top of second: the strand into the sink of first is
+// joined to the strand out of the source of second.  The diagram returned is unreduced, and
+// neither first nor second is modified.
+func ComposeStrands(first, second *StrandDiagram) (*StrandDiagram, error) {
+	if string(first.alphabet) != string(second.alphabet) {
+		return nil, errors.New("ComposeStrands(): diagrams are over different alphabets")
+	}
+	g := newStrandGraph(len(first.alphabet))
+	top := g.copyFrom(first.g)
+	bottom := g.copyFrom(second.g)
+	d := &StrandDiagram{alphabet: first.Alphabet(), g: g, source: top[first.source], sink: bottom[second.sink]}
+
+	upper, lower := g.vertices[top[first.sink]].in[0], g.vertices[bottom[second.source]].out[0]
+	u, l := g.edges[upper], g.edges[lower]
+	u.head, u.headPort = l.head, l.headPort
+	g.vertices[u.head].in[u.headPort] = upper
+	delete(g.edges, lower)
+	delete(g.vertices, top[first.sink])
+	delete(g.vertices, bottom[second.source])
+	return d, nil
+}
+
+// strandFlow is a cone of the domain flowing along a strand: the cone at Domain, to be
+// carried to the cone at the word the strand reaches at the sink followed by Suffix.
+type strandFlow struct {
+	domain, suffix string
+}
+
+// TreePair returns the tree pair represented by d.  The cone at the root flows down from the
+// source.  A split passes each cone flowing in along the output named by the first letter of
+// its suffix, splitting the cone by the letters of the alphabet when its suffix is empty, and
+// a merge puts the letter of each input in front of the suffixes of the cones flowing in.
+// The cones reaching the sink give the prefix map, and the tree pair returned is minimised.
+func (d *StrandDiagram) TreePair() *treePair {
+	letter := make(map[rune]int, len(d.alphabet))
+	for k, c := range d.alphabet {
+		letter[c] = k
+	}
+	flows := map[int][]strandFlow{d.g.vertices[d.source].out[0]: {{}}}
+	pending := make(map[int]int, len(d.g.vertices))
+	for id, v := range d.g.vertices {
+		pending[id] = len(v.in)
+	}
+	ready := []int{d.g.edges[d.g.vertices[d.source].out[0]].head}
+	pending[ready[0]]--
+	for len(ready) > 0 {
+		id := ready[0]
+		ready = ready[1:]
+		v := d.g.vertices[id]
+		switch v.kind {
+		case splitVertex:
+			for _, f := range flows[v.in[0]] {
+				if "" == f.suffix {
+					for k, c := range d.alphabet {
+						flows[v.out[k]] = append(flows[v.out[k]], strandFlow{domain: f.domain + string(c)})
+					}
+					continue
+				}
+				c, size := utf8.DecodeRuneInString(f.suffix)
+				flows[v.out[letter[c]]] = append(flows[v.out[letter[c]]], strandFlow{f.domain, f.suffix[size:]})
+			}
+		case mergeVertex:
+			for k, e := range v.in {
+				for _, f := range flows[e] {
+					flows[v.out[0]] = append(flows[v.out[0]], strandFlow{f.domain, string(d.alphabet[k]) + f.suffix})
+				}
+			}
+		}
+		for _, e := range v.out {
+			head := d.g.edges[e].head
+			if pending[head]--; 0 == pending[head] {
+				ready = append(ready, head)
+			}
+		}
+	}
+
+	m := make(leafMap)
+	for _, f := range flows[d.g.vertices[d.sink].in[0]] {
+		m[f.domain] = f.suffix
+	}
+	tp := m.treePair(d.alphabet)
+	tp.Minimise()
+	return tp
+}
+
+// DOT writes d in the Graphviz DOT language, from the source at the top to the sink at the
+// bottom, labelling the strands out of each split and into each merge with their letters.
+func (d *StrandDiagram) DOT() string {
+	name := func(id int) string {
+		switch id {
+		case d.source:
+			return "top"
+		case d.sink:
+			return "bottom"
+		}
+		return "v" + strconv.Itoa(id)
+	}
+	var b strings.Builder
+	b.WriteString("digraph strands {\n\tnode [shape=point];\n\ttop [shape=plaintext];\n\tbottom [shape=plaintext];\n")
+	for _, id := range d.g.sortedVertices() {
+		v := d.g.vertices[id]
+		for k, e := range v.out {
+			edge := d.g.edges[e]
+			var labels []string
+			if splitVertex == v.kind {
+				labels = append(labels, "taillabel="+strconv.Quote(string(d.alphabet[k])))
+			}
+			if mergeVertex == d.g.vertices[edge.head].kind {
+				labels = append(labels, "headlabel="+strconv.Quote(string(d.alphabet[edge.headPort])))
+			}
+			b.WriteString("\t" + name(id) + " -> " + name(edge.head))
+			if len(labels) > 0 {
+				b.WriteString(" [" + strings.Join(labels, ", ") + "]")
+			}
+			b.WriteString(";\n")
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
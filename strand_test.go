@@ -0,0 +1,81 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrandDiagram(t *testing.T) {
+
+	t.Run("StrandDiagram round trip test", func(t *testing.T) {
+		for _, word := range []string{"A", "B^-1", "C", "pi0", "A B^2 C^-1 pi0", "A^-1 A"} {
+			tp, err := EvaluateWord("01", word)
+			assert.Nil(t, err)
+			d := NewStrandDiagram(tp)
+			assert.True(t, EqualsAsElements(tp, d.TreePair()), word)
+			d.Reduce()
+			assert.True(t, EqualsAsElements(tp, d.TreePair()), word)
+		}
+
+		tp, _ := ParseElement("012", "{1100000,1010000,4 1 2 3 0}")
+		d := NewStrandDiagram(tp)
+		d.Reduce()
+		assert.True(t, EqualsAsElements(tp, d.TreePair()))
+	})
+
+	t.Run("StrandDiagram Reduce test", func(t *testing.T) {
+		// the unreduced identity {1100100,1100100,0 1 2 3} collapses caret by caret.
+		tp, _ := binaryFromDFS("{1100100,1100100,0 1 2 3}")
+		d := NewStrandDiagram(tp)
+		assert.Equal(t, 6, d.Vertices())
+		assert.Equal(t, 3, d.Reduce())
+		assert.Equal(t, 0, d.Vertices())
+		assert.True(t, d.TreePair().IsIdentity())
+
+		// the diagram of a minimal pair is reduced.
+		a, _ := GeneratorA()
+		b, _ := GeneratorB()
+		assert.Equal(t, 0, NewStrandDiagram(Multiply(clone(a), clone(b))).Reduce())
+	})
+
+	t.Run("ComposeStrands test", func(t *testing.T) {
+		a, _ := GeneratorA()
+		b, _ := GeneratorB()
+		d, err := ComposeStrands(NewStrandDiagram(a), NewStrandDiagram(b))
+		assert.Nil(t, err)
+		assert.Equal(t, 10, d.Vertices())
+		assert.True(t, EqualsAsElements(Multiply(clone(a), clone(b)), d.TreePair()))
+		assert.True(t, d.Reduce() > 0)
+		assert.Equal(t, NewStrandDiagram(Multiply(clone(a), clone(b))).Vertices(), d.Vertices())
+		assert.True(t, EqualsAsElements(Multiply(clone(a), clone(b)), d.TreePair()))
+
+		aInv := clone(a)
+		aInv.Invert()
+		d, _ = ComposeStrands(NewStrandDiagram(a), NewStrandDiagram(aInv))
+		d.Reduce()
+		assert.Equal(t, 0, d.Vertices())
+
+		ternary, _ := NewTreePairAlpha("012")
+		_, err = ComposeStrands(NewStrandDiagram(a), NewStrandDiagram(ternary))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("StrandDiagram DOT test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		want := `digraph strands {
+	node [shape=point];
+	top [shape=plaintext];
+	bottom [shape=plaintext];
+	top -> v3;
+	v3 -> v5 [taillabel="0", headlabel="0"];
+	v3 -> v4 [taillabel="1"];
+	v4 -> v5 [taillabel="0", headlabel="1"];
+	v4 -> v6 [taillabel="1", headlabel="1"];
+	v5 -> v6 [headlabel="0"];
+	v6 -> bottom;
+}
+`
+		assert.Equal(t, want, NewStrandDiagram(x0).DOT())
+	})
+}
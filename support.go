@@ -0,0 +1,81 @@
+package treepair
+
+import (
+	"sort"
+
+	"github.com/loeksnokes/prefcode"
+)
+
+// Support returns, in dictionary order, a minimal set of addresses whose cones together make
+// up the closure of the set of points moved by tp.  Its complement is the union of the cones
+// fixed pointwise by tp, which are exactly the cones below domain leaves of the minimised
+// pair that tp carries to themselves.  Sibling cones are merged into their parent, so no
+// address has all of its children present.  The identity has empty support, and an element
+// fixing no cone pointwise has support {prefcode.EmptyString}.  tp itself is not modified.
+func Support(tp TreePair) []string {
+	min := clone(tp)
+	min.Minimise()
+	if min.IsIdentity() {
+		return nil
+	}
+
+	alpha := min.Alphabet()
+	moved := map[string]bool{}
+	for _, v := range leafPairs(min) {
+		if v[0] != v[1] {
+			moved[v[0]] = true
+		}
+	}
+
+	// merge complete families of siblings, deepest first, until none are left.
+	for merging := true; merging; {
+		merging = false
+		for _, w := range sortedByLengthDesc(moved) {
+			if !moved[w] {
+				continue
+			}
+			runes := []rune(w)
+			parent := string(runes[:len(runes)-1])
+			complete := true
+			for _, a := range alpha {
+				if !moved[parent+string(a)] {
+					complete = false
+					break
+				}
+			}
+			if !complete {
+				continue
+			}
+			for _, a := range alpha {
+				delete(moved, parent+string(a))
+			}
+			if "" == parent {
+				parent = prefcode.EmptyString
+			}
+			moved[parent] = true
+			merging = true
+		}
+	}
+
+	support := make([]string, 0, len(moved))
+	for w := range moved {
+		support = append(support, w)
+	}
+	sort.Strings(support)
+	return support
+}
+
+// sortedByLengthDesc returns the keys of set, longest first.
+func sortedByLengthDesc(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
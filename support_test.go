@@ -0,0 +1,46 @@
+package treepair
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupport(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	support := func(t *testing.T, word string) string {
+		t.Helper()
+		tp, err := EvaluateWord("01", word)
+		assert.Nil(t, err)
+		return strings.Join(Support(tp), " ")
+	}
+
+	t.Run("Support test", func(t *testing.T) {
+		assertCorrectMessage(t, support(t, ""), "")
+		assertCorrectMessage(t, support(t, "x0"), "𝛆")
+		assertCorrectMessage(t, support(t, "C"), "𝛆")
+		assertCorrectMessage(t, support(t, "x1"), "1")
+		assertCorrectMessage(t, support(t, "x3^-2"), "111")
+		assertCorrectMessage(t, support(t, "pi0"), "1")
+		assertCorrectMessage(t, support(t, "x0 x1^-1"), "0 10 110")
+	})
+
+	// elements with disjoint supports commute.
+	t.Run("Support disjoint test", func(t *testing.T) {
+		a, _ := binaryFromDFS("{11000,11000,1 0 2}")
+		b, _ := EvaluateWord("01", "x2")
+		assertCorrectMessage(t, strings.Join(Support(a), " "), "0")
+		assertCorrectMessage(t, strings.Join(Support(b), " "), "11")
+		ab := Multiply(clone(a), clone(b))
+		ba := Multiply(clone(b), clone(a))
+		assert.True(t, EqualsAsElements(ab, ba))
+	})
+}
@@ -0,0 +1,142 @@
+package treepair
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/loeksnokes/prefcode"
+)
+
+// standInLetters are the letters standing in for symbols, in increasing order.  They are one
+// byte each, as prefcode slices words by bytes in places.
+const standInLetters = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+/*
+Symbols is an alphabet whose letters are strings of any length, as {"a1", "a2", "b"}.  The
+symbols must form a prefix code, so that an address such as "a2ba1" splits into symbols in
+only one way.  A tree pair over the symbols is a tree pair over stand-in letters '0', '1',
+..., one for each symbol in the order given, and the Symbols translate addresses between the
+two.  Dictionary order of addresses follows the order of the symbols.
+*/
+type Symbols struct {
+	names    []string
+	letterOf map[string]rune
+	nameOf   map[rune]string
+}
+
+// NewSymbols returns the alphabet of the symbols names, in that order.  There must be at
+// least two and at most 62 symbols, none empty and none a prefix of another.
+func NewSymbols(names ...string) (*Symbols, error) {
+	if len(names) < 2 || len(names) > len(standInLetters) {
+		return nil, errors.New("NewSymbols(): need between 2 and " + strconv.Itoa(len(standInLetters)) + " symbols, not " + strconv.Itoa(len(names)))
+	}
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	for k, v := range sorted {
+		if "" == v {
+			return nil, errors.New("NewSymbols(): a symbol is empty")
+		}
+		if k > 0 && strings.HasPrefix(v, sorted[k-1]) {
+			return nil, errors.New("NewSymbols(): symbol " + sorted[k-1] + " is a prefix of " + v)
+		}
+	}
+	s := &Symbols{names: append([]string(nil), names...), letterOf: make(map[string]rune, len(names)), nameOf: make(map[rune]string, len(names))}
+	for k, v := range names {
+		letter := rune(standInLetters[k])
+		s.letterOf[v] = letter
+		s.nameOf[letter] = v
+	}
+	return s, nil
+}
+
+// Names returns a copy of the symbols, in order.
+func (s *Symbols) Names() []string {
+	return append([]string(nil), s.names...)
+}
+
+// Alphabet returns the stand-in letters, to be given to NewTreePairAlpha.
+func (s *Symbols) Alphabet() string {
+	return standInLetters[:len(s.names)]
+}
+
+// NewTreePair returns the trivial tree pair over the symbols.
+func (s *Symbols) NewTreePair() (*treePair, error) {
+	return NewTreePairAlpha(s.Alphabet())
+}
+
+// Tokenize splits an address into its symbols.  The root, "" or prefcode.EmptyString, has none.
+func (s *Symbols) Tokenize(address string) ([]string, error) {
+	var tokens []string
+	if prefcode.EmptyString == address {
+		return tokens, nil
+	}
+	for rest := address; "" != rest; {
+		found := false
+		for _, v := range s.names {
+			if strings.HasPrefix(rest, v) {
+				tokens = append(tokens, v)
+				rest = rest[len(v):]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.New("Symbols.Tokenize(): " + address + " does not split into symbols at " + rest)
+		}
+	}
+	return tokens, nil
+}
+
+// Encode returns the word of stand-in letters for an address.  The root is written as given.
+func (s *Symbols) Encode(address string) (string, error) {
+	if prefcode.EmptyString == address {
+		return address, nil
+	}
+	tokens, err := s.Tokenize(address)
+	if nil != err {
+		return "", err
+	}
+	letters := make([]rune, len(tokens))
+	for k, v := range tokens {
+		letters[k] = s.letterOf[v]
+	}
+	return string(letters), nil
+}
+
+// Decode returns the address written by a word of stand-in letters.  The root is written as
+// given.
+func (s *Symbols) Decode(word string) (string, error) {
+	if prefcode.EmptyString == word {
+		return word, nil
+	}
+	var b strings.Builder
+	for _, c := range word {
+		name, ok := s.nameOf[c]
+		if !ok {
+			return "", errors.New("Symbols.Decode(): " + word + " has letter " + string(c) + " which stands for no symbol")
+		}
+		b.WriteString(name)
+	}
+	return b.String(), nil
+}
+
+// FullString writes tp, a tree pair over the symbols, as its own FullString does but with
+// the leaves written in symbols, as "{D: [a1 0], [a2 1], [b 2] || R: ...}".
+func (s *Symbols) FullString(tp TreePair) (string, error) {
+	var sides [2]string
+	for k, code := range []prefcode.PrefCode{tp.CodeDomain(), tp.CodeRange()} {
+		leaves := sortedLeaves(code)
+		parts := make([]string, len(leaves))
+		for j, v := range leaves {
+			address, err := s.Decode(v)
+			if nil != err {
+				return "", err
+			}
+			parts[j] = "[" + address + " " + strconv.Itoa(code.LabelAtLeaf(v)) + "]"
+		}
+		sides[k] = strings.Join(parts, ", ")
+	}
+	return "{D: " + sides[0] + " || R: " + sides[1] + "}", nil
+}
@@ -0,0 +1,72 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/loeksnokes/prefcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbols(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	t.Run("Tokenize test", func(t *testing.T) {
+		s, err := NewSymbols("a1", "a2", "b")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, s.Alphabet(), "012")
+		tokens, err := s.Tokenize("a2ba1")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a2", "b", "a1"}, tokens)
+		tokens, _ = s.Tokenize(prefcode.EmptyString)
+		assert.Equal(t, 0, len(tokens))
+		_, err = s.Tokenize("a2a")
+		assert.NotNil(t, err)
+
+		word, _ := s.Encode("a2ba1")
+		assertCorrectMessage(t, word, "120")
+		address, _ := s.Decode(word)
+		assertCorrectMessage(t, address, "a2ba1")
+		_, err = s.Decode("13")
+		assert.NotNil(t, err)
+
+		for _, names := range [][]string{{"a"}, {"a", "ab"}, {"a", ""}, {"b", "a", "b"}} {
+			_, err := NewSymbols(names...)
+			assert.NotNil(t, err, names)
+		}
+	})
+
+	// the rotation over {b, a1, a2} carrying b to a2, a1 to b and a2 to a1.  Symbols of different lengths
+	// leave the order of the leaves alone.
+	t.Run("Symbols tree pair test", func(t *testing.T) {
+		s, _ := NewSymbols("b", "a1", "a2")
+		tp, err := s.NewTreePair()
+		assert.Nil(t, err)
+		assert.True(t, EncodeDFS(tp, "{1000,1000,1 2 0}"))
+		full, _ := s.FullString(tp)
+		assertCorrectMessage(t, full, "{D: [b 0], [a1 1], [a2 2] || R: [b 1], [a1 2], [a2 0]}")
+
+		at, _ := s.Encode("a1a2")
+		tp.ExpandDomainAt(at)
+		full, _ = s.FullString(tp)
+		assertCorrectMessage(t, full, "{D: [b 0], [a1b 1], [a1a1 2], [a1a2b 3], [a1a2a1 4], [a1a2a2 5], [a2 6] || R: [bb 1], [ba1 2], [ba2b 3], [ba2a1 4], [ba2a2 5], [a1 6], [a2 0]}")
+		tp.Minimise()
+		full, _ = s.FullString(tp)
+		assertCorrectMessage(t, full, "{D: [b 0], [a1 1], [a2 2] || R: [b 1], [a1 2], [a2 0]}")
+		assert.True(t, Power(tp, 3).IsIdentity())
+	})
+
+	// letters of more than one byte reduce as ASCII letters do.
+	t.Run("Multi-byte letters test", func(t *testing.T) {
+		tp, _ := NewTreePairAlpha("αβ")
+		tp.ExpandDomainAt("α")
+		assertCorrectMessage(t, tp.FullString(), "{D: [αα 0], [αβ 1], [β 2] || R: [αα 0], [αβ 1], [β 2]}")
+		assert.True(t, tp.ReduceDomainAt("α"))
+		assertCorrectMessage(t, tp.FullString(), "{D: [α 0], [β 1] || R: [α 0], [β 1]}")
+	})
+}
@@ -0,0 +1,76 @@
+package treepair
+
+import "sync"
+
+// SyncTreePair holds an element behind a read-write mutex, so that goroutines may read and
+// change it concurrently.  It keeps its own copy of the tree pair and hands out only copies.
+type SyncTreePair struct {
+	mu sync.RWMutex
+	tp *treePair
+}
+
+// NewSyncTreePair returns a SyncTreePair holding a copy of tp.
+func NewSyncTreePair(tp TreePair) *SyncTreePair {
+	return &SyncTreePair{tp: clone(tp)}
+}
+
+// Get returns a copy of the tree pair held.
+func (s *SyncTreePair) Get() TreePair {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return clone(s.tp)
+}
+
+// Set replaces the tree pair held by a copy of tp.
+func (s *SyncTreePair) Set(tp TreePair) {
+	c := clone(tp)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tp = c
+}
+
+// Read calls f with the tree pair held, under the read lock.  f must not change it, but may
+// call the read-only accessors such as CodeDomain and CodeRange from several goroutines.
+func (s *SyncTreePair) Read(f func(tp TreePair)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	f(s.tp)
+}
+
+// Update calls f with the tree pair held, under the write lock, and holds a copy of the
+// tree pair f returns instead.
+func (s *SyncTreePair) Update(f func(tp TreePair) TreePair) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tp = clone(f(s.tp))
+}
+
+// Multiply replaces the element held by its product with other, applying the element held
+// first.  other is not modified.
+func (s *SyncTreePair) Multiply(other TreePair) {
+	other = clone(other)
+	s.Update(func(tp TreePair) TreePair {
+		return Multiply(tp, other)
+	})
+}
+
+// Minimise minimises the tree pair held.
+func (s *SyncTreePair) Minimise() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tp.Minimise()
+}
+
+// Classify returns the classification of the element held.
+func (s *SyncTreePair) Classify() Classification {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Classify(s.tp)
+}
+
+// DFSString returns the DFS notation of the tree pair held.
+func (s *SyncTreePair) DFSString() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tp.DFSString()
+}
@@ -0,0 +1,84 @@
+package treepair
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncTreePair(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	t.Run("SyncTreePair test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		s := NewSyncTreePair(c)
+		s.Multiply(c)
+		assertCorrectMessage(t, s.DFSString(), "{10100,10100,2 0 1}")
+		assertCorrectMessage(t, c.DFSString(), "{10100,10100,1 2 0}")
+
+		got := s.Get()
+		got.Invert()
+		assertCorrectMessage(t, s.DFSString(), "{10100,10100,2 0 1}")
+
+		tp, _ := NewTreePairAlpha("01")
+		EncodeDFS(tp, "{1100100,1110000,0 1 2 3}")
+		s.Set(tp)
+		s.Minimise()
+		assertCorrectMessage(t, s.DFSString(), "{10100,11000,0 1 2}")
+		assertCorrectMessage(t, tp.DFSString(), "{1100100,1110000,0 1 2 3}")
+		assert.Equal(t, 1, s.Classify().WordLength)
+		s.Read(func(tp TreePair) {
+			assert.True(t, tp.InF())
+		})
+	})
+
+	// goroutines multiplying by C and reading concurrently: C^3 is the identity, so 30
+	// multiplications leave the element where it was.
+	t.Run("SyncTreePair concurrent test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		x0, _ := GeneratorX(0)
+		s := NewSyncTreePair(x0)
+		var wg sync.WaitGroup
+		for k := 0; k < 30; k++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				s.Multiply(c)
+			}()
+			go func() {
+				defer wg.Done()
+				s.Classify()
+			}()
+		}
+		wg.Wait()
+		assert.True(t, EqualsAsElements(s.Get(), x0))
+	})
+
+	// Get hands out a clone sharing the codes held; reading the codes from concurrent Read
+	// callbacks must not race with each other or with the clone.
+	t.Run("SyncTreePair concurrent Read test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		s := NewSyncTreePair(x0)
+		got := s.Get()
+		want := got.CodeDomain().String() + got.CodeRange().String()
+		var wg sync.WaitGroup
+		for k := 0; k < 8; k++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.Read(func(tp TreePair) {
+					assert.Equal(t, want, tp.CodeDomain().String()+tp.CodeRange().String())
+				})
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, want, got.CodeDomain().String()+got.CodeRange().String())
+	})
+}
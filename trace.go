@@ -0,0 +1,58 @@
+package treepair
+
+import "sync/atomic"
+
+// Logger is anything that can receive formatted trace output.  A *log.Logger
+// from the standard library satisfies this interface.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// silentLogger discards everything.  It is the default Logger so that library
+// users get clean output unless they opt in to tracing.
+type silentLogger struct{}
+
+func (silentLogger) Printf(format string, v ...interface{}) {}
+
+// installedLogger holds the installed Logger.  Loggers of different types are stored in an
+// atomic.Value through it, which only takes values of one type.
+type installedLogger struct {
+	l Logger
+}
+
+// tracer is read by products running on several goroutines, as MultiplyAllParallel runs them,
+// while SetLogger may be called at any time, so it is only ever loaded and stored atomically.
+var tracer atomic.Value
+
+func init() {
+	tracer.Store(installedLogger{silentLogger{}})
+}
+
+// SetLogger installs l as the package trace hook.  Multiplication, expansion and
+// reduction report their intermediate states to l step by step.  Passing nil
+// restores the default (silent) behaviour.  SetLogger is safe to call while products
+// are being computed; l itself must be safe for concurrent use if they are computed on
+// several goroutines, as a *log.Logger is.
+func SetLogger(l Logger) {
+	if nil == l {
+		l = silentLogger{}
+	}
+	tracer.Store(installedLogger{l})
+}
+
+// logger returns the installed Logger.
+func logger() Logger {
+	return tracer.Load().(installedLogger).l
+}
+
+// tracef sends one line of trace output to the installed Logger.
+func tracef(format string, v ...interface{}) {
+	logger().Printf(format, v...)
+}
+
+// tracing reports whether a Logger other than the silent default is installed, so that
+// trace arguments which are costly to build need only be built when someone reads them.
+func tracing() bool {
+	_, silent := logger().(silentLogger)
+	return !silent
+}
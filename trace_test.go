@@ -0,0 +1,77 @@
+package treepair
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingLogger keeps every line it is sent.
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *recordingLogger) Printf(format string, v ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, fmt.Sprintf(format, v...))
+}
+
+func TestTrace(t *testing.T) {
+
+	t.Run("Silent by default", func(t *testing.T) {
+		_, isSilent := logger().(silentLogger)
+		assert.True(t, isSilent, "default tracer should be silent.")
+	})
+
+	t.Run("SetLogger receives Multiply trace", func(t *testing.T) {
+		rec := &recordingLogger{}
+		SetLogger(rec)
+		defer SetLogger(nil)
+
+		dTP, _ := NewTreePairAlpha("01")
+		rTP, _ := NewTreePairAlpha("01")
+		EncodeDFS(dTP, "{10100,11000,0 1 2}")
+		EncodeDFS(rTP, "{10100,11000,0 1 2}")
+		Multiply(dTP, rTP)
+
+		assert.NotEmpty(t, rec.lines, "Multiply produced no trace.")
+		assert.True(t, strings.HasPrefix(rec.lines[0], "Multiply(): first: "), "unexpected first trace line: "+rec.lines[0])
+	})
+
+	t.Run("SetLogger nil restores silence", func(t *testing.T) {
+		SetLogger(&recordingLogger{})
+		SetLogger(nil)
+		_, isSilent := logger().(silentLogger)
+		assert.True(t, isSilent, "SetLogger(nil) should restore the silent tracer.")
+	})
+
+	// run with -race: the Logger is swapped while products are computed in parallel.
+	t.Run("SetLogger during MultiplyAllParallel test", func(t *testing.T) {
+		defer SetLogger(nil)
+		x0, _ := GeneratorX(0)
+		c, _ := GeneratorC()
+		elems := make([]TreePair, 32)
+		for k := range elems {
+			elems[k] = []TreePair{x0, c}[k%2]
+		}
+		want, _ := MultiplyAll(elems...)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for k := 0; k < 50; k++ {
+				SetLogger(&recordingLogger{})
+				SetLogger(nil)
+			}
+		}()
+		got, err := MultiplyAllParallel(elems)
+		<-done
+		assert.Nil(t, err)
+		assert.True(t, EqualsAsElements(want, got))
+	})
+}
@@ -0,0 +1,203 @@
+package treepair
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/loeksnokes/prefcode"
+)
+
+// TransducerIdentity names the state of a Transducer which copies its input to its output.
+const TransducerIdentity = "id"
+
+// TransducerEdge is a transition of a Transducer: reading Input in state From it writes Output
+// (possibly empty) and moves to state To.
+type TransducerEdge struct {
+	From, To int
+	Input    rune
+	Output   string
+}
+
+// Transducer is an initial, asynchronous finite state transducer over Alphabet: an edge may
+// write a word of any length, possibly empty, so it is not a synchronous (letter-to-letter)
+// transducer in general.  States are named by the shortest word read from the initial state
+// to reach them, with prefcode.EmptyString for the initial state itself, except for the state
+// TransducerIdentity which copies every letter.  Edges are listed by state and then by letter.
+type Transducer struct {
+	Alphabet []rune
+	States   []string
+	Initial  int
+	Edges    []TransducerEdge
+}
+
+// ToTransducer returns the minimal asynchronous transducer realising the prefix replacement
+// map of tp.  It starts from the tree of the minimised domain, one state per internal vertex,
+// with each edge writing as much of the image as is already determined by the letters read.
+// States whose maps then agree are merged, so the states returned are exactly the distinct
+// maps u -> f(wu), with the common prefix of the image removed, for the words w read.  A
+// state reaching a leaf of the domain tree passes to TransducerIdentity.  tp itself is not
+// modified.
+func ToTransducer(tp TreePair) Transducer {
+	min := clone(tp)
+	min.Minimise()
+	alpha := min.Alphabet()
+	image := map[string]string{}
+	for _, v := range leafPairs(min) {
+		image[rootAsEmpty(v[0])] = rootAsEmpty(v[1])
+	}
+
+	// the internal vertices of the domain tree, shortest first.
+	var internal []string
+	if _, leaf := image[""]; !leaf {
+		internal = append(internal, "")
+		for k := 0; k < len(internal); k++ {
+			for _, c := range alpha {
+				if w := internal[k] + string(c); hasLeafBelow(image, w) {
+					internal = append(internal, w)
+				}
+			}
+		}
+	}
+
+	// common[w] is the longest common prefix of the images of the cone under w; an edge
+	// reading c in state w writes what common[wc] adds to common[w].
+	common := make(map[string]string, len(internal)+len(image))
+	for d, r := range image {
+		common[d] = r
+	}
+	for k := len(internal) - 1; k >= 0; k-- {
+		w := internal[k]
+		prefix := common[w+string(alpha[0])]
+		for _, c := range alpha[1:] {
+			prefix = commonPrefix(prefix, common[w+string(c)])
+		}
+		common[w] = prefix
+	}
+
+	// the tree transducer, with the identity state last.
+	index := make(map[string]int, len(internal))
+	for k, w := range internal {
+		index[w] = k
+	}
+	identity := len(internal)
+	to := make([][]int, identity+1)
+	out := make([][]string, identity+1)
+	for k, w := range internal {
+		for _, c := range alpha {
+			next := w + string(c)
+			k2, inner := index[next]
+			if !inner {
+				k2 = identity
+			}
+			to[k] = append(to[k], k2)
+			out[k] = append(out[k], strings.TrimPrefix(common[next], common[w]))
+		}
+	}
+	for _, c := range alpha {
+		to[identity] = append(to[identity], identity)
+		out[identity] = append(out[identity], string(c))
+	}
+
+	// merge states with the same outputs whose targets are merged, until nothing changes.
+	class := make([]int, len(to))
+	for classes := 0; ; {
+		keys := map[string]int{}
+		next := make([]int, len(to))
+		for k := range to {
+			var key strings.Builder
+			for j := range alpha {
+				key.WriteString(strconv.Quote(out[k][j]) + " " + strconv.Itoa(class[to[k][j]]) + " ")
+			}
+			if _, ok := keys[key.String()]; !ok {
+				keys[key.String()] = len(keys)
+			}
+			next[k] = keys[key.String()]
+		}
+		class = next
+		if len(keys) == classes {
+			break
+		}
+		classes = len(keys)
+	}
+
+	// one state per class, named by its first member, with the identity class last.
+	state := map[int]int{}
+	var members []int
+	for k := range to {
+		if _, ok := state[class[k]]; !ok && class[k] != class[identity] {
+			state[class[k]] = len(members)
+			members = append(members, k)
+		}
+	}
+	state[class[identity]] = len(members)
+	members = append(members, identity)
+
+	t := Transducer{Alphabet: alpha, Initial: state[class[0]]}
+	for _, k := range members {
+		if k == identity {
+			t.States = append(t.States, TransducerIdentity)
+		} else {
+			t.States = append(t.States, emptyAsRoot(internal[k]))
+		}
+	}
+	for s, k := range members {
+		for j, c := range alpha {
+			t.Edges = append(t.Edges, TransducerEdge{From: s, To: state[class[to[k][j]]], Input: c, Output: out[k][j]})
+		}
+	}
+	return t
+}
+
+// commonPrefix returns the longest common prefix of u and v.
+func commonPrefix(u, v string) string {
+	for k, r := range u {
+		if !strings.HasPrefix(v[k:], string(r)) {
+			return u[:k]
+		}
+	}
+	return u
+}
+
+// Apply runs the transducer on the finite word w from its initial state and returns what it
+// writes.  Words above the leaves of the domain tree produce no output.  Letters outside the
+// alphabet give an error.
+func (t Transducer) Apply(w string) (string, error) {
+	if prefcode.EmptyString == w {
+		w = ""
+	}
+	next := make(map[int]map[rune]TransducerEdge, len(t.States))
+	for _, e := range t.Edges {
+		if nil == next[e.From] {
+			next[e.From] = map[rune]TransducerEdge{}
+		}
+		next[e.From][e.Input] = e
+	}
+	var out strings.Builder
+	state := t.Initial
+	for _, r := range w {
+		e, ok := next[state][r]
+		if !ok {
+			return "", errors.New("Transducer.Apply(): letter " + string(r) + " of " + w + " is not in the alphabet " + string(t.Alphabet))
+		}
+		out.WriteString(e.Output)
+		state = e.To
+	}
+	return out.String(), nil
+}
+
+// DOT writes the transducer in the Graphviz DOT language, labelling each edge input|output.
+func (t Transducer) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph transducer {\n\trankdir=LR;\n\tstart [shape=point];\n")
+	for k, v := range t.States {
+		b.WriteString("\tq" + strconv.Itoa(k) + " [label=" + strconv.Quote(v) + "];\n")
+	}
+	b.WriteString("\tstart -> q" + strconv.Itoa(t.Initial) + ";\n")
+	for _, e := range t.Edges {
+		label := string(e.Input) + "|" + emptyAsRoot(e.Output)
+		b.WriteString("\tq" + strconv.Itoa(e.From) + " -> q" + strconv.Itoa(e.To) + " [label=" + strconv.Quote(label) + "];\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
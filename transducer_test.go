@@ -0,0 +1,88 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransducer(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	t.Run("ToTransducer states test", func(t *testing.T) {
+		for word, want := range map[string]int{
+			"":    1,
+			"x0":  3,
+			"x1":  4,
+			"C":   3,
+			"pi0": 3,
+		} {
+			tp, err := EvaluateWord("01", word)
+			assert.Nil(t, err)
+			tr := ToTransducer(tp)
+			assert.Equal(t, want, len(tr.States), word)
+			assert.Equal(t, 2*want, len(tr.Edges), word)
+		}
+	})
+
+	// the tree transducer of each has more states than the minimal one: x0 on both halves
+	// has states for x0 and for x0 below 1, and the second element is a rotation of the
+	// root followed by the identity.
+	t.Run("ToTransducer minimal states test", func(t *testing.T) {
+		for dfs, want := range map[string]int{
+			"{11010010100,11100011000,0 1 2 3 4 5}": 4,
+			"{10100,11000,1 2 0}":                   2,
+		} {
+			tp, _ := NewTreePairAlpha("01")
+			assert.True(t, EncodeDFS(tp, dfs))
+			tr := ToTransducer(tp)
+			assert.Equal(t, want, len(tr.States), dfs)
+			for _, w := range []string{"000", "010", "011", "100", "110", "111"} {
+				want, _ := ImageOfWord(tp, w)
+				got, err := tr.Apply(w)
+				assert.Nil(t, err)
+				assertCorrectMessage(t, emptyAsRoot(got), want)
+			}
+		}
+	})
+
+	t.Run("Transducer Apply test", func(t *testing.T) {
+		words := []string{""}
+		for k := 0; k < len(words) && len(words[k]) < 6; k++ {
+			words = append(words, words[k]+"0", words[k]+"1")
+		}
+		for _, word := range []string{"x0", "x1^-1", "C", "A pi0", "B C x2^2", "pi0 x0^-3"} {
+			tp, err := EvaluateWord("01", word)
+			assert.Nil(t, err)
+			tr := ToTransducer(tp)
+			for _, w := range words {
+				want, err := ImageOfWord(tp, w)
+				if nil != err {
+					continue
+				}
+				got, err := tr.Apply(w)
+				assert.Nil(t, err)
+				assertCorrectMessage(t, emptyAsRoot(got), want)
+			}
+		}
+		tr := ToTransducer(identityOver([]rune("01")))
+		_, err := tr.Apply("012")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Transducer DOT test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		want := "digraph transducer {\n\trankdir=LR;\n\tstart [shape=point];\n" +
+			"\tq0 [label=\"𝛆\"];\n\tq1 [label=\"1\"];\n\tq2 [label=\"id\"];\n\tstart -> q0;\n" +
+			"\tq0 -> q2 [label=\"0|00\"];\n\tq0 -> q1 [label=\"1|𝛆\"];\n" +
+			"\tq1 -> q2 [label=\"0|01\"];\n\tq1 -> q2 [label=\"1|1\"];\n" +
+			"\tq2 -> q2 [label=\"0|0\"];\n\tq2 -> q2 [label=\"1|1\"];\n}\n"
+		assertCorrectMessage(t, ToTransducer(x0).DOT(), want)
+	})
+}
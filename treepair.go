@@ -1,9 +1,14 @@
 package treepair
 
 import (
-	"fmt"
+	"errors"
+	"hash/fnv"
+	"io"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/loeksnokes/prefcode"
 )
@@ -25,7 +30,9 @@ Can:
  3. ResetLabels sets the
     domain tree labels as 0 1 ... k-1 corresponding to its dictionary order and
     modifies range tree so the initial prefix map is unchanged.
- 4. Minimise and Minimize (same function but for British English or American English.)
+ 4. Minimise and Minimize (same function but for British English or American English.), and
+    decide whether it is minimal without changing it.  MinimiseTrace also returns the carets
+    reduced.
  5. Multiply tree pairs based off of same alphabet.
  6. Invert an element.
  7. Detect if the element is in F, T, or V.
@@ -34,57 +41,213 @@ Can:
  10. Initialise from Full representation string: e.g. "D: [00 0], [01 1], [1 2], R: [0 1], [10 2], [11 0]"
  11. Return domain/range permutations (natural permutation from prefix code in
     dictionary order to the numeric labels of leaves)
+ 12. Detect if the element is the identity.
+ 13. Return the number of roots of its trees, always 1 (see ForestPair for the groups G(n,r)).
+ 14. Return the DFS notation representation string, as read by EncodeDFS.
+ 15. Return a canonical string and hash of the element, the same for every tree pair
+    representing it, for use as map keys.
+ 16. Visit the domain leaves in dictionary order together with their labels.
+ 17. Visit the pairs (domain leaf, range leaf) of the prefix map, in dictionary order of
+    the domain leaves.
+ 18. Write the prefix map as CSV rows (domain leaf, range leaf, label).
+ 19. Minimise itself automatically after every expansion and permutation, if asked to.
+ 20. Expand its domain tree (and correspondingly its range tree) to refine a given prefix code,
+    or to the full tree of a given depth.
+ 21. Return the greatest and least depths of the leaves of the domain and range trees of its
+    minimal tree pair, and statistics of their shapes.
+ 22. Report every expansion, reduction and permutation made to it to an Observer.
+ 23. Keep a history of its changes, to undo and redo them.
 */
 type TreePair interface {
 	Alphabet() []rune
 	ApplyPermDomain(perm map[int]int) bool
 	ApplyPermRange(perm map[int]int) bool
+	AutoMinimise() bool
+	CanonicalString() string
 	CodeDomain() prefcode.PrefCode
 	CodeRange() prefcode.PrefCode
+	DepthRange() (dom, ran int)
 	Equals(tp *TreePair) bool
 	ExpandRangeAt(s string)
 	ExpandDomainAt(s string)
+	ExpandDomainToCode(code prefcode.PrefCode) error
+	ExpandToDepth(k int) error
 	ExposedCarets() []string
 	FullString() string
+	Hash() uint64
 	InF() bool
 	InT() bool
 	InV() bool
 	Invert()
+	IsIdentity() bool
+	IsMinimal() bool
+	Leaves(visit func(leaf string, label int) bool) bool
+	MaxDepth() (dom, ran int)
+	MinDepth() (dom, ran int)
 	Minimise()
+	MinimiseTrace() (dom, ran []string)
 	Minimize()
+	MutableCodeDomain() prefcode.PrefCode
+	MutableCodeRange() prefcode.PrefCode
+	Redo() bool
+	Pairs(visit func(domainLeaf, rangeLeaf string) bool) bool
 	PermuteLabels(perm map[int]int) bool
 	ResetLabels() bool
+	Roots() int
 	ReduceDomainAt(s string) bool
 	ReduceRangeAt(s string) bool
 	Size() int
+	SetAutoMinimise(on bool)
+	SetHistory(on bool)
+	SetObserver(o Observer)
+	Stats() ShapeStats
 	SwapPermAtRangeKeys(a, b string) bool
 	SwapPermAtDomainKeys(a, b string) bool
-	// DFSString() string
+	DFSString() string
+	Undo() bool
+	WriteCSV(w io.Writer) error
 }
 
 type treePair struct {
 	alphabet []rune
-	dom      prefcode.PrefCode
-	ran      prefcode.PrefCode
+	// codePair holds the codes dom and ran.  It is a pointer so that the value receivers
+	// share it, with the trees it keeps them as and the prefix codes read off those.
+	*codePair
+	// autoMinimise is set by SetAutoMinimise.  Copies made by clone do not inherit it.
+	autoMinimise bool
+	// observer is set by SetObserver.  Copies made by clone do not inherit it.
+	observer Observer
+	// history is set by SetHistory, and shared by the value receivers.  Copies made by clone
+	// do not inherit it.
+	history *history
+	// perms caches the permutations of dom and ran.  It is a pointer so that the value
+	// receivers share it, as they share the codes.
+	perms *permCache
+}
+
+// codePair holds the domain and range codes of a tree pair.  It keeps them as hash-consed
+// caret trees, shared by the copies made by clone, and as prefix codes dom and ran of its
+// own, read off the trees the first time they are wanted.  A change is made to dom and ran
+// and drops the trees, which are built again, sharing every subtree the change left alone,
+// when the tree pair is next copied.  mu guards reading off and building, which goroutines
+// sharing the tree pair may do together.
+type codePair struct {
+	mu       sync.Mutex
+	dom, ran prefcode.PrefCode
+	trees    *codeTrees
+}
+
+// newCodePair returns a codePair holding dom and ran alone.
+func newCodePair(dom, ran prefcode.PrefCode) *codePair {
+	return &codePair{dom: dom, ran: ran}
+}
+
+// codes returns the prefix codes of c, reading them off its trees if need be.  They are not
+// to be changed in place unless own has been called.
+func (c *codePair) codes() (dom, ran prefcode.PrefCode) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if nil == c.dom {
+		c.dom, c.ran = c.trees.codes()
+	}
+	return c.dom, c.ran
+}
+
+// codeAlphabet returns a copy of the alphabet of the codes of c, without reading the codes
+// off its trees.
+func (c *codePair) codeAlphabet() []rune {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if nil == c.dom {
+		return append([]rune(nil), c.trees.store.alphabet...)
+	}
+	return c.dom.Alphabet()
+}
+
+// share returns a codePair holding the trees of c, building them first if c has changed
+// since they were.
+func (c *codePair) share() *codePair {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if nil == c.trees {
+		c.trees = newCodeTrees(c.dom, c.ran)
+	}
+	return &codePair{trees: c.trees}
+}
+
+// own readies the prefix codes of c to be changed in place, dropping the trees, which no
+// longer follow them.
+func (c *codePair) own() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if nil == c.dom {
+		c.dom, c.ran = c.trees.codes()
+	}
+	c.trees = nil
+}
+
+// invert swaps the domain and range codes of c.
+func (c *codePair) invert() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dom, c.ran = c.ran, c.dom
+	if nil != c.trees {
+		c.trees = c.trees.inverse()
+	}
+}
+
+// permCache holds the permutations of the codes of a tree pair once they have been read off.
+// A nil map has not been read off since its code last changed.  The mutex lets elements
+// shared between goroutines fill the cache as they are read.
+type permCache struct {
+	mu       sync.Mutex
+	dom, ran map[int]int
 }
 
 // NewTreePairAlpha returns a treepair as a TreePair and sets alphabet of runes by input string.
 func NewTreePairAlpha(alphaStr string) (*treePair, error) {
 	dpc, errd := prefcode.NewPrefCodeAlphaString(alphaStr)
-	rpc, errr := prefcode.NewPrefCodeAlphaString(alphaStr)
 	if nil != errd {
-		outStr := "NewTreePairAlpha(): Failed to create domaintree from " + alphaStr
-		fmt.Println(outStr)
 		return nil, errd
 	}
+	rpc, errr := prefcode.NewPrefCodeAlphaString(alphaStr)
 	if nil != errr {
-		outStr := "NewTreePairAlpha(): Failed to create rangetree from " + alphaStr
-		fmt.Println(outStr)
 		return nil, errr
 	}
 	return &treePair{alphabet: prefcode.StringToRuneSlice(alphaStr),
-		dom: dpc,
-		ran: rpc}, nil
+		codePair: newCodePair(dpc, rpc),
+		perms:    &permCache{}}, nil
+}
+
+// permutations returns the permutations of the domain and range codes, as their Permutation
+// methods do, reading each off the code only if it is not cached.  The maps are shared with
+// the cache and must not be modified.
+func (tp treePair) permutations() (dom, ran map[int]int) {
+	if nil == tp.perms {
+		return tp.CodeDomain().Permutation(), tp.CodeRange().Permutation()
+	}
+	tp.perms.mu.Lock()
+	defer tp.perms.mu.Unlock()
+	if nil == tp.perms.dom {
+		tp.perms.dom = tp.CodeDomain().Permutation()
+	}
+	if nil == tp.perms.ran {
+		tp.perms.ran = tp.CodeRange().Permutation()
+	}
+	return tp.perms.dom, tp.perms.ran
+}
+
+// changed empties the cache of permutations, once the codes of tp have changed or have been
+// handed out to be changed.  It is called before the change, and first makes the codes of tp
+// its own.
+func (tp treePair) changed() {
+	tp.own()
+	if nil == tp.perms {
+		return
+	}
+	tp.perms.mu.Lock()
+	tp.perms.dom, tp.perms.ran = nil, nil
+	tp.perms.mu.Unlock()
 }
 
 // EncodeDFS returns a treepair from an alphabet string (like "01") and a DFS string like
@@ -95,114 +258,161 @@ func NewTreePairAlpha(alphaStr string) (*treePair, error) {
 // 00 -> 11
 // 01 -> 0
 // 1 -> 10
-// in this example.  Code verifies that the DFS strings work for alphabet cardinality along the way.
+// in this example.  Code verifies that the DFS strings work for alphabet cardinality along the way,
+// and that the permutation labels every leaf, returning false otherwise.  See EncodeDFSMode for
+// the reason a string is rejected, and for a lenient reading.
 func EncodeDFS(tp TreePair, DFS string) bool {
-
-	//fmt.Println("Encode DFS: " + DFS)
-	s := strings.Split(DFS, ",")
-	//a do nothing tree pair since the DFS was poorly formatted.
-	if len(s) != 3 {
-		fmt.Println(DFS + " did not have three fields between commas.")
-		return false
-	}
-	if !strings.HasPrefix(s[0], "{") || !strings.HasSuffix(s[2], "}") {
-		fmt.Println(DFS + " did not have first field starting with `{`." +
-			"or final field did not end with `}`.")
-		return false
-	}
-	s[0] = strings.TrimPrefix(s[0], "{")
-	s[2] = strings.TrimSuffix(s[2], "}")
-
-	//fmt.Println("Encode DFS: s[0]: " + s[0])
-	//fmt.Println("Encode DFS: s[1]: " + s[1])
-	//fmt.Println("tp.FullString():" + tp.FullString())
-
-	alphaSize := len(tp.Alphabet())
-	if !prefcode.ValidDFSForPrefC(alphaSize, s[0]) ||
-		!prefcode.ValidDFSForPrefC(alphaSize, s[1]) {
-		return false
-	}
-	//fmt.Println("Encode DFS: Valid codes!")
-
-	if !prefcode.DFSToPrefCode(tp.CodeDomain(), s[0]) {
-		return false
-	}
-	//fmt.Println("Encoded Domain code")
-	//fmt.Println("Resulting tp: " + tp.FullString())
-	if !prefcode.DFSToPrefCode(tp.CodeRange(), s[1]) {
-		return false
-	}
-	//fmt.Println("Encoded Range code")
-	//fmt.Println("Resulting tp: " + tp.FullString())
-
-	perm := make(map[int]int, (len(s[2])+1)/2)
-	permNumStrings := strings.Split(s[2], " ")
-
-	//fmt.Println("Encoding permutation at range: " + s[2])
-
-	//apply permutation to range from DFSString
-	for k, v := range permNumStrings {
-		pv, err := strconv.Atoi(v)
-		if err != nil {
-			fmt.Println("NewTreePair DFS: bad perm conversion")
-			return false
-		}
-		perm[k] = pv
-	}
-	tp.ApplyPermRange(perm)
-	//fmt.Println("Resulting tp: " + tp.FullString())
-	return true
+	return nil == EncodeDFSMode(tp, DFS, DFSStrict)
 }
 
 // returns a ptr to a copy of the alphabet runes.
 func (tp treePair) Alphabet() []rune {
-	retVal := tp.dom.Alphabet()
-	return retVal
+	return tp.codeAlphabet()
 }
 
-// CodeDomain returns a ptr to the prefcode in domain
+// CodeDomain returns a ptr to the prefcode in domain, to be read only: clones of tp are made
+// from trees kept beside it, which changes made to it would not reach.  Use MutableCodeDomain
+// to change it.
 func (tp treePair) CodeDomain() prefcode.PrefCode {
-	return tp.dom
+	dom, _ := tp.codes()
+	return dom
 }
 
-// CodeRange  returns a ptr to the prefcode in range
+// CodeRange  returns a ptr to the prefcode in range, to be read only as for CodeDomain.
 func (tp treePair) CodeRange() prefcode.PrefCode {
-	return tp.ran
+	_, ran := tp.codes()
+	return ran
+}
+
+// MutableCodeDomain returns a ptr to the prefcode in domain, to be changed in place.  It first
+// drops the trees tp shares with its clones.  Changes made through it are seen by tp until tp is
+// next cloned or changed: ask for the code again before changing it later.
+func (tp treePair) MutableCodeDomain() prefcode.PrefCode {
+	tp.changed()
+	return tp.CodeDomain()
+}
+
+// MutableCodeRange returns a ptr to the prefcode in range, to be changed as for
+// MutableCodeDomain.
+func (tp treePair) MutableCodeRange() prefcode.PrefCode {
+	tp.changed()
+	return tp.CodeRange()
 }
 
 func (tp treePair) FullString() (fullString string) {
-	fullString = "{D: " + tp.dom.String() + " || R: " + tp.ran.String() + "}"
+	fullString = "{D: " + tp.CodeDomain().String() + " || R: " + tp.CodeRange().String() + "}"
 	return
 }
 
 // Equals compares a treepair to an input treepair as formal combinatorial objects.
-// It is not a comparison of maps.  For that, one should minimise both tree pairs first.
+// It is not a comparison of maps.  For that, use EqualsAsElements.
 func (tp treePair) Equals(tpp *TreePair) bool {
 	return tp.FullString() == (*tpp).FullString()
 }
 
+// EqualsAsElements reports whether a and b are over the same alphabet and represent the same
+// element, whatever their trees and labels: their canonical strings agree.  Neither is
+// modified.
+func EqualsAsElements(a, b TreePair) bool {
+	return string(a.Alphabet()) == string(b.Alphabet()) && canonicalString(a) == canonicalString(b)
+}
+
+// EqualsAsMaps reports whether a and b are over the same alphabet and induce the same map,
+// without minimising either: both leaf maps are expanded to the join of their domain trees,
+// where the images of the common leaves are compared.  It agrees with EqualsAsElements, and
+// serves as a cross-check on reduction.  Neither is modified.
+func EqualsAsMaps(a, b TreePair) bool {
+	alpha := a.Alphabet()
+	if string(alpha) != string(b.Alphabet()) {
+		return false
+	}
+	ma, mb := newLeafMap(a), newLeafMap(b)
+	for refinedA, refinedB := true, true; refinedA || refinedB; {
+		ma, refinedA = ma.refineBelow(alpha, mb)
+		mb, refinedB = mb.refineBelow(alpha, ma)
+	}
+	for k, v := range ma {
+		if mb[k] != v {
+			return false
+		}
+	}
+	return len(ma) == len(mb)
+}
+
+// refineBelow expands m at each of its domain leaves having a leaf of other below it, and
+// reports whether it expanded any.
+func (m leafMap) refineBelow(alpha []rune, other leafMap) (leafMap, bool) {
+	refined := false
+	for _, k := range sortedKeys(m) {
+		if hasLeafBelow(other, k) {
+			m = m.expandAt(alpha, k)
+			refined = true
+		}
+	}
+	return m, refined
+}
+
+// SetAutoMinimise switches automatic minimisation on or off.  While it is on, tp is minimised
+// straight away and again after every ApplyPermDomain, ApplyPermRange, ExpandDomainAt and
+// ExpandRangeAt, so in particular expansions are undone at once.  Products made by Multiply
+// inherit the setting of the first factor.
+func (tp *treePair) SetAutoMinimise(on bool) {
+	tp.autoMinimise = on
+	tp.autoReduce()
+}
+
+// AutoMinimise reports whether tp minimises itself automatically.
+func (tp treePair) AutoMinimise() bool {
+	return tp.autoMinimise
+}
+
+// autoReduce minimises tp if it minimises itself automatically.
+func (tp treePair) autoReduce() {
+	if tp.autoMinimise {
+		tp.Minimise()
+	}
+}
+
 // ApplyPermDomain acts by permutation on labels of domain tree
 func (tp treePair) ApplyPermDomain(perm map[int]int) bool {
-	return tp.dom.ApplyPerm(perm)
+	defer record(&tp)()
+	defer tp.autoReduce()
+	tp.changed()
+	ok := tp.CodeDomain().ApplyPerm(perm)
+	if ok && nil != tp.observer {
+		tp.observer.OnPermute(true, perm)
+	}
+	return ok
 }
 
 // ApplyPermRange acts by permutation on labels of range tree
 func (tp treePair) ApplyPermRange(perm map[int]int) bool {
-	return tp.ran.ApplyPerm(perm)
+	defer record(&tp)()
+	defer tp.autoReduce()
+	tp.changed()
+	ok := tp.CodeRange().ApplyPerm(perm)
+	if ok && nil != tp.observer {
+		tp.observer.OnPermute(false, perm)
+	}
+	return ok
 }
 
 // PermuteLabels acts by same permutation on labels of domain and range tree
 func (tp treePair) PermuteLabels(perm map[int]int) bool {
-	domSuccess := tp.ApplyPermDomain(perm)
-	ranSuccess := tp.ApplyPermRange(perm)
+	defer record(&tp)()
+	tp.changed()
+	domSuccess := tp.CodeDomain().ApplyPerm(perm)
+	ranSuccess := tp.CodeRange().ApplyPerm(perm)
 	return domSuccess && ranSuccess
 }
 
 // ResetLabels applies the same permutation to the labels of domain and range tree
 // so that the resulting permutation on domain tree corresponds to the natural
-// dictionary order on that prefix code.
+// dictionary order on that prefix code.  The permutations afterwards follow from those before,
+// so they stay cached.
 func (tp treePair) ResetLabels() bool {
-	currentPerm := tp.dom.Permutation()
+	defer record(&tp)()
+	currentPerm, rangePerm := tp.permutations()
 	permSize := len(currentPerm)
 	inversePerm := make(map[int]int, permSize)
 
@@ -210,19 +420,90 @@ func (tp treePair) ResetLabels() bool {
 		inversePerm[v] = k
 	}
 
-	return tp.PermuteLabels(inversePerm)
+	if !tp.PermuteLabels(inversePerm) {
+		return false
+	}
+	if nil != tp.perms {
+		identity := make(map[int]int, permSize)
+		reset := make(map[int]int, permSize)
+		for k, v := range rangePerm {
+			identity[k] = k
+			reset[k] = inversePerm[v]
+		}
+		tp.perms.mu.Lock()
+		tp.perms.dom, tp.perms.ran = identity, reset
+		tp.perms.mu.Unlock()
+	}
+	return true
 }
 
 // Invert returns the inverse tree-pair element.  Labels are not reset.
 func (tp *treePair) Invert() {
-	tp.dom, tp.ran = tp.ran, tp.dom
+	defer record(tp)()
+	tp.invert()
+	if nil != tp.perms {
+		tp.perms.mu.Lock()
+		tp.perms.dom, tp.perms.ran = tp.perms.ran, tp.perms.dom
+		tp.perms.mu.Unlock()
+	}
+}
+
+// IsIdentity assesses if elmt is the identity: a minimised copy is the trivial tree pair.
+func (tp *treePair) IsIdentity() bool {
+	min := clone(tp)
+	min.Minimise()
+	_, domTrivial := min.CodeDomain().Code()[prefcode.EmptyString]
+	_, ranTrivial := min.CodeRange().Code()[prefcode.EmptyString]
+	return domTrivial && ranTrivial
+}
+
+// Leaves calls visit on each domain leaf in dictionary order, with its label, until visit
+// returns false, and reports whether every leaf was visited.  The root of a trivial tree is
+// written prefcode.EmptyString, as in FullString.
+func (tp *treePair) Leaves(visit func(leaf string, label int) bool) bool {
+	for _, v := range sortedLeaves(tp.CodeDomain()) {
+		if !visit(v, tp.CodeDomain().LabelAtLeaf(v)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Pairs calls visit on each domain leaf in dictionary order, with the range leaf the prefix
+// map carries it to, until visit returns false, and reports whether every pair was visited.
+func (tp *treePair) Pairs(visit func(domainLeaf, rangeLeaf string) bool) bool {
+	for _, v := range leafPairs(tp) {
+		if !visit(v[0], v[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// CanonicalString returns the full string of the minimised tree pair with labels reset, which
+// is the same for every tree pair representing the element.  tp itself is not modified.
+func (tp *treePair) CanonicalString() string {
+	return canonicalString(tp)
+}
+
+// Hash returns the 64-bit FNV-1a hash of the canonical string of tp, which is stable across
+// runs.  Distinct elements may share a hash, so compare canonical strings to be sure.
+func (tp *treePair) Hash() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(canonicalString(tp)))
+	return h.Sum64()
+}
+
+// Roots returns the number of roots of the trees of tp, which is 1: tp is an element of
+// V_n = G(n,1).
+func (tp *treePair) Roots() int {
+	return 1
 }
 
 // InF assesses if elmt is in R. Thompson's group F
 // does not relabel the element
 func (tp *treePair) InF() bool {
-	domainPerm := (*tp).CodeDomain().Permutation()
-	rangePerm := (*tp).CodeRange().Permutation()
+	domainPerm, rangePerm := tp.permutations()
 	lrp := len(rangePerm)
 
 	for k := 0; k < lrp; k++ {
@@ -236,8 +517,7 @@ func (tp *treePair) InF() bool {
 // InF assesses if elmt is in R. Thompson's group F
 // does not relabel the element
 func (tp *treePair) InT() bool {
-	domainPerm := (*tp).CodeDomain().Permutation()
-	rangePerm := (*tp).CodeRange().Permutation()
+	domainPerm, rangePerm := tp.permutations()
 	lrp := len(rangePerm)
 
 	//makes a double copy of rangePerm
@@ -301,9 +581,10 @@ func (tp *treePair) InV() bool { return true }
 // **In all cases has a side effect of resetting labels (even if no reduction is possible).**
 // true if reduction occurred, false if it was not possible.
 func (tp treePair) ReduceDomainAt(s string) bool {
+	defer record(&tp)()
 	tp.ResetLabels()
 
-	reductionSpots := tp.dom.ExposedCarets()
+	reductionSpots := exposedCarets(tp.CodeDomain())
 
 	sRootOfExposedCaret := false
 	for _, v := range reductionSpots {
@@ -319,16 +600,17 @@ func (tp treePair) ReduceDomainAt(s string) bool {
 	// alphabet size is a, and labels are in order.  Check if the corresponding leaves in
 	// range are the leaves of an exposed caret.
 	firstLeaf := s + string(tp.alphabet[0])
-	leftLeafLabelDomain := tp.dom.LabelAtLeaf(firstLeaf)
-	firstImageLeaf := tp.ran.LeafAtLabel(leftLeafLabelDomain)
+	leftLeafLabelDomain := tp.CodeDomain().LabelAtLeaf(firstLeaf)
+	firstImageLeaf := tp.CodeRange().LeafAtLabel(leftLeafLabelDomain)
 
 	if "" == firstImageLeaf {
 		return false
 	}
 
-	rangeRoot := firstImageLeaf[:len(firstImageLeaf)-1]
+	_, lastSize := utf8.DecodeLastRuneInString(firstImageLeaf)
+	rangeRoot := firstImageLeaf[:len(firstImageLeaf)-lastSize]
 	for k, v := range tp.alphabet {
-		if (leftLeafLabelDomain + k) != tp.ran.LabelAtLeaf(rangeRoot+string(v)) {
+		if (leftLeafLabelDomain + k) != tp.CodeRange().LabelAtLeaf(rangeRoot+string(v)) {
 			return false
 		}
 	}
@@ -338,8 +620,13 @@ func (tp treePair) ReduceDomainAt(s string) bool {
 	// a,a+1,...,a+(alphaSize-1)
 
 	//Payload!  Reduce on both sides!!
-	tp.dom.ReduceAt(s)
-	tp.ran.ReduceAt(rangeRoot)
+	tracef("ReduceDomainAt(): reducing domain caret at %q and range caret at %q", s, rangeRoot)
+	tp.changed()
+	reduceCodeAt(tp.CodeDomain(), s)
+	reduceCodeAt(tp.CodeRange(), rangeRoot)
+	if nil != tp.observer {
+		tp.observer.OnReduce(s, rangeRoot)
+	}
 
 	//reindex from domain tree (this should actually do nothing!)
 	tp.ResetLabels()
@@ -349,6 +636,8 @@ func (tp treePair) ReduceDomainAt(s string) bool {
 // ReduceRangeAt reduces treepair tp at exposed caret in range if the preimage set is
 // also an exposed caret of leaves listed with same corresponding labels.
 func (tp treePair) ReduceRangeAt(s string) bool {
+	defer record(&tp)()
+	tp.observer = inverted(tp.observer)
 	tp.Invert()
 	wasReduced := tp.ReduceDomainAt(s)
 	tp.Invert()
@@ -361,110 +650,312 @@ func (tp treePair) ReduceRangeAt(s string) bool {
 // permutations are expanded correspondingly.  If s is shallower than leaves of Domain tree
 // then nothing happens.
 func (tp treePair) ExpandDomainAt(s string) {
-	prefixLeaf := tp.dom.GetPrefixOf(s)
+	defer record(&tp)()
+	tp.expandDomainAt(s)
+	tp.autoReduce()
+}
+
+// ExpandDomainToCode expands the domain tree of tp at each caret of the tree of code, and
+// the range tree correspondingly, so that the domain code refines code; carets already in the
+// domain tree are left alone.  A code over another alphabet gives an error.  An element
+// minimising itself automatically (see SetAutoMinimise) minimises again afterwards, as for
+// ExpandDomainAt.
+func (tp treePair) ExpandDomainToCode(code prefcode.PrefCode) error {
+	defer record(&tp)()
+	if string(tp.Alphabet()) != string(code.Alphabet()) {
+		return errors.New("ExpandDomainToCode(): code is not over the alphabet " + string(tp.Alphabet()))
+	}
+	carets := make([]string, 0, code.Size())
+	for v := range caretsOf(code) {
+		carets = append(carets, v)
+	}
+	for _, v := range shallowFirst(carets) {
+		tp.expandDomainAt(v)
+	}
+	tp.autoReduce()
+	return nil
+}
+
+// ExpandToDepth expands tp so that its domain tree is the full tree of depth k, every domain
+// leaf having length k, and its range tree correspondingly.  The prefix map is then a
+// permutation of the words of length k followed by a change of suffix at each.  A domain leaf
+// deeper than k, or a negative k, gives an error: minimise tp first to expand as little as
+// possible.  An element minimising itself automatically (see SetAutoMinimise) minimises
+// again afterwards, as for ExpandDomainAt.
+func (tp treePair) ExpandToDepth(k int) error {
+	defer record(&tp)()
+	if k < 0 {
+		return errors.New("ExpandToDepth(): depth " + strconv.Itoa(k) + " is negative")
+	}
+	for leaf := range tp.CodeDomain().Code() {
+		if wordLength(leaf) > k {
+			return errors.New("ExpandToDepth(): domain leaf " + leaf + " is deeper than " + strconv.Itoa(k))
+		}
+	}
+	for expanded := true; expanded; {
+		expanded = false
+		for _, leaf := range sortedLeaves(tp.CodeDomain()) {
+			if wordLength(leaf) < k {
+				tp.expandDomainAt(rootAsEmpty(leaf))
+				expanded = true
+			}
+		}
+	}
+	tp.autoReduce()
+	return nil
+}
+
+// expandDomainAt is ExpandDomainAt without automatic minimisation.
+func (tp treePair) expandDomainAt(s string) {
+	prefixLeaf := tp.CodeDomain().GetPrefixOf(s)
 	lenPref := len(prefixLeaf)
 
 	// s was too shallow
-	if "" == prefixLeaf && prefcode.EmptyString != tp.dom.LeafAtLabel(0) {
+	if "" == prefixLeaf && prefcode.EmptyString != tp.CodeDomain().LeafAtLabel(0) {
 		return
 	}
 
 	suffix := s[lenPref:]
 
-	permValue := tp.dom.LabelAtLeaf(prefixLeaf)
-	newPrefix := tp.ran.LeafAtLabel(permValue)
+	permValue := tp.CodeDomain().LabelAtLeaf(prefixLeaf)
+	newPrefix := tp.CodeRange().LeafAtLabel(permValue)
 
 	ranExpandPt := newPrefix + suffix
 
-	tp.dom.ExpandAt(s)
-	tp.ran.ExpandAt(ranExpandPt)
+	tracef("ExpandDomainAt(): expanding domain at %q and range at %q", s, ranExpandPt)
+	tp.changed()
+	tp.CodeDomain().ExpandAt(s)
+	tp.CodeRange().ExpandAt(ranExpandPt)
+	if nil != tp.observer {
+		tp.observer.OnExpand(s, ranExpandPt)
+	}
 
 	return
 }
 
 // ExpandRanAt expands the treepair if s is  a leaf or is deeper that the range tree code.
 func (tp treePair) ExpandRangeAt(s string) {
+	defer record(&tp)()
+	observer := tp.observer
+	tp.observer = inverted(observer)
 	tp.Invert()
-	tp.ExpandDomainAt(s)
+	tp.expandDomainAt(s)
 	tp.Invert()
-	return
+	tp.observer = observer
+	tp.autoReduce()
 }
 
-// Multiply returns a new TreePair that is the product of the two that are fed in.
+// ErrBudgetExceeded is returned by MultiplyWithBudget when the product would need more
+// leaves than allowed.
+var ErrBudgetExceeded = errors.New("product exceeds the leaf budget")
+
+// Multiply returns a new, minimised TreePair that is the product of the two that are fed in.
+// The factors are minimised too.  The product minimises itself automatically if first does.
+// Each pair d -> r of first is composed with second: directly if r is a domain leaf of
+// second, through the leaf above r if there is one, and otherwise by expanding d and r at the
+// carets of second below r, so the expansion work is proportional to the new carets.
+// Multiply panics if the factors are over different alphabets: MultiplyAll and
+// MultiplyWithBudget report that as an error instead.
 func Multiply(first, second TreePair) *treePair {
-
-	fmt.Println("first: " + first.FullString())
-	fmt.Println("second: " + second.FullString())
-	fmt.Println("%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%")
-	fmt.Println("%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%")
-	//build steady labelling
-	first.ResetLabels()
-	second.ResetLabels()
-
-	// Make a prefix code that is join of range of first element and domain of second element
-	fmt.Println("First Range: " + first.CodeRange().String())
-	fmt.Println("Second Domain: " + second.CodeDomain().String())
-	fullCode, err := first.CodeRange().Join(second.CodeDomain())
+	answer, err := multiply(first, second, 0)
 	if nil != err {
-		panic("Multiply(): err return for join")
+		panic(err)
 	}
-	fmt.Println("%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%")
-	fmt.Println("%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%")
-	fmt.Println("Join D-R code: " + fullCode.String())
+	return answer
+}
 
-	//for each leaf of the join tree, force it to be a leaf in range first/domain second
-	for key, _ := range fullCode.Code() {
-		first.ExpandRangeAt(key)
-		second.ExpandDomainAt(key)
+// MultiplyWithBudget is Multiply, but gives up with ErrBudgetExceeded as soon as the factors
+// or the expanded product, before it is minimised, have more than maxLeaves leaves.  The
+// factors are then left as they were.  Factors over different alphabets give an error.
+func MultiplyWithBudget(first, second TreePair, maxLeaves int) (*treePair, error) {
+	if maxLeaves < 1 {
+		return nil, errors.New("MultiplyWithBudget(): budget of " + strconv.Itoa(maxLeaves) + " leaves is not positive")
 	}
+	return multiply(first, second, maxLeaves)
+}
 
-	fmt.Println("%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%")
-	fmt.Println("%%%%%%%%%%%%%%%%%%%%%%%%%%%%%%")
-	fmt.Println("Expanded treepairs")
-	fmt.Println("first: " + first.FullString())
-	fmt.Println("second: " + second.FullString())
+// multiply is Multiply within a budget of maxLeaves leaves, where 0 means no budget.
+func multiply(first, second TreePair, maxLeaves int) (*treePair, error) {
+	// composing over different alphabets would expand at carets the other factor never has.
+	if string(first.Alphabet()) != string(second.Alphabet()) {
+		return nil, errors.New("Multiply(): elements are over different alphabets " +
+			string(first.Alphabet()) + " and " + string(second.Alphabet()))
+	}
 
-	// align the permutation of domain of second element to the permutation on range of first element.
-	second.PermuteLabels(first.CodeRange().Permutation())
+	if tracing() {
+		tracef("Multiply(): first: %s", first.FullString())
+		tracef("Multiply(): second: %s", second.FullString())
+	}
+	if 0 != maxLeaves && (first.Size() > maxLeaves || second.Size() > maxLeaves) {
+		return nil, ErrBudgetExceeded
+	}
+	alpha := first.Alphabet()
+	f, g := pooledLeafMap(first), pooledLeafMap(second)
 
-	answer := treePair{alphabet: first.Alphabet(), dom: first.CodeDomain(), ran: second.CodeRange()}
+	product := emptyPooledLeafMap()
+	within := true
+	for d, r := range f {
+		if within = composeLeaf(alpha, g, d, r, true, product, maxLeaves); !within {
+			break
+		}
+	}
+	releaseLeafMap(f)
+	releaseLeafMap(g)
+	if !within {
+		tracef("Multiply(): expanded product exceeds %d leaves", maxLeaves)
+		releaseLeafMap(product)
+		return nil, ErrBudgetExceeded
+	}
+	tracef("Multiply(): expanded product has %d leaves", len(product))
+	product.minimise(func(rune) []rune { return alpha })
+
+	answer := identityOver(alpha)
+	answer.setLeafMap(product)
+	releaseLeafMap(product)
+	answer.autoMinimise = first.AutoMinimise()
 
 	first.Minimise()
 	second.Minimise()
 
-	// return a new treepair with the correct domain, range, and permutation.
-	return &answer
+	if tracing() {
+		tracef("Multiply(): product: %s", answer.FullString())
+	}
+	return answer, nil
 }
 
-func Power(first TreePair, pow int) *treePair {
-	if pow == 0 {
-		// return the identity in a way that multiplies easily with previous
-		return &treePair{alphabet: first.Alphabet(), dom: first.CodeRange(), ran: first.CodeRange()}
+// composeLeaf adds to product the pairs of the composite of d -> r with the leaf map g,
+// expanding at the carets of g below r.  checkAbove says whether a domain leaf of g may lie
+// above r.  It stops, reporting false, once product has more than maxLeaves pairs, unless
+// maxLeaves is 0.
+func composeLeaf(alpha []rune, g leafMap, d, r string, checkAbove bool, product leafMap, maxLeaves int) bool {
+	if image, ok := g[r]; ok {
+		product[d] = image
+		return 0 == maxLeaves || len(product) <= maxLeaves
+	}
+	if checkAbove {
+		for i := len(r) - 1; i >= 0; i-- {
+			if !utf8.RuneStart(r[i]) {
+				continue
+			}
+			if image, ok := g[r[:i]]; ok {
+				product[d] = image + r[i:]
+				return 0 == maxLeaves || len(product) <= maxLeaves
+			}
+		}
+	}
+	for _, c := range alpha {
+		if !composeLeaf(alpha, g, d+string(c), r+string(c), false, product, maxLeaves) {
+			return false
+		}
 	}
+	return true
+}
+
+// Power returns first raised to the power pow, computed by square-and-multiply
+// with minimisation of the intermediate elements.  first itself is left unchanged.
+func Power(first TreePair, pow int) *treePair {
+	base := clone(first)
 	if pow < 0 {
-		first.Invert()
+		base.Invert()
 		pow *= -1
 	}
-	first.Minimise()
-	return Multiply(first, Power(first, pow-1))
+	base.Minimise()
+
+	answer := identityOver(base.Alphabet())
+	for pow > 0 {
+		if pow&1 == 1 {
+			answer = Multiply(answer, clone(base))
+			answer.Minimise()
+		}
+		pow >>= 1
+		if pow > 0 {
+			base = Multiply(base, clone(base))
+			base.Minimise()
+		}
+	}
+	return answer
 }
 
 // Minimise reduces a tree-pair.  Even if no reductions
 // are possible, the labels will be reset (domain tree labels
-// will appear in natural order)
+// will appear in natural order).  The reduction works on the leaf map, reducing each caret
+// as soon as it becomes reducible, and then writes the codes afresh.
 func (tp treePair) Minimise() {
-	domExposed := tp.dom.ExposedCarets()
+	defer record(&tp)()
+	alpha := tp.Alphabet()
+	m := pooledLeafMap(&tp)
+	size := len(m)
+	var reduced func(root, image string)
+	if nil != tp.observer {
+		reduced = tp.observer.OnReduce
+	}
+	m.minimiseReporting(func(rune) []rune { return alpha }, reduced)
+	tracef("Minimise(): reduced %d leaves to %d", size, len(m))
+	tp.setLeafMap(m)
+	releaseLeafMap(m)
+}
 
-	madeReduction := false
-	for _, v := range domExposed {
-		if tp.ReduceDomainAt(v) {
-			madeReduction = true
+// MinimiseTrace minimises tp as Minimise does, and returns the roots of the carets reduced
+// in the domain tree and in the range tree, in the order the reductions were made: the k-th
+// domain caret was carried onto the k-th range caret.  The root is written as "".
+func (tp treePair) MinimiseTrace() (dom, ran []string) {
+	defer record(&tp)()
+	alpha := tp.Alphabet()
+	m := pooledLeafMap(&tp)
+	m.minimiseReporting(func(rune) []rune { return alpha }, func(root, image string) {
+		dom = append(dom, root)
+		ran = append(ran, image)
+		if nil != tp.observer {
+			tp.observer.OnReduce(root, image)
+		}
+	})
+	tracef("MinimiseTrace(): made %d reductions", len(dom))
+	tp.setLeafMap(m)
+	releaseLeafMap(m)
+	return dom, ran
+}
+
+// IsMinimal reports whether tp is its minimal tree pair, that is whether no caret of its
+// domain tree is carried, letter by letter, onto a caret of its range tree.  Unlike
+// ReduceDomainAt and Minimise, it leaves tp alone, labels included.
+func (tp *treePair) IsMinimal() bool {
+	first := string(tp.alphabet[0])
+	m := pooledLeafMap(tp)
+	defer releaseLeafMap(m)
+	for d, r := range m {
+		if !strings.HasSuffix(d, first) || !strings.HasSuffix(r, first) {
+			continue
+		}
+		root, image := strings.TrimSuffix(d, first), strings.TrimSuffix(r, first)
+		reducible := true
+		for _, c := range tp.alphabet[1:] {
+			if v, ok := m[root+string(c)]; !ok || v != image+string(c) {
+				reducible = false
+				break
+			}
+		}
+		if reducible {
+			return false
 		}
 	}
-	if madeReduction { // if reductions occurred, new reductions can become possible.
-		tp.Minimise()
+	return true
+}
+
+// setLeafMap rewrites the codes of tp in place to carry out the leaf map m, labelling the
+// domain leaves in dictionary order.
+func (tp treePair) setLeafMap(m leafMap) {
+	tp.changed()
+	dom, ran := tp.CodeDomain().Code(), tp.CodeRange().Code()
+	for k := range dom {
+		delete(dom, k)
+	}
+	for k := range ran {
+		delete(ran, k)
+	}
+	for label, d := range sortedKeys(m) {
+		dom[emptyAsRoot(d)] = label
+		ran[emptyAsRoot(m[d])] = label
 	}
-	return
 }
 
 // Minimize This does Minimise, but For American English spellers
@@ -477,48 +968,164 @@ func (tp treePair) SwapPermAtRangeKeys(a, b string) bool  { return true }
 func (tp treePair) SwapPermAtDomainKeys(a, b string) bool { return true }
 
 // NewTreePairDFS(s string)
-func (tp treePair) ExposedCarets() []string { return tp.dom.ExposedCarets() }
-func (tp treePair) Size() int               { return tp.dom.Size() }
-func (tp treePair) DFSString() string       { return "Stuff" }
-
-func badSpeed(DFS string, cap int) (fast bool) {
-	fast = false
-	strLen := len(DFS)
-	//Empty string DFS is not allowed: returned as too Fast.
-	if strLen < 1 {
-		fmt.Println("badSpeed(): Tree description by DFS cannot be empty.")
-		return true
-	}
-	stackHeight := 1
-	limit := cap - 1
-	for ii, v := range DFS {
-		if `1` == string(v) {
-			stackHeight = stackHeight + cap - 1
+func (tp treePair) ExposedCarets() []string { return exposedCarets(tp.CodeDomain()) }
+func (tp treePair) Size() int               { return tp.CodeDomain().Size() }
+
+// DFSString returns the DFS notation of tp, e.g. "{11000,10100,1 2 0}": the shapes of the
+// domain and range trees followed by, for each range leaf in dictionary order, the position
+// of the domain leaf carried to it.  The trivial tree pair is "{0,0,0}".
+func (tp treePair) DFSString() string {
+	pairs := leafPairs(&tp)
+	dom := make(map[string]bool, len(pairs))
+	ran := make(map[string]bool, len(pairs))
+	position := make(map[string]int, len(pairs))
+	for k, v := range pairs {
+		dom[rootAsEmpty(v[0])] = true
+		ran[rootAsEmpty(v[1])] = true
+		position[v[1]] = k
+	}
+	perm := make([]string, 0, len(pairs))
+	for _, v := range sortedLeaves(tp.CodeRange()) {
+		perm = append(perm, strconv.Itoa(position[v]))
+	}
+	return "{" + codeDFS(dom, tp.alphabet, "") + "," + codeDFS(ran, tp.alphabet, "") + "," + strings.Join(perm, " ") + "}"
+}
+
+// copyCode returns a deep copy of the prefix code pc, labels included.
+func copyCode(pc prefcode.PrefCode) prefcode.PrefCode {
+	cpc, err := prefcode.NewPrefCodeAlphaRunes(pc.Alphabet())
+	if nil != err {
+		panic("copyCode(): could not rebuild code over alphabet " + string(pc.Alphabet()))
+	}
+	// prefixCode has value receivers, so the copy is filled through its map.
+	code := cpc.Code()
+	delete(code, prefcode.EmptyString)
+	for k, v := range pc.Code() {
+		code[k] = v
+	}
+	return cpc
+}
+
+// reduceCodeAt reduces pc at s.  prefcode's ReduceAt cannot collapse a code to
+// the root through its value receiver, so that case clears the map in place.
+func reduceCodeAt(pc prefcode.PrefCode, s string) bool {
+	if "" != s && prefcode.EmptyString != s {
+		return pc.ReduceAt(s)
+	}
+	code := pc.Code()
+	for k := range code {
+		delete(code, k)
+	}
+	code[prefcode.EmptyString] = 0
+	return true
+}
+
+// exposedCarets lists in dictionary order the roots of the carets of pc all of whose
+// children are leaves.  prefcode's own ExposedCarets compares concatenated decimal
+// labels against the alphabet size, which misfires once labels reach two digits.
+func exposedCarets(pc prefcode.PrefCode) []string {
+	alpha := pc.Alphabet()
+	code := pc.Code()
+	checked := make(map[string]bool, len(code))
+	var caretRoots []string
+	for leaf := range code {
+		if prefcode.EmptyString == leaf {
+			continue
+		}
+		leafRunes := []rune(leaf)
+		root := string(leafRunes[:len(leafRunes)-1])
+		if checked[root] {
 			continue
 		}
-		if `0` == string(v) { //certainly the case
-			stackHeight = stackHeight - 1
-			if 0 == stackHeight && ii < limit {
-				fmt.Println("badSpeed(): Tree description by DFS cannot be empty.")
-				return true
+		checked[root] = true
+		exposed := true
+		for _, a := range alpha {
+			if _, ok := code[root+string(a)]; !ok {
+				exposed = false
+				break
 			}
 		}
+		if exposed {
+			caretRoots = append(caretRoots, root)
+		}
 	}
-	if 0 == stackHeight {
-		return false
+	sort.Strings(caretRoots)
+	return caretRoots
+}
+
+// joinCodes returns the smallest prefix code refining both p and q.  It expands a
+// fresh code at the exposed carets of both, which recovers the union of their trees.
+func joinCodes(p, q prefcode.PrefCode) prefcode.PrefCode {
+	jpc, err := prefcode.NewPrefCodeAlphaRunes(p.Alphabet())
+	if nil != err {
+		panic("joinCodes(): could not build code over alphabet " + string(p.Alphabet()))
 	}
-	fmt.Println("badSpeed(): Tree description by DFS cannot have too many `1`'s.")
-	return true
+	for _, v := range exposedCarets(p) {
+		jpc.ExpandAt(v)
+	}
+	for _, v := range exposedCarets(q) {
+		jpc.ExpandAt(v)
+	}
+	return jpc
+}
+
+// sortedLeaves returns the leaves of pc in dictionary order.
+func sortedLeaves(pc prefcode.PrefCode) []string {
+	leaves := make([]string, 0, pc.Size())
+	for k := range pc.Code() {
+		leaves = append(leaves, k)
+	}
+	sort.Strings(leaves)
+	return leaves
+}
 
+// clone returns a copy of tp which can be mutated without touching tp.  A copy of a
+// *treePair shares the hash-consed trees of its codes, and starts with its cached
+// permutations, which are never modified in place.  Copying is then cheap enough to take
+// snapshots of the states of a search, and the snapshots of related states share most of
+// their trees.
+func clone(tp TreePair) *treePair {
+	t, ok := tp.(*treePair)
+	if !ok {
+		return &treePair{alphabet: tp.Alphabet(), codePair: newCodePair(copyCode(tp.CodeDomain()), copyCode(tp.CodeRange())), perms: &permCache{}}
+	}
+	c := &treePair{alphabet: t.Alphabet(), codePair: t.share(), perms: &permCache{}}
+	if nil != t.perms {
+		t.perms.mu.Lock()
+		c.perms.dom, c.perms.ran = t.perms.dom, t.perms.ran
+		t.perms.mu.Unlock()
+	}
+	return c
 }
 
-// Checks if A is less than or equal to B as tree-pairs.
-// Dictionary order on pair (size of domain tree, Full Description String)
-func LessEqual(tpA treePair, tpB treePair) bool {
-	if tpA.dom.Size() < tpB.dom.Size() {
-		return true
+// identityOver returns the trivial tree pair over the alphabet alpha.
+func identityOver(alpha []rune) *treePair {
+	tp, err := NewTreePairAlpha(string(alpha))
+	if nil != err {
+		panic("identityOver(): bad alphabet " + string(alpha))
 	}
-	AString := tpA.FullString()
-	BString := tpB.FullString()
-	return AString <= BString
+	return tp
+}
+
+// Compare orders elements, returning -1, 0 or 1 as a comes before, with, or after b.  It
+// compares the minimised tree pairs: first by size, then by canonical string, then by
+// alphabet.  This is a total order on elements, and Compare(a, b) is 0 exactly when
+// EqualsAsElements(a, b).  Neither is modified.
+func Compare(a, b TreePair) int {
+	minA, minB := canonicalCopy(a), canonicalCopy(b)
+	switch {
+	case minA.Size() < minB.Size():
+		return -1
+	case minA.Size() > minB.Size():
+		return 1
+	}
+	if c := strings.Compare(minA.FullString(), minB.FullString()); 0 != c {
+		return c
+	}
+	return strings.Compare(string(a.Alphabet()), string(b.Alphabet()))
+}
+
+// LessEqual reports whether A comes before or with B in the order of Compare.
+func LessEqual(tpA treePair, tpB treePair) bool {
+	return Compare(&tpA, &tpB) <= 0
 }
@@ -1,9 +1,13 @@
 package treepair
 
 import (
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/loeksnokes/prefcode"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -69,6 +73,20 @@ func Test(t *testing.T) {
 		assertCorrectMessage(t, got, want)
 	})
 
+	// DFSString reads back what EncodeDFS wrote.
+	t.Run("DFSString test", func(t *testing.T) {
+		for _, dfs := range []string{"{11000,10100,1 2 0}", "{1111000011000,1110100010100,0 1 2 3 4 5 6}", "{1100100,1110000,3 0 2 1}"} {
+			tp, _ := NewTreePairAlpha("01")
+			EncodeDFS(tp, dfs)
+			assertCorrectMessage(t, tp.DFSString(), dfs)
+		}
+		tp, _ := NewTreePairAlpha("012")
+		EncodeDFS(tp, "{1000,1000,2 0 1}")
+		assertCorrectMessage(t, tp.DFSString(), "{1000,1000,2 0 1}")
+		tp, _ = NewTreePairAlpha("01")
+		assertCorrectMessage(t, tp.DFSString(), "{0,0,0}")
+	})
+
 	// Minimise reduces tree pair
 	t.Run("Minimise test", func(t *testing.T) {
 		//reduces element to minimal tree pair.
@@ -253,6 +271,43 @@ func Test(t *testing.T) {
 		assertCorrectMessage(t, got, want)
 	})
 
+	// Power uses square-and-multiply and leaves its argument alone.
+	t.Run("Power test", func(t *testing.T) {
+		tp, err := NewTreePairAlpha("01")
+		if nil != err {
+			assertCorrectMessage(t, "Failed to NewTreePairAlpha('01')", " in Power test.")
+		}
+		EncodeDFS(tp, "{10100,11000,0 1 2}")
+		before := tp.FullString()
+
+		got := Power(tp, 3).FullString()
+		want := "{D: [0 0], [10 1], [110 2], [1110 3], [1111 4] || R: [0000 0], [0001 1], [001 2], [01 3], [1 4]}"
+		assertCorrectMessage(t, got, want)
+		assertCorrectMessage(t, tp.FullString(), before)
+
+		got = Power(tp, -2).FullString()
+		want = "{D: [000 0], [001 1], [01 2], [1 3] || R: [0 0], [10 1], [110 2], [111 3]}"
+		assertCorrectMessage(t, got, want)
+		assertCorrectMessage(t, tp.FullString(), before)
+
+		got = Power(tp, 0).FullString()
+		want = "{D: [𝛆 0] || R: [𝛆 0]}"
+		assertCorrectMessage(t, got, want)
+
+		assert.Equal(t, 102, Power(tp, 100).Size(), "x0^100 should have 102 leaves.")
+	})
+
+	// Power with a large exponent stays small for a torsion element.
+	t.Run("Power torsion test", func(t *testing.T) {
+		tp, err := NewTreePairAlpha("01")
+		if nil != err {
+			assertCorrectMessage(t, "Failed to NewTreePairAlpha('01')", " in Power torsion test.")
+		}
+		EncodeDFS(tp, "{10100,10100,1 2 0}")
+		assertCorrectMessage(t, Power(tp, 1000).FullString(), tp.FullString())
+		assertCorrectMessage(t, Power(tp, 3).FullString(), "{D: [𝛆 0] || R: [𝛆 0]}")
+	})
+
 	// InF false tests we can recognise the element is not in R. Thompson's group F
 	t.Run("InF false", func(t *testing.T) {
 		//reduces element to minimal tree pair.
@@ -351,6 +406,463 @@ func Test(t *testing.T) {
 		assertCorrectMessage(t, got, want)
 	})
 
+	// IsIdentity sees through unreduced pairs, but not through a nontrivial permutation.
+	t.Run("IsIdentity test", func(t *testing.T) {
+		tp, err := NewTreePairAlpha("01")
+		if nil != err {
+			assertCorrectMessage(t, "Failed to NewTreePairAlpha('01')", " in IsIdentity test.")
+		}
+		assertCorrectMessage(t, strconv.FormatBool(tp.IsIdentity()), "true")
+
+		EncodeDFS(tp, "{1110000,1110000,0 1 2 3}")
+		assertCorrectMessage(t, strconv.FormatBool(tp.IsIdentity()), "true")
+		assertCorrectMessage(t, tp.FullString(), "{D: [000 0], [001 1], [01 2], [1 3] || R: [000 0], [001 1], [01 2], [1 3]}")
+
+		EncodeDFS(tp, "{1110000,1110000,1 0 2 3}")
+		assertCorrectMessage(t, strconv.FormatBool(tp.IsIdentity()), "false")
+
+		c, _ := GeneratorC()
+		assertCorrectMessage(t, strconv.FormatBool(Power(c, 3).IsIdentity()), "true")
+	})
+
+	// CanonicalString and Hash see through unreduced pairs and their labels.
+	t.Run("CanonicalString and Hash test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		tp, _ := NewTreePairAlpha("01")
+		EncodeDFS(tp, "{1100100,1110000,0 1 2 3}")
+		assertCorrectMessage(t, tp.CanonicalString(), x0.CanonicalString())
+		assertCorrectMessage(t, x0.CanonicalString(), "{D: [0 0], [10 1], [11 2] || R: [00 0], [01 1], [1 2]}")
+		assertCorrectMessage(t, strconv.FormatUint(tp.Hash(), 10), strconv.FormatUint(x0.Hash(), 10))
+		assertCorrectMessage(t, strconv.FormatUint(x0.Hash(), 16), "caf96f3cd4a8d2b4")
+
+		x1, _ := GeneratorX(1)
+		assertCorrectMessage(t, strconv.FormatBool(x0.Hash() == x1.Hash()), "false")
+		assertCorrectMessage(t, x0.FullString(), "{D: [0 0], [10 1], [11 2] || R: [00 0], [01 1], [1 2]}")
+	})
+
+	// Leaves agrees with the domain side of FullString, and stops when asked to.
+	t.Run("Leaves test", func(t *testing.T) {
+		tp, _ := NewTreePairAlpha("01")
+		EncodeDFS(tp, "{11000,10100,1 2 0}")
+		tp.Invert()
+		var leaves []string
+		assert.True(t, tp.Leaves(func(leaf string, label int) bool {
+			leaves = append(leaves, "["+leaf+" "+strconv.Itoa(label)+"]")
+			return true
+		}))
+		assertCorrectMessage(t, "{D: "+strings.Join(leaves, ", ")+" ||", strings.SplitAfter(tp.FullString(), "||")[0])
+
+		visited := 0
+		assert.False(t, tp.Leaves(func(leaf string, label int) bool {
+			visited++
+			return false
+		}))
+		assert.Equal(t, 1, visited)
+
+		identity, _ := NewTreePairAlpha("01")
+		identity.Leaves(func(leaf string, label int) bool {
+			assertCorrectMessage(t, leaf+" "+strconv.Itoa(label), prefcode.EmptyString+" 0")
+			return true
+		})
+	})
+
+	// x0 carries 0 to 00, 10 to 01 and 11 to 1, and Pairs stops when asked to.
+	t.Run("Pairs test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		var pairs []string
+		assert.True(t, x0.Pairs(func(domainLeaf, rangeLeaf string) bool {
+			pairs = append(pairs, domainLeaf+"->"+rangeLeaf)
+			return true
+		}))
+		assertCorrectMessage(t, strings.Join(pairs, " "), "0->00 10->01 11->1")
+
+		c, _ := GeneratorC()
+		pairs = nil
+		assert.False(t, c.Pairs(func(domainLeaf, rangeLeaf string) bool {
+			pairs = append(pairs, domainLeaf+"->"+rangeLeaf)
+			return len(pairs) < 2
+		}))
+		assertCorrectMessage(t, strings.Join(pairs, " "), "0->11 10->0")
+	})
+
+	// x0 with an extra caret is x0, though Equals tells them apart, and neither is reduced.
+	t.Run("EqualsAsElements test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		tp, _ := NewTreePairAlpha("01")
+		EncodeDFS(tp, "{1100100,1110000,0 1 2 3}")
+		var x0TP TreePair = x0
+		assert.False(t, tp.Equals(&x0TP))
+		assert.True(t, EqualsAsElements(tp, x0))
+		assertCorrectMessage(t, tp.DFSString(), "{1100100,1110000,0 1 2 3}")
+
+		x1, _ := GeneratorX(1)
+		assert.False(t, EqualsAsElements(x0, x1))
+		ternary, _ := NewTreePairAlpha("012")
+		identity, _ := NewTreePairAlpha("01")
+		assert.False(t, EqualsAsElements(identity, ternary))
+		assert.True(t, EqualsAsElements(identity, Power(x0, 0)))
+	})
+
+	// EqualsAsMaps agrees with EqualsAsElements on the elements with up to 3 leaves, written
+	// minimally or with an extra caret.
+	t.Run("EqualsAsMaps test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		tp, _ := NewTreePairAlpha("01")
+		EncodeDFS(tp, "{1100100,1110000,0 1 2 3}")
+		assert.True(t, EqualsAsMaps(tp, x0))
+		assert.True(t, EqualsAsMaps(x0, tp))
+		assertCorrectMessage(t, tp.DFSString(), "{1100100,1110000,0 1 2 3}")
+
+		e, _ := NewEnumerator("01", 3, GroupV)
+		var elements []TreePair
+		e.Each(func(tp TreePair) bool {
+			expanded := clone(tp)
+			expanded.ExpandDomainAt(sortedLeaves(expanded.CodeDomain())[0])
+			assert.Equal(t, tp.Size()+1, expanded.Size())
+			elements = append(elements, tp, expanded)
+			return true
+		})
+		for _, a := range elements {
+			for _, b := range elements {
+				assert.Equal(t, EqualsAsElements(a, b), EqualsAsMaps(a, b), a.DFSString()+" "+b.DFSString())
+			}
+		}
+		ternary, _ := NewTreePairAlpha("012")
+		identity, _ := NewTreePairAlpha("01")
+		assert.False(t, EqualsAsMaps(identity, ternary))
+	})
+
+	// Compare sees through representations, and is a total order consistent with
+	// EqualsAsElements on the elements with up to 3 leaves.
+	t.Run("Compare test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		x1, _ := GeneratorX(1)
+		big, _ := NewTreePairAlpha("01")
+		EncodeDFS(big, "{1100100,1110000,0 1 2 3}")
+		assert.Equal(t, 0, Compare(big, x0))
+		assert.Equal(t, -1, Compare(big, x1))
+		assert.Equal(t, 1, Compare(x1, big))
+		assertCorrectMessage(t, big.DFSString(), "{1100100,1110000,0 1 2 3}")
+		ternary, _ := NewTreePairAlpha("012")
+		identity, _ := NewTreePairAlpha("01")
+		assert.Equal(t, -1, Compare(identity, ternary))
+
+		e, _ := NewEnumerator("01", 3, GroupV)
+		var elements []TreePair
+		e.Each(func(tp TreePair) bool {
+			expanded := clone(tp)
+			expanded.ExpandDomainAt(sortedLeaves(expanded.CodeDomain())[0])
+			elements = append(elements, tp, expanded)
+			return true
+		})
+		sort.Slice(elements, func(i, j int) bool { return Compare(elements[i], elements[j]) < 0 })
+		for i, a := range elements {
+			for j, b := range elements {
+				assert.Equal(t, EqualsAsElements(a, b), 0 == Compare(a, b))
+				assert.Equal(t, Compare(a, b), -Compare(b, a))
+				if i < j {
+					assert.True(t, Compare(a, b) <= 0)
+				}
+			}
+		}
+	})
+
+	// an auto-minimising x0 stays minimal, and still multiplies correctly.
+	t.Run("SetAutoMinimise test", func(t *testing.T) {
+		tp, _ := NewTreePairAlpha("01")
+		EncodeDFS(tp, "{1100100,1110000,0 1 2 3}")
+		assert.False(t, tp.AutoMinimise())
+		tp.SetAutoMinimise(true)
+		assert.True(t, tp.AutoMinimise())
+		assertCorrectMessage(t, tp.DFSString(), "{10100,11000,0 1 2}")
+		tp.ExpandDomainAt("0")
+		tp.ExpandRangeAt("11")
+		assertCorrectMessage(t, tp.DFSString(), "{10100,11000,0 1 2}")
+		assert.True(t, EncodeDFS(tp, "{1100100,1110000,0 1 2 3}"))
+		assertCorrectMessage(t, tp.DFSString(), "{10100,11000,0 1 2}")
+
+		x0, _ := GeneratorX(0)
+		x1, _ := GeneratorX(1)
+		product := Multiply(tp, Power(x0, -1))
+		assert.True(t, product.IsIdentity())
+		assert.True(t, product.AutoMinimise())
+		assert.False(t, Multiply(x1, tp).AutoMinimise())
+		assert.True(t, EqualsAsElements(Multiply(tp, x1), Multiply(Power(x0, 1), x1)))
+
+		tp.SetAutoMinimise(false)
+		tp.ExpandDomainAt("0")
+		assertCorrectMessage(t, tp.DFSString(), "{1100100,1110000,0 1 2 3}")
+	})
+
+	// expanding every leaf eight times gives pairs with hundreds of leaves, which reduce back.
+	t.Run("Minimise large test", func(t *testing.T) {
+		for _, dfs := range []string{"{10100,11000,0 1 2}", "{10100,10100,1 2 0}", "{1101000,1011000,3 1 0 2}"} {
+			tp, _ := NewTreePairAlpha("01")
+			EncodeDFS(tp, dfs)
+			for k := 0; k < 8; k++ {
+				for _, leaf := range sortedLeaves(tp.CodeDomain()) {
+					tp.ExpandDomainAt(leaf)
+				}
+			}
+			assert.True(t, tp.Size() > 500)
+			tp.Minimise()
+			assertCorrectMessage(t, tp.DFSString(), dfs)
+		}
+	})
+
+	// x_n, expanded once at every leaf, has a domain tree of depth n+3.
+	t.Run("Minimise deep test", func(t *testing.T) {
+		n := 2000
+		spine := strings.Repeat("1", n)
+		pairs := leafMap{spine + "0": spine + "00", spine + "10": spine + "01", spine + "11": spine + "1"}
+		for k := 0; k < n; k++ {
+			pairs[spine[:k]+"0"] = spine[:k] + "0"
+		}
+		expanded := make(leafMap, 2*len(pairs))
+		for d, r := range pairs {
+			expanded[d+"0"], expanded[d+"1"] = r+"0", r+"1"
+		}
+		tp := identityOver([]rune("01"))
+		tp.setLeafMap(expanded)
+		tp.Minimise()
+		assert.Equal(t, n+3, tp.Size())
+		assert.Equal(t, pairs, newLeafMap(tp))
+	})
+
+	// clones share the trees of the codes until one of them changes them.
+	t.Run("Copy-on-write test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		before := c.FullString()
+		snapshot := clone(c)
+		assert.Same(t, c.trees, snapshot.trees)
+		assert.Nil(t, snapshot.dom, "the codes are read off the trees only when wanted.")
+
+		c.ExpandDomainAt("0")
+		assert.Nil(t, c.trees)
+		assert.Equal(t, before, snapshot.FullString())
+		assert.NotSame(t, c.CodeDomain(), snapshot.CodeDomain())
+
+		other := clone(snapshot)
+		other.Invert()
+		other.ApplyPermRange(map[int]int{0: 1, 1: 0, 2: 2})
+		assert.Equal(t, before, snapshot.FullString())
+		snapshot.MutableCodeDomain().ExpandAt("11")
+		assert.Equal(t, 4, snapshot.Size())
+		assert.Equal(t, 3, other.Size())
+	})
+
+	// goroutines may read the codes of a tree pair together, reading them off the trees it
+	// shares with a clone, which go test -race checks.
+	t.Run("Concurrent code reads test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		snapshot := clone(c)
+		want := c.CodeDomain().String() + c.CodeRange().String()
+		var wg sync.WaitGroup
+		got := make([]string, 4)
+		for k := range got {
+			wg.Add(1)
+			go func(k int) {
+				defer wg.Done()
+				got[k] = snapshot.CodeDomain().String() + snapshot.CodeRange().String()
+			}(k)
+		}
+		wg.Wait()
+		for _, v := range got {
+			assert.Equal(t, want, v)
+		}
+		assert.Same(t, c.trees, snapshot.trees)
+	})
+
+	// the cached permutations follow every change to the codes: InF and InT agree with a
+	// tree pair sharing the codes but reading the permutations afresh each time.
+	t.Run("Permutation cache test", func(t *testing.T) {
+		tp, _ := NewTreePairAlpha("01")
+		EncodeDFS(tp, "{10100,10100,1 2 0}")
+		check := func(step string) {
+			t.Helper()
+			uncached := &treePair{alphabet: tp.alphabet, codePair: tp.codePair}
+			assert.Equal(t, uncached.InF(), tp.InF(), step)
+			assert.Equal(t, uncached.InT(), tp.InT(), step)
+		}
+		check("EncodeDFS")
+		assert.True(t, tp.InT() && !tp.InF())
+		tp.ApplyPermRange(map[int]int{0: 0, 1: 2, 2: 1})
+		check("ApplyPermRange")
+		tp.ApplyPermDomain(map[int]int{0: 2, 1: 1, 2: 0})
+		check("ApplyPermDomain")
+		tp.ResetLabels()
+		check("ResetLabels")
+		assert.Equal(t, map[int]int{0: 0, 1: 1, 2: 2}, tp.dom.Permutation())
+		tp.Invert()
+		check("Invert")
+		tp.ExpandDomainAt("0")
+		check("ExpandDomainAt")
+		tp.ReduceDomainAt("0")
+		check("ReduceDomainAt")
+		tp.Minimise()
+		check("Minimise")
+
+		// a code asked for to be changed may be changed directly.
+		EncodeDFS(tp, "{10100,10100,1 2 0}")
+		assert.True(t, tp.InT())
+		code := tp.MutableCodeRange().Code()
+		code["10"], code["11"] = code["11"], code["10"]
+		check("MutableCodeRange")
+		assert.False(t, tp.InT())
+
+		c := clone(tp)
+		code = c.MutableCodeRange().Code()
+		code["0"], code["10"] = code["10"], code["0"]
+		check("clone")
+		assert.False(t, tp.InT())
+		assert.True(t, c.InF())
+	})
+
+	// x0 x1 expands to 5 leaves, which need no minimising.
+	t.Run("MultiplyWithBudget test", func(t *testing.T) {
+		x0, _ := NewTreePairAlpha("01")
+		EncodeDFS(x0, "{1100100,1110000,0 1 2 3}")
+		x1, _ := GeneratorX(1)
+
+		_, err := MultiplyWithBudget(x0, x1, 4)
+		assert.Equal(t, ErrBudgetExceeded, err)
+		assertCorrectMessage(t, x0.DFSString(), "{1100100,1110000,0 1 2 3}")
+		_, err = MultiplyWithBudget(x0, x1, 0)
+		assert.NotNil(t, err)
+
+		x0.Minimise()
+		_, err = MultiplyWithBudget(x0, x1, 4)
+		assert.Equal(t, ErrBudgetExceeded, err)
+		product, err := MultiplyWithBudget(x0, x1, 5)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, product.DFSString(), Multiply(x0, x1).DFSString())
+
+		big := Power(x1, 40)
+		_, err = MultiplyWithBudget(big, x0, 40)
+		assert.Equal(t, ErrBudgetExceeded, err)
+	})
+
+	t.Run("Multiply alphabets test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		ternary, _ := NewTreePairAlpha("012")
+		ternary.ExpandDomainAt("")
+		ternary.ApplyPermRange(map[int]int{0: 1, 1: 0})
+
+		_, err := MultiplyWithBudget(x0, ternary, 100)
+		assert.EqualError(t, err, "Multiply(): elements are over different alphabets 01 and 012")
+		_, err = MultiplyAll(ternary, x0)
+		assert.NotNil(t, err)
+		assert.PanicsWithError(t, "Multiply(): elements are over different alphabets 01 and 012", func() { Multiply(x0, ternary) })
+	})
+
+	t.Run("ExpandDomainToCode test", func(t *testing.T) {
+		x0, _ := GeneratorX(0)
+		x1, _ := GeneratorX(1)
+		tp := clone(x0)
+		assert.Nil(t, tp.ExpandDomainToCode(x1.CodeDomain()))
+		assert.Equal(t, []string{"0", "10", "110", "111"}, sortedLeaves(tp.CodeDomain()))
+		assert.Equal(t, []string{"00", "01", "10", "11"}, sortedLeaves(tp.CodeRange()))
+		assert.True(t, EqualsAsElements(x0, tp))
+
+		// carets above the leaves are left alone, and the trivial tree expands at the root.
+		assert.Nil(t, tp.ExpandDomainToCode(x0.CodeDomain()))
+		assert.Equal(t, 4, tp.Size())
+		id := identityOver([]rune("01"))
+		assert.Nil(t, id.ExpandDomainToCode(x1.CodeRange()))
+		assert.Equal(t, []string{"0", "100", "101", "11"}, sortedLeaves(id.CodeRange()))
+
+		ternary, _ := NewTreePairAlpha("012")
+		assert.NotNil(t, tp.ExpandDomainToCode(ternary.CodeDomain()))
+	})
+
+	t.Run("ExpandToDepth test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		tp := clone(c)
+		assert.Nil(t, tp.ExpandToDepth(3))
+		assert.Equal(t, 8, tp.Size())
+		tp.Leaves(func(leaf string, label int) bool {
+			assert.Equal(t, 3, len(leaf))
+			return true
+		})
+		assert.True(t, EqualsAsElements(c, tp))
+		assert.NotNil(t, tp.ExpandToDepth(2), "leaves are already at depth 3.")
+		assert.NotNil(t, tp.ExpandToDepth(-1))
+
+		id := identityOver([]rune("012"))
+		assert.Nil(t, id.ExpandToDepth(2))
+		assert.Equal(t, 9, id.Size())
+		assert.True(t, id.IsIdentity())
+		id = identityOver([]rune("01"))
+		assert.Nil(t, id.ExpandToDepth(0))
+		assert.Equal(t, 1, id.Size())
+	})
+
+	t.Run("MinimiseTrace test", func(t *testing.T) {
+		a, _ := GeneratorA()
+		a.ExpandDomainAt("11")
+		a.ExpandDomainAt("111")
+		dom, ran := a.MinimiseTrace()
+		assert.Equal(t, []string{"111", "11"}, dom)
+		assert.Equal(t, []string{"11", "1"}, ran)
+		want, _ := GeneratorA()
+		assert.Equal(t, want.FullString(), a.FullString())
+
+		dom, ran = a.MinimiseTrace()
+		assert.Empty(t, dom)
+		assert.Empty(t, ran)
+
+		id := identityOver([]rune("01"))
+		id.ExpandDomainAt("")
+		dom, ran = id.MinimiseTrace()
+		assert.Equal(t, []string{""}, dom)
+		assert.Equal(t, []string{""}, ran)
+		assert.True(t, id.IsIdentity())
+	})
+
+	t.Run("IsMinimal test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		assert.True(t, c.IsMinimal())
+		c.ExpandRangeAt("0")
+		before := c.FullString()
+		assert.False(t, c.IsMinimal())
+		assert.Equal(t, before, c.FullString(), "the element is left alone.")
+		c.Minimise()
+		assert.True(t, c.IsMinimal())
+
+		for _, tp := range []TreePair{identityOver([]rune("01")), identityOver([]rune("012"))} {
+			assert.True(t, tp.IsMinimal())
+			tp.ExpandDomainAt("1")
+			assert.False(t, tp.IsMinimal())
+		}
+	})
+
+	// products of the elements of V with up to 3 leaves are associative and have inverses,
+	// and long products stay quick.
+	t.Run("Multiply group laws test", func(t *testing.T) {
+		e, _ := NewEnumerator("01", 3, GroupV)
+		var elements []TreePair
+		e.Each(func(tp TreePair) bool {
+			elements = append(elements, tp)
+			return true
+		})
+		for _, a := range elements {
+			assert.True(t, Multiply(Power(a, 1), Power(a, -1)).IsIdentity(), a.DFSString())
+			for _, b := range elements {
+				ab := Multiply(Power(a, 1), Power(b, 1))
+				for _, c := range elements[:6] {
+					left := Multiply(Power(ab, 1), Power(c, 1))
+					right := Multiply(Power(a, 1), Multiply(Power(b, 1), Power(c, 1)))
+					assert.True(t, EqualsAsMaps(left, right))
+				}
+			}
+		}
+
+		x0, _ := GeneratorX(0)
+		x1, _ := GeneratorX(1)
+		long := Power(Multiply(Power(x0, 1), Power(x1, -1)), 300)
+		assert.Equal(t, 0, Compare(Power(long, -1), Power(Multiply(Power(x1, 1), Power(x0, -1)), 300)))
+	})
+
 	t.Run("LessEqual test", func(t *testing.T) {
 		dTP, err := NewTreePairAlpha("01")
 		if nil != err {
@@ -372,3 +884,64 @@ func Test(t *testing.T) {
 		assert.False(t, LessEqual(*rTP, *dTP), "rTP was not greater than dTP")
 	})
 }
+
+// a product-heavy workload: a word in x0, x1, C and pi0 multiplied out one letter at a time.
+func BenchmarkMultiply(b *testing.B) {
+	x0, _ := GeneratorX(0)
+	x1, _ := GeneratorX(1)
+	c, _ := GeneratorC()
+	pi0, _ := GeneratorPi0()
+	word := []TreePair{x0, c, x1, pi0, Power(x1, -1), c, x0, Power(pi0, 1)}
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		product := identityOver([]rune("01"))
+		for k := 0; k < 64; k++ {
+			product = Multiply(product, word[k%len(word)])
+		}
+	}
+}
+
+func BenchmarkPower(b *testing.B) {
+	x0, _ := GeneratorX(0)
+	x1, _ := GeneratorX(1)
+	g := Multiply(x0, Power(x1, -1))
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		Power(g, 500)
+	}
+}
+
+func BenchmarkMinimise(b *testing.B) {
+	tp, _ := NewTreePairAlpha("01")
+	EncodeDFS(tp, "{1101000,1011000,3 1 0 2}")
+	for k := 0; k < 8; k++ {
+		for _, leaf := range sortedLeaves(tp.CodeDomain()) {
+			tp.ExpandDomainAt(leaf)
+		}
+	}
+	expanded := newLeafMap(tp)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tp.setLeafMap(expanded)
+		tp.Minimise()
+	}
+}
+
+func BenchmarkClassify(b *testing.B) {
+	tp, _ := NewTreePairAlpha("01")
+	EncodeDFS(tp, "{1101000,1011000,3 1 0 2}")
+	for k := 0; k < 6; k++ {
+		for _, leaf := range sortedLeaves(tp.CodeDomain()) {
+			tp.ExpandDomainAt(leaf)
+		}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tp.ResetLabels()
+		tp.InF()
+		tp.InT()
+		tp.ResetLabels()
+	}
+}
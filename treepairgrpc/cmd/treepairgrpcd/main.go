@@ -0,0 +1,35 @@
+// Command treepairgrpcd serves the TreePairService of treepairgrpc over gRPC.
+//
+// Usage:
+//
+//	treepairgrpcd [-addr :9090] [-max-leaves 1024] [-timeout 10s] [-search-leaves 5]
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"time"
+
+	"github.com/loeksnokes/treepair/treepairgrpc"
+	"github.com/loeksnokes/treepair/treepairgrpc/treepairpb"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "address to listen on")
+	maxLeaves := flag.Int("max-leaves", 1024, "the most leaves an element or product may have")
+	timeout := flag.Duration("timeout", 10*time.Second, "the longest a call may run")
+	searchLeaves := flag.Int("search-leaves", 5, "the most leaves of the conjugators tried in T")
+	flag.Parse()
+	listener, err := net.Listen("tcp", *addr)
+	if nil != err {
+		log.Fatal(err)
+	}
+	server := grpc.NewServer()
+	service := treepairgrpc.NewServer()
+	service.MaxLeaves, service.Timeout = *maxLeaves, *timeout
+	service.ConjugatorSearchLeaves = *searchLeaves
+	treepairpb.RegisterTreePairServiceServer(server, service)
+	log.Fatal(server.Serve(listener))
+}
@@ -0,0 +1,187 @@
+// Package treepairgrpc serves the arithmetic of R. Thompson's groups F, T and V over gRPC, as
+// the TreePairService of treepairpb/treepair.proto.  It is a module of its own so that the
+// treepair package does not depend on gRPC.
+//
+// The code in treepairpb is generated from treepair.proto with protoc-gen-go and
+// protoc-gen-go-grpc, using paths=source_relative.
+package treepairgrpc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/loeksnokes/treepair"
+	"github.com/loeksnokes/treepair/treepairgrpc/treepairpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements treepairpb.TreePairServiceServer.  Malformed elements, and elements or
+// products with more than MaxLeaves leaves, are answered with codes.InvalidArgument, and calls
+// still running after Timeout with codes.DeadlineExceeded.
+type Server struct {
+	treepairpb.UnimplementedTreePairServiceServer
+	// MaxLeaves is the most leaves an element or product may have.
+	MaxLeaves int
+	// Timeout is the longest a call may run, within any deadline the client sets.
+	Timeout time.Duration
+	// ConjugatorSearchLeaves is the most leaves of the conjugators tried in T when the
+	// invariants do not decide conjugacy.
+	ConjugatorSearchLeaves int
+}
+
+// NewServer returns a Server refusing elements with more than 1024 leaves, abandoning calls
+// after 10 seconds and searching for conjugators in T with up to 5 leaves.
+func NewServer() *Server {
+	return &Server{MaxLeaves: 1024, Timeout: 10 * time.Second, ConjugatorSearchLeaves: 5}
+}
+
+// decode reads an element, failing with codes.InvalidArgument.
+func (s *Server) decode(e *treepairpb.Element) (treepair.TreePair, error) {
+	if nil == e {
+		return nil, status.Error(codes.InvalidArgument, "no element given")
+	}
+	tp, err := treepair.ElementJSON{Alphabet: e.GetAlphabet(), DFS: e.GetDfs()}.TreePair()
+	if nil != err {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if tp.Size() > s.MaxLeaves {
+		return nil, status.Error(codes.InvalidArgument, "element has "+strconv.Itoa(tp.Size())+
+			" leaves, more than the limit of "+strconv.Itoa(s.MaxLeaves))
+	}
+	return tp, nil
+}
+
+// encode writes an element as a response.
+func encode(tp treepair.TreePair) *treepairpb.ElementResponse {
+	e := treepair.EncodeJSON(tp)
+	return &treepairpb.ElementResponse{Result: &treepairpb.Element{Alphabet: e.Alphabet, Dfs: e.DFS}}
+}
+
+// Multiply returns the product of the elements, applying the first one first.
+func (s *Server) Multiply(ctx context.Context, req *treepairpb.MultiplyRequest) (*treepairpb.ElementResponse, error) {
+	if 0 == len(req.GetElements()) {
+		return nil, status.Error(codes.InvalidArgument, "no elements given")
+	}
+	factors := make([]treepair.TreePair, len(req.GetElements()))
+	for k, v := range req.GetElements() {
+		tp, err := s.decode(v)
+		if nil != err {
+			return nil, err
+		}
+		if k > 0 && string(factors[0].Alphabet()) != string(tp.Alphabet()) {
+			return nil, status.Error(codes.InvalidArgument, "elements are over different alphabets")
+		}
+		factors[k] = tp
+	}
+	product, err := treepair.MultiplyAllWithBudget(s.MaxLeaves, factors...)
+	if nil != err {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return encode(product), nil
+}
+
+// Invert returns the minimised inverse of the element.
+func (s *Server) Invert(ctx context.Context, req *treepairpb.ElementRequest) (*treepairpb.ElementResponse, error) {
+	tp, err := s.decode(req.GetElement())
+	if nil != err {
+		return nil, err
+	}
+	tp.Invert()
+	tp.Minimise()
+	return encode(tp), nil
+}
+
+// Minimise returns the minimal tree pair of the element.
+func (s *Server) Minimise(ctx context.Context, req *treepairpb.ElementRequest) (*treepairpb.ElementResponse, error) {
+	tp, err := s.decode(req.GetElement())
+	if nil != err {
+		return nil, err
+	}
+	tp.Minimise()
+	return encode(tp), nil
+}
+
+// AreConjugate decides whether the elements are conjugate in T or in V.  In T it reports an
+// undecided answer when no conjugator is found with up to ConjugatorSearchLeaves leaves; in V
+// the answer is always decided.  Neither can be interrupted, so each is left to finish on its
+// own when the call runs out of time.
+func (s *Server) AreConjugate(ctx context.Context, req *treepairpb.ConjugacyRequest) (*treepairpb.ConjugacyResponse, error) {
+	a, err := s.decode(req.GetA())
+	if nil != err {
+		return nil, err
+	}
+	b, err := s.decode(req.GetB())
+	if nil != err {
+		return nil, err
+	}
+	var areConjugate func(a, b treepair.TreePair) (bool, error)
+	switch req.GetGroup() {
+	case treepairpb.Group_GROUP_T:
+		areConjugate = func(a, b treepair.TreePair) (bool, error) {
+			return treepair.SearchConjugacyInT(a, b, s.ConjugatorSearchLeaves)
+		}
+	case treepairpb.Group_GROUP_V:
+		areConjugate = treepair.AreConjugateInV
+	default:
+		return nil, status.Error(codes.InvalidArgument, "no group given")
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.Timeout)
+	defer cancel()
+	if err := ctx.Err(); nil != err {
+		return nil, status.FromContextError(err).Err()
+	}
+	type answer struct {
+		conjugate bool
+		err       error
+	}
+	answers := make(chan answer, 1)
+	go func() {
+		conjugate, err := areConjugate(a, b)
+		answers <- answer{conjugate, err}
+	}()
+	var conjugate bool
+	select {
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	case v := <-answers:
+		conjugate, err = v.conjugate, v.err
+	}
+	if errors.Is(err, treepair.ErrConjugacyUndecided) {
+		return &treepairpb.ConjugacyResponse{}, nil
+	}
+	if nil != err {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &treepairpb.ConjugacyResponse{Conjugate: conjugate, Decided: true}, nil
+}
+
+// Classify returns the classification of the element.
+func (s *Server) Classify(ctx context.Context, req *treepairpb.ElementRequest) (*treepairpb.Classification, error) {
+	tp, err := s.decode(req.GetElement())
+	if nil != err {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.Timeout)
+	defer cancel()
+	c, err := treepair.ClassifyContext(ctx, tp)
+	if nil != err {
+		return nil, status.FromContextError(err).Err()
+	}
+	answer := &treepairpb.Classification{
+		InF:         c.InF,
+		InT:         c.InT,
+		InV:         c.InV,
+		Size:        int32(c.Size),
+		WordLength:  int32(c.WordLength),
+		Order:       int32(c.Order),
+		SlopeAtZero: int32(c.SlopeAtZero),
+		SlopeAtOne:  int32(c.SlopeAtOne),
+	}
+	if nil != c.RotationNumber {
+		answer.RotationNumber = c.RotationNumber.RatString()
+	}
+	return answer, nil
+}
@@ -0,0 +1,127 @@
+package treepairgrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/loeksnokes/treepair/treepairgrpc/treepairpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServer(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	treepairpb.RegisterTreePairServiceServer(server, NewServer())
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err)
+	defer conn.Close()
+	client := treepairpb.NewTreePairServiceClient(conn)
+	ctx := context.Background()
+
+	element := func(dfs string) *treepairpb.Element {
+		return &treepairpb.Element{Alphabet: "01", Dfs: dfs}
+	}
+	// C = {10100,10100,1 2 0} has order 3 and C^2 has rotation number 1/3.
+	c, c2 := element("{10100,10100,1 2 0}"), element("{10100,10100,2 0 1}")
+
+	t.Run("arithmetic test", func(t *testing.T) {
+		product, err := client.Multiply(ctx, &treepairpb.MultiplyRequest{Elements: []*treepairpb.Element{c, c}})
+		assert.Nil(t, err)
+		assertCorrectMessage(t, product.GetResult().GetDfs(), c2.Dfs)
+
+		inverse, err := client.Invert(ctx, &treepairpb.ElementRequest{Element: c})
+		assert.Nil(t, err)
+		assertCorrectMessage(t, inverse.GetResult().GetDfs(), c2.Dfs)
+
+		min, err := client.Minimise(ctx, &treepairpb.ElementRequest{Element: element("{1100100,1110000,0 1 2 3}")})
+		assert.Nil(t, err)
+		assertCorrectMessage(t, min.GetResult().GetDfs(), "{10100,11000,0 1 2}")
+		assertCorrectMessage(t, min.GetResult().GetAlphabet(), "01")
+	})
+
+	t.Run("AreConjugate test", func(t *testing.T) {
+		for group, want := range map[treepairpb.Group]bool{treepairpb.Group_GROUP_T: false, treepairpb.Group_GROUP_V: true} {
+			answer, err := client.AreConjugate(ctx, &treepairpb.ConjugacyRequest{A: c, B: c2, Group: group})
+			assert.Nil(t, err)
+			assert.True(t, answer.GetDecided())
+			assert.Equal(t, want, answer.GetConjugate(), group.String())
+		}
+	})
+
+	t.Run("Classify test", func(t *testing.T) {
+		answer, err := client.Classify(ctx, &treepairpb.ElementRequest{Element: c})
+		assert.Nil(t, err)
+		assert.False(t, answer.GetInF())
+		assert.True(t, answer.GetInT())
+		assert.Equal(t, int32(3), answer.GetOrder())
+		assert.Equal(t, int32(-1), answer.GetWordLength())
+		assertCorrectMessage(t, answer.GetRotationNumber(), "2/3")
+
+		answer, err = client.Classify(ctx, &treepairpb.ElementRequest{Element: element("{10100,11000,0 1 2}")})
+		assert.Nil(t, err)
+		assert.True(t, answer.GetInF())
+		assert.Equal(t, int32(1), answer.GetWordLength())
+		assertCorrectMessage(t, answer.GetRotationNumber(), "0")
+	})
+
+	t.Run("errors test", func(t *testing.T) {
+		_, err := client.Multiply(ctx, &treepairpb.MultiplyRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assertCorrectMessage(t, status.Convert(err).Message(), "no elements given")
+
+		_, err = client.Minimise(ctx, &treepairpb.ElementRequest{})
+		assertCorrectMessage(t, status.Convert(err).Message(), "no element given")
+
+		_, err = client.Classify(ctx, &treepairpb.ElementRequest{Element: &treepairpb.Element{Dfs: "{0,0,0}"}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assertCorrectMessage(t, status.Convert(err).Message(), "ElementJSON.TreePair(): no alphabet")
+
+		_, err = client.AreConjugate(ctx, &treepairpb.ConjugacyRequest{A: c, B: c})
+		assertCorrectMessage(t, status.Convert(err).Message(), "no group given")
+	})
+
+	// the n-th power of x0 = {11000,10100,0 1 2} has n+2 leaves.
+	t.Run("limits test", func(t *testing.T) {
+		x0 := element("{11000,10100,0 1 2}")
+		small := &Server{MaxLeaves: 6, Timeout: 10 * time.Second}
+		_, err := small.Minimise(ctx, &treepairpb.ElementRequest{Element: element("{1110000,1010100,0 1 2 3}")})
+		assert.Nil(t, err)
+		_, err = small.Classify(ctx, &treepairpb.ElementRequest{Element: element("{1111110000000,1010101010100,0 1 2 3 4 5 6}")})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assertCorrectMessage(t, status.Convert(err).Message(), "element has 7 leaves, more than the limit of 6")
+
+		powers := make([]*treepairpb.Element, 8)
+		for k := range powers {
+			powers[k] = x0
+		}
+		_, err = small.Multiply(ctx, &treepairpb.MultiplyRequest{Elements: powers})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assertCorrectMessage(t, status.Convert(err).Message(), "product exceeds the leaf budget")
+
+		hurried := &Server{MaxLeaves: 16, Timeout: -time.Second}
+		_, err = hurried.Classify(ctx, &treepairpb.ElementRequest{Element: x0})
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+		_, err = hurried.AreConjugate(ctx, &treepairpb.ConjugacyRequest{A: c, B: c2, Group: treepairpb.Group_GROUP_V})
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	})
+}
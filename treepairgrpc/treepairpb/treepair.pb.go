@@ -0,0 +1,592 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: treepair.proto
+
+package treepairpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Group int32
+
+const (
+	Group_GROUP_UNSPECIFIED Group = 0
+	Group_GROUP_T           Group = 1
+	Group_GROUP_V           Group = 2
+)
+
+// Enum value maps for Group.
+var (
+	Group_name = map[int32]string{
+		0: "GROUP_UNSPECIFIED",
+		1: "GROUP_T",
+		2: "GROUP_V",
+	}
+	Group_value = map[string]int32{
+		"GROUP_UNSPECIFIED": 0,
+		"GROUP_T":           1,
+		"GROUP_V":           2,
+	}
+)
+
+func (x Group) Enum() *Group {
+	p := new(Group)
+	*p = x
+	return p
+}
+
+func (x Group) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Group) Descriptor() protoreflect.EnumDescriptor {
+	return file_treepair_proto_enumTypes[0].Descriptor()
+}
+
+func (Group) Type() protoreflect.EnumType {
+	return &file_treepair_proto_enumTypes[0]
+}
+
+func (x Group) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Group.Descriptor instead.
+func (Group) EnumDescriptor() ([]byte, []int) {
+	return file_treepair_proto_rawDescGZIP(), []int{0}
+}
+
+type Element struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alphabet      string                 `protobuf:"bytes,1,opt,name=alphabet,proto3" json:"alphabet,omitempty"`
+	Dfs           string                 `protobuf:"bytes,2,opt,name=dfs,proto3" json:"dfs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Element) Reset() {
+	*x = Element{}
+	mi := &file_treepair_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Element) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Element) ProtoMessage() {}
+
+func (x *Element) ProtoReflect() protoreflect.Message {
+	mi := &file_treepair_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Element.ProtoReflect.Descriptor instead.
+func (*Element) Descriptor() ([]byte, []int) {
+	return file_treepair_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Element) GetAlphabet() string {
+	if x != nil {
+		return x.Alphabet
+	}
+	return ""
+}
+
+func (x *Element) GetDfs() string {
+	if x != nil {
+		return x.Dfs
+	}
+	return ""
+}
+
+type ElementRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Element       *Element               `protobuf:"bytes,1,opt,name=element,proto3" json:"element,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ElementRequest) Reset() {
+	*x = ElementRequest{}
+	mi := &file_treepair_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ElementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ElementRequest) ProtoMessage() {}
+
+func (x *ElementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_treepair_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ElementRequest.ProtoReflect.Descriptor instead.
+func (*ElementRequest) Descriptor() ([]byte, []int) {
+	return file_treepair_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ElementRequest) GetElement() *Element {
+	if x != nil {
+		return x.Element
+	}
+	return nil
+}
+
+type ElementResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *Element               `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ElementResponse) Reset() {
+	*x = ElementResponse{}
+	mi := &file_treepair_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ElementResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ElementResponse) ProtoMessage() {}
+
+func (x *ElementResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_treepair_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ElementResponse.ProtoReflect.Descriptor instead.
+func (*ElementResponse) Descriptor() ([]byte, []int) {
+	return file_treepair_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ElementResponse) GetResult() *Element {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type MultiplyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Elements      []*Element             `protobuf:"bytes,1,rep,name=elements,proto3" json:"elements,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MultiplyRequest) Reset() {
+	*x = MultiplyRequest{}
+	mi := &file_treepair_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MultiplyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiplyRequest) ProtoMessage() {}
+
+func (x *MultiplyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_treepair_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiplyRequest.ProtoReflect.Descriptor instead.
+func (*MultiplyRequest) Descriptor() ([]byte, []int) {
+	return file_treepair_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MultiplyRequest) GetElements() []*Element {
+	if x != nil {
+		return x.Elements
+	}
+	return nil
+}
+
+type ConjugacyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	A             *Element               `protobuf:"bytes,1,opt,name=a,proto3" json:"a,omitempty"`
+	B             *Element               `protobuf:"bytes,2,opt,name=b,proto3" json:"b,omitempty"`
+	Group         Group                  `protobuf:"varint,3,opt,name=group,proto3,enum=treepair.v1.Group" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConjugacyRequest) Reset() {
+	*x = ConjugacyRequest{}
+	mi := &file_treepair_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConjugacyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConjugacyRequest) ProtoMessage() {}
+
+func (x *ConjugacyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_treepair_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConjugacyRequest.ProtoReflect.Descriptor instead.
+func (*ConjugacyRequest) Descriptor() ([]byte, []int) {
+	return file_treepair_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ConjugacyRequest) GetA() *Element {
+	if x != nil {
+		return x.A
+	}
+	return nil
+}
+
+func (x *ConjugacyRequest) GetB() *Element {
+	if x != nil {
+		return x.B
+	}
+	return nil
+}
+
+func (x *ConjugacyRequest) GetGroup() Group {
+	if x != nil {
+		return x.Group
+	}
+	return Group_GROUP_UNSPECIFIED
+}
+
+type ConjugacyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Conjugate     bool                   `protobuf:"varint,1,opt,name=conjugate,proto3" json:"conjugate,omitempty"`
+	Decided       bool                   `protobuf:"varint,2,opt,name=decided,proto3" json:"decided,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConjugacyResponse) Reset() {
+	*x = ConjugacyResponse{}
+	mi := &file_treepair_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConjugacyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConjugacyResponse) ProtoMessage() {}
+
+func (x *ConjugacyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_treepair_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConjugacyResponse.ProtoReflect.Descriptor instead.
+func (*ConjugacyResponse) Descriptor() ([]byte, []int) {
+	return file_treepair_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ConjugacyResponse) GetConjugate() bool {
+	if x != nil {
+		return x.Conjugate
+	}
+	return false
+}
+
+func (x *ConjugacyResponse) GetDecided() bool {
+	if x != nil {
+		return x.Decided
+	}
+	return false
+}
+
+type Classification struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	InF            bool                   `protobuf:"varint,1,opt,name=in_f,json=inF,proto3" json:"in_f,omitempty"`
+	InT            bool                   `protobuf:"varint,2,opt,name=in_t,json=inT,proto3" json:"in_t,omitempty"`
+	InV            bool                   `protobuf:"varint,3,opt,name=in_v,json=inV,proto3" json:"in_v,omitempty"`
+	Size           int32                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	WordLength     int32                  `protobuf:"varint,5,opt,name=word_length,json=wordLength,proto3" json:"word_length,omitempty"`
+	Order          int32                  `protobuf:"varint,6,opt,name=order,proto3" json:"order,omitempty"`
+	RotationNumber string                 `protobuf:"bytes,7,opt,name=rotation_number,json=rotationNumber,proto3" json:"rotation_number,omitempty"`
+	SlopeAtZero    int32                  `protobuf:"varint,8,opt,name=slope_at_zero,json=slopeAtZero,proto3" json:"slope_at_zero,omitempty"`
+	SlopeAtOne     int32                  `protobuf:"varint,9,opt,name=slope_at_one,json=slopeAtOne,proto3" json:"slope_at_one,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Classification) Reset() {
+	*x = Classification{}
+	mi := &file_treepair_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Classification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Classification) ProtoMessage() {}
+
+func (x *Classification) ProtoReflect() protoreflect.Message {
+	mi := &file_treepair_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Classification.ProtoReflect.Descriptor instead.
+func (*Classification) Descriptor() ([]byte, []int) {
+	return file_treepair_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Classification) GetInF() bool {
+	if x != nil {
+		return x.InF
+	}
+	return false
+}
+
+func (x *Classification) GetInT() bool {
+	if x != nil {
+		return x.InT
+	}
+	return false
+}
+
+func (x *Classification) GetInV() bool {
+	if x != nil {
+		return x.InV
+	}
+	return false
+}
+
+func (x *Classification) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Classification) GetWordLength() int32 {
+	if x != nil {
+		return x.WordLength
+	}
+	return 0
+}
+
+func (x *Classification) GetOrder() int32 {
+	if x != nil {
+		return x.Order
+	}
+	return 0
+}
+
+func (x *Classification) GetRotationNumber() string {
+	if x != nil {
+		return x.RotationNumber
+	}
+	return ""
+}
+
+func (x *Classification) GetSlopeAtZero() int32 {
+	if x != nil {
+		return x.SlopeAtZero
+	}
+	return 0
+}
+
+func (x *Classification) GetSlopeAtOne() int32 {
+	if x != nil {
+		return x.SlopeAtOne
+	}
+	return 0
+}
+
+var File_treepair_proto protoreflect.FileDescriptor
+
+const file_treepair_proto_rawDesc = "" +
+	"\n" +
+	"\x0etreepair.proto\x12\vtreepair.v1\"7\n" +
+	"\aElement\x12\x1a\n" +
+	"\balphabet\x18\x01 \x01(\tR\balphabet\x12\x10\n" +
+	"\x03dfs\x18\x02 \x01(\tR\x03dfs\"@\n" +
+	"\x0eElementRequest\x12.\n" +
+	"\aelement\x18\x01 \x01(\v2\x14.treepair.v1.ElementR\aelement\"?\n" +
+	"\x0fElementResponse\x12,\n" +
+	"\x06result\x18\x01 \x01(\v2\x14.treepair.v1.ElementR\x06result\"C\n" +
+	"\x0fMultiplyRequest\x120\n" +
+	"\belements\x18\x01 \x03(\v2\x14.treepair.v1.ElementR\belements\"\x84\x01\n" +
+	"\x10ConjugacyRequest\x12\"\n" +
+	"\x01a\x18\x01 \x01(\v2\x14.treepair.v1.ElementR\x01a\x12\"\n" +
+	"\x01b\x18\x02 \x01(\v2\x14.treepair.v1.ElementR\x01b\x12(\n" +
+	"\x05group\x18\x03 \x01(\x0e2\x12.treepair.v1.GroupR\x05group\"K\n" +
+	"\x11ConjugacyResponse\x12\x1c\n" +
+	"\tconjugate\x18\x01 \x01(\bR\tconjugate\x12\x18\n" +
+	"\adecided\x18\x02 \x01(\bR\adecided\"\x83\x02\n" +
+	"\x0eClassification\x12\x11\n" +
+	"\x04in_f\x18\x01 \x01(\bR\x03inF\x12\x11\n" +
+	"\x04in_t\x18\x02 \x01(\bR\x03inT\x12\x11\n" +
+	"\x04in_v\x18\x03 \x01(\bR\x03inV\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x05R\x04size\x12\x1f\n" +
+	"\vword_length\x18\x05 \x01(\x05R\n" +
+	"wordLength\x12\x14\n" +
+	"\x05order\x18\x06 \x01(\x05R\x05order\x12'\n" +
+	"\x0frotation_number\x18\a \x01(\tR\x0erotationNumber\x12\"\n" +
+	"\rslope_at_zero\x18\b \x01(\x05R\vslopeAtZero\x12 \n" +
+	"\fslope_at_one\x18\t \x01(\x05R\n" +
+	"slopeAtOne*8\n" +
+	"\x05Group\x12\x15\n" +
+	"\x11GROUP_UNSPECIFIED\x10\x00\x12\v\n" +
+	"\aGROUP_T\x10\x01\x12\v\n" +
+	"\aGROUP_V\x10\x022\xfa\x02\n" +
+	"\x0fTreePairService\x12F\n" +
+	"\bMultiply\x12\x1c.treepair.v1.MultiplyRequest\x1a\x1c.treepair.v1.ElementResponse\x12C\n" +
+	"\x06Invert\x12\x1b.treepair.v1.ElementRequest\x1a\x1c.treepair.v1.ElementResponse\x12E\n" +
+	"\bMinimise\x12\x1b.treepair.v1.ElementRequest\x1a\x1c.treepair.v1.ElementResponse\x12M\n" +
+	"\fAreConjugate\x12\x1d.treepair.v1.ConjugacyRequest\x1a\x1e.treepair.v1.ConjugacyResponse\x12D\n" +
+	"\bClassify\x12\x1b.treepair.v1.ElementRequest\x1a\x1b.treepair.v1.ClassificationB8Z6github.com/loeksnokes/treepair/treepairgrpc/treepairpbb\x06proto3"
+
+var (
+	file_treepair_proto_rawDescOnce sync.Once
+	file_treepair_proto_rawDescData []byte
+)
+
+func file_treepair_proto_rawDescGZIP() []byte {
+	file_treepair_proto_rawDescOnce.Do(func() {
+		file_treepair_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_treepair_proto_rawDesc), len(file_treepair_proto_rawDesc)))
+	})
+	return file_treepair_proto_rawDescData
+}
+
+var file_treepair_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_treepair_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_treepair_proto_goTypes = []any{
+	(Group)(0),                // 0: treepair.v1.Group
+	(*Element)(nil),           // 1: treepair.v1.Element
+	(*ElementRequest)(nil),    // 2: treepair.v1.ElementRequest
+	(*ElementResponse)(nil),   // 3: treepair.v1.ElementResponse
+	(*MultiplyRequest)(nil),   // 4: treepair.v1.MultiplyRequest
+	(*ConjugacyRequest)(nil),  // 5: treepair.v1.ConjugacyRequest
+	(*ConjugacyResponse)(nil), // 6: treepair.v1.ConjugacyResponse
+	(*Classification)(nil),    // 7: treepair.v1.Classification
+}
+var file_treepair_proto_depIdxs = []int32{
+	1,  // 0: treepair.v1.ElementRequest.element:type_name -> treepair.v1.Element
+	1,  // 1: treepair.v1.ElementResponse.result:type_name -> treepair.v1.Element
+	1,  // 2: treepair.v1.MultiplyRequest.elements:type_name -> treepair.v1.Element
+	1,  // 3: treepair.v1.ConjugacyRequest.a:type_name -> treepair.v1.Element
+	1,  // 4: treepair.v1.ConjugacyRequest.b:type_name -> treepair.v1.Element
+	0,  // 5: treepair.v1.ConjugacyRequest.group:type_name -> treepair.v1.Group
+	4,  // 6: treepair.v1.TreePairService.Multiply:input_type -> treepair.v1.MultiplyRequest
+	2,  // 7: treepair.v1.TreePairService.Invert:input_type -> treepair.v1.ElementRequest
+	2,  // 8: treepair.v1.TreePairService.Minimise:input_type -> treepair.v1.ElementRequest
+	5,  // 9: treepair.v1.TreePairService.AreConjugate:input_type -> treepair.v1.ConjugacyRequest
+	2,  // 10: treepair.v1.TreePairService.Classify:input_type -> treepair.v1.ElementRequest
+	3,  // 11: treepair.v1.TreePairService.Multiply:output_type -> treepair.v1.ElementResponse
+	3,  // 12: treepair.v1.TreePairService.Invert:output_type -> treepair.v1.ElementResponse
+	3,  // 13: treepair.v1.TreePairService.Minimise:output_type -> treepair.v1.ElementResponse
+	6,  // 14: treepair.v1.TreePairService.AreConjugate:output_type -> treepair.v1.ConjugacyResponse
+	7,  // 15: treepair.v1.TreePairService.Classify:output_type -> treepair.v1.Classification
+	11, // [11:16] is the sub-list for method output_type
+	6,  // [6:11] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_treepair_proto_init() }
+func file_treepair_proto_init() {
+	if File_treepair_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_treepair_proto_rawDesc), len(file_treepair_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_treepair_proto_goTypes,
+		DependencyIndexes: file_treepair_proto_depIdxs,
+		EnumInfos:         file_treepair_proto_enumTypes,
+		MessageInfos:      file_treepair_proto_msgTypes,
+	}.Build()
+	File_treepair_proto = out.File
+	file_treepair_proto_goTypes = nil
+	file_treepair_proto_depIdxs = nil
+}
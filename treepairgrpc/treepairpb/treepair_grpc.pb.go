@@ -0,0 +1,273 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: treepair.proto
+
+package treepairpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TreePairService_Multiply_FullMethodName     = "/treepair.v1.TreePairService/Multiply"
+	TreePairService_Invert_FullMethodName       = "/treepair.v1.TreePairService/Invert"
+	TreePairService_Minimise_FullMethodName     = "/treepair.v1.TreePairService/Minimise"
+	TreePairService_AreConjugate_FullMethodName = "/treepair.v1.TreePairService/AreConjugate"
+	TreePairService_Classify_FullMethodName     = "/treepair.v1.TreePairService/Classify"
+)
+
+// TreePairServiceClient is the client API for TreePairService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TreePairServiceClient interface {
+	Multiply(ctx context.Context, in *MultiplyRequest, opts ...grpc.CallOption) (*ElementResponse, error)
+	Invert(ctx context.Context, in *ElementRequest, opts ...grpc.CallOption) (*ElementResponse, error)
+	Minimise(ctx context.Context, in *ElementRequest, opts ...grpc.CallOption) (*ElementResponse, error)
+	AreConjugate(ctx context.Context, in *ConjugacyRequest, opts ...grpc.CallOption) (*ConjugacyResponse, error)
+	Classify(ctx context.Context, in *ElementRequest, opts ...grpc.CallOption) (*Classification, error)
+}
+
+type treePairServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTreePairServiceClient(cc grpc.ClientConnInterface) TreePairServiceClient {
+	return &treePairServiceClient{cc}
+}
+
+func (c *treePairServiceClient) Multiply(ctx context.Context, in *MultiplyRequest, opts ...grpc.CallOption) (*ElementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ElementResponse)
+	err := c.cc.Invoke(ctx, TreePairService_Multiply_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *treePairServiceClient) Invert(ctx context.Context, in *ElementRequest, opts ...grpc.CallOption) (*ElementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ElementResponse)
+	err := c.cc.Invoke(ctx, TreePairService_Invert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *treePairServiceClient) Minimise(ctx context.Context, in *ElementRequest, opts ...grpc.CallOption) (*ElementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ElementResponse)
+	err := c.cc.Invoke(ctx, TreePairService_Minimise_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *treePairServiceClient) AreConjugate(ctx context.Context, in *ConjugacyRequest, opts ...grpc.CallOption) (*ConjugacyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConjugacyResponse)
+	err := c.cc.Invoke(ctx, TreePairService_AreConjugate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *treePairServiceClient) Classify(ctx context.Context, in *ElementRequest, opts ...grpc.CallOption) (*Classification, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Classification)
+	err := c.cc.Invoke(ctx, TreePairService_Classify_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TreePairServiceServer is the server API for TreePairService service.
+// All implementations must embed UnimplementedTreePairServiceServer
+// for forward compatibility.
+type TreePairServiceServer interface {
+	Multiply(context.Context, *MultiplyRequest) (*ElementResponse, error)
+	Invert(context.Context, *ElementRequest) (*ElementResponse, error)
+	Minimise(context.Context, *ElementRequest) (*ElementResponse, error)
+	AreConjugate(context.Context, *ConjugacyRequest) (*ConjugacyResponse, error)
+	Classify(context.Context, *ElementRequest) (*Classification, error)
+	mustEmbedUnimplementedTreePairServiceServer()
+}
+
+// UnimplementedTreePairServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTreePairServiceServer struct{}
+
+func (UnimplementedTreePairServiceServer) Multiply(context.Context, *MultiplyRequest) (*ElementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Multiply not implemented")
+}
+func (UnimplementedTreePairServiceServer) Invert(context.Context, *ElementRequest) (*ElementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Invert not implemented")
+}
+func (UnimplementedTreePairServiceServer) Minimise(context.Context, *ElementRequest) (*ElementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Minimise not implemented")
+}
+func (UnimplementedTreePairServiceServer) AreConjugate(context.Context, *ConjugacyRequest) (*ConjugacyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AreConjugate not implemented")
+}
+func (UnimplementedTreePairServiceServer) Classify(context.Context, *ElementRequest) (*Classification, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Classify not implemented")
+}
+func (UnimplementedTreePairServiceServer) mustEmbedUnimplementedTreePairServiceServer() {}
+func (UnimplementedTreePairServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeTreePairServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TreePairServiceServer will
+// result in compilation errors.
+type UnsafeTreePairServiceServer interface {
+	mustEmbedUnimplementedTreePairServiceServer()
+}
+
+func RegisterTreePairServiceServer(s grpc.ServiceRegistrar, srv TreePairServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTreePairServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TreePairService_ServiceDesc, srv)
+}
+
+func _TreePairService_Multiply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultiplyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TreePairServiceServer).Multiply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TreePairService_Multiply_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TreePairServiceServer).Multiply(ctx, req.(*MultiplyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TreePairService_Invert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ElementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TreePairServiceServer).Invert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TreePairService_Invert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TreePairServiceServer).Invert(ctx, req.(*ElementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TreePairService_Minimise_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ElementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TreePairServiceServer).Minimise(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TreePairService_Minimise_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TreePairServiceServer).Minimise(ctx, req.(*ElementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TreePairService_AreConjugate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConjugacyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TreePairServiceServer).AreConjugate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TreePairService_AreConjugate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TreePairServiceServer).AreConjugate(ctx, req.(*ConjugacyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TreePairService_Classify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ElementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TreePairServiceServer).Classify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TreePairService_Classify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TreePairServiceServer).Classify(ctx, req.(*ElementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TreePairService_ServiceDesc is the grpc.ServiceDesc for TreePairService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TreePairService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "treepair.v1.TreePairService",
+	HandlerType: (*TreePairServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Multiply",
+			Handler:    _TreePairService_Multiply_Handler,
+		},
+		{
+			MethodName: "Invert",
+			Handler:    _TreePairService_Invert_Handler,
+		},
+		{
+			MethodName: "Minimise",
+			Handler:    _TreePairService_Minimise_Handler,
+		},
+		{
+			MethodName: "AreConjugate",
+			Handler:    _TreePairService_AreConjugate_Handler,
+		},
+		{
+			MethodName: "Classify",
+			Handler:    _TreePairService_Classify_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "treepair.proto",
+}
@@ -0,0 +1,191 @@
+package treepair
+
+import (
+	"errors"
+	"strings"
+)
+
+// Automorphism is an automorphism of V, or of the groups F and T it preserves.
+type Automorphism interface {
+	// Apply returns the image of tp, which is not modified.
+	Apply(tp TreePair) (TreePair, error)
+}
+
+// letterAutomorphism renames the letters of every address, conjugating by the homeomorphism
+// of the Cantor set doing so.
+type letterAutomorphism struct {
+	alphabet []rune
+	image    map[rune]rune
+	order    int
+}
+
+// AlphabetPermutation returns the automorphism renaming the k-th letter of alphaStr as the
+// k-th letter of image in every address: it carries the element d -> r to the element
+// σ(d) -> σ(r).  image must be a permutation of alphaStr.  The automorphism preserves F and T
+// only if it reverses the alphabet, as Flip does, or is the identity.
+func AlphabetPermutation(alphaStr, image string) (Automorphism, error) {
+	alpha, perm := []rune(alphaStr), []rune(image)
+	if len(alpha) != len(perm) {
+		return nil, errors.New("AlphabetPermutation(): " + image + " is not a permutation of " + alphaStr)
+	}
+	phi := &letterAutomorphism{alphabet: alpha, image: make(map[rune]rune, len(alpha))}
+	for k, c := range alpha {
+		if !strings.ContainsRune(alphaStr, perm[k]) || strings.ContainsRune(string(perm[:k]), perm[k]) {
+			return nil, errors.New("AlphabetPermutation(): " + image + " is not a permutation of " + alphaStr)
+		}
+		phi.image[c] = perm[k]
+	}
+	phi.order = 1
+	for _, c := range alpha {
+		length := 1
+		for d := phi.image[c]; d != c; d = phi.image[d] {
+			length++
+		}
+		phi.order = phi.order / gcd(phi.order, length) * length
+	}
+	return phi, nil
+}
+
+// Flip returns the automorphism reversing the alphabet, which for "01" is conjugation by the
+// reflection x -> 1-x of the interval, the flip of F.
+func Flip(alphaStr string) (Automorphism, error) {
+	reversed := []rune(alphaStr)
+	for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+		reversed[i], reversed[j] = reversed[j], reversed[i]
+	}
+	return AlphabetPermutation(alphaStr, string(reversed))
+}
+
+// rename applies the renaming of letters to w.
+func (phi *letterAutomorphism) rename(w string) string {
+	renamed := []rune(w)
+	for k, c := range renamed {
+		renamed[k] = phi.image[c]
+	}
+	return string(renamed)
+}
+
+func (phi *letterAutomorphism) Apply(tp TreePair) (TreePair, error) {
+	if string(phi.alphabet) != string(tp.Alphabet()) {
+		return nil, errors.New("Apply(): element is not over the alphabet " + string(phi.alphabet))
+	}
+	m := newLeafMap(tp)
+	renamed := make(leafMap, len(m))
+	for d, r := range m {
+		renamed[phi.rename(d)] = phi.rename(r)
+	}
+	image := renamed.treePair(phi.alphabet)
+	image.Minimise()
+	return image, nil
+}
+
+// innerAutomorphism conjugates by an element c, carrying x to c^-1 x c.
+type innerAutomorphism struct {
+	c, cInv *treePair
+}
+
+// InnerAutomorphism returns the automorphism carrying x to c^-1 x c, as Multiply composes.  c
+// itself is not modified.
+func InnerAutomorphism(c TreePair) Automorphism {
+	cInv := clone(c)
+	cInv.Invert()
+	return &innerAutomorphism{c: clone(c), cInv: cInv}
+}
+
+func (phi *innerAutomorphism) Apply(tp TreePair) (TreePair, error) {
+	if string(phi.c.Alphabet()) != string(tp.Alphabet()) {
+		return nil, errors.New("Apply(): element is not over the alphabet " + string(phi.c.Alphabet()))
+	}
+	return Multiply(Multiply(clone(phi.cInv), clone(tp)), clone(phi.c)), nil
+}
+
+// smallestGroup returns the least of F, T and V containing every element of tps.
+func smallestGroup(tps ...TreePair) GroupKind {
+	g := GroupF
+	for _, tp := range tps {
+		switch {
+		case !tp.InT():
+			return GroupV
+		case !tp.InF():
+			g = GroupT
+		}
+	}
+	return g
+}
+
+// SearchTwistedConjugator searches for an h with b = h^-1 a phi(h), making a and b
+// phi-twisted conjugate, and returns it if found.  It is a bounded search, not a decision
+// procedure: ok is true when h is found, false when a and b are shown not to be twisted
+// conjugate, and ErrConjugacyUndecided is returned when neither happens.  h is taken from
+// the least of F, T and V containing a and b (and c, for phi = InnerAutomorphism(c)), which
+// phi should preserve.  For phi = InnerAutomorphism(c) this is ordinary conjugacy of a c^-1
+// and b c^-1: decided by ConjugatorInV in V, and searched for as by SearchConjugatorInT in T
+// and among elements of F with up to maxLeaves leaves in F.  For a renaming of letters of
+// order n, b twisted conjugate to a by h makes the norm b phi(b) ... phi^(n-1)(b) conjugate
+// to the norm of a by h, so elements with norms not conjugate in V are not twisted
+// conjugate.  Otherwise h is searched for among elements with up to maxLeaves leaves.
+// Neither a nor b is modified.
+func SearchTwistedConjugator(a, b TreePair, phi Automorphism, maxLeaves int) (TreePair, bool, error) {
+	if string(a.Alphabet()) != string(b.Alphabet()) {
+		return nil, false, errors.New("SearchTwistedConjugator(): elements are over different alphabets")
+	}
+	g := smallestGroup(a, b)
+
+	if inner, ok := phi.(*innerAutomorphism); ok {
+		if string(inner.c.Alphabet()) != string(a.Alphabet()) {
+			return nil, false, errors.New("SearchTwistedConjugator(): automorphism is over a different alphabet")
+		}
+		g = smallestGroup(a, b, inner.c)
+		aTwisted := Multiply(clone(a), clone(inner.cInv))
+		bTwisted := Multiply(clone(b), clone(inner.cInv))
+		var h TreePair
+		var err error
+		switch g {
+		case GroupV:
+			h, err = ConjugatorInV(aTwisted, bTwisted)
+		case GroupT:
+			h, err = SearchConjugatorInT(aTwisted, bTwisted, maxLeaves)
+		default:
+			if found := findConjugator(aTwisted, bTwisted, GroupF, maxLeaves); nil != found {
+				h = found
+			} else {
+				err = ErrConjugacyUndecided
+			}
+		}
+		return h, nil != h, err
+	}
+
+	if letters, ok := phi.(*letterAutomorphism); ok {
+		if string(letters.alphabet) != string(a.Alphabet()) {
+			return nil, false, errors.New("SearchTwistedConjugator(): automorphism is over a different alphabet")
+		}
+		normA, normB := clone(a), clone(b)
+		imageA, imageB := TreePair(clone(a)), TreePair(clone(b))
+		for k := 1; k < letters.order; k++ {
+			imageA, _ = phi.Apply(imageA)
+			imageB, _ = phi.Apply(imageB)
+			normA = Multiply(normA, clone(imageA))
+			normB = Multiply(normB, clone(imageB))
+		}
+		if h, err := ConjugatorInV(normA, normB); nil == h && nil == err {
+			return nil, false, nil
+		}
+	}
+
+	var found *treePair
+	for leaves := 1; leaves <= maxLeaves && nil == found; leaves++ {
+		forEachElement(a.Alphabet(), leaves, g, func(h *treePair) bool {
+			image, err := phi.Apply(h)
+			if nil != err || !EqualsAsElements(Multiply(clone(a), clone(image)), Multiply(clone(h), clone(b))) {
+				return true
+			}
+			found = h
+			found.Minimise()
+			return false
+		})
+	}
+	if nil != found {
+		return found, true, nil
+	}
+	return nil, false, ErrConjugacyUndecided
+}
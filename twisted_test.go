@@ -0,0 +1,98 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwisted(t *testing.T) {
+
+	isTwistedConjugator := func(t *testing.T, a, b, h TreePair, phi Automorphism) {
+		t.Helper()
+		assert.NotNil(t, h)
+		image, err := phi.Apply(h)
+		assert.Nil(t, err)
+		assert.True(t, EqualsAsElements(Multiply(clone(a), clone(image)), Multiply(clone(h), clone(b))))
+	}
+
+	t.Run("Automorphism Apply test", func(t *testing.T) {
+		flip, err := Flip("01")
+		assert.Nil(t, err)
+		x0, _ := GeneratorX(0)
+		image, err := flip.Apply(x0)
+		assert.Nil(t, err)
+		x0Inv := clone(x0)
+		x0Inv.Invert()
+		assert.True(t, EqualsAsElements(x0Inv, image), "the flip inverts x0.")
+		assert.True(t, image.InF())
+
+		rotate, err := AlphabetPermutation("012", "120")
+		assert.Nil(t, err)
+		tp, _ := ParseElement("012", "{1100000,1010000,0 1 2 3 4}")
+		image = tp
+		for k := 0; k < 3; k++ {
+			image, err = rotate.Apply(image)
+			assert.Nil(t, err)
+		}
+		assert.True(t, EqualsAsElements(tp, image), "the renaming has order 3.")
+		_, err = rotate.Apply(x0)
+		assert.NotNil(t, err)
+
+		_, err = AlphabetPermutation("012", "112")
+		assert.NotNil(t, err)
+		_, err = AlphabetPermutation("012", "10")
+		assert.NotNil(t, err)
+
+		c, _ := GeneratorC()
+		image, err = InnerAutomorphism(c).Apply(x0)
+		assert.Nil(t, err)
+		want, _ := EvaluateWord("01", "C^-1 A C")
+		assert.True(t, EqualsAsElements(want, image))
+	})
+
+	t.Run("SearchTwistedConjugator flip test", func(t *testing.T) {
+		flip, _ := Flip("01")
+		a, _ := EvaluateWord("01", "A B")
+		h, _ := EvaluateWord("01", "B")
+		hInv := clone(h)
+		hInv.Invert()
+		image, _ := flip.Apply(h)
+		b := Multiply(Multiply(hInv, clone(a)), image)
+		found, ok, err := SearchTwistedConjugator(a, b, flip, 5)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		isTwistedConjugator(t, a, b, found, flip)
+		assert.True(t, found.InF())
+
+		// the search gives up within its bound.
+		_, ok, err = SearchTwistedConjugator(a, b, flip, 1)
+		assert.ErrorIs(t, err, ErrConjugacyUndecided)
+		assert.False(t, ok)
+
+		// the norm of x1 is not the identity.
+		x1, _ := GeneratorX(1)
+		_, ok, err = SearchTwistedConjugator(identityOver([]rune("01")), x1, flip, 5)
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("SearchTwistedConjugator inner test", func(t *testing.T) {
+		c, _ := GeneratorC()
+		phi := InnerAutomorphism(c)
+		a, _ := EvaluateWord("01", "A")
+		h, _ := EvaluateWord("01", "B^-1 C")
+		hInv := clone(h)
+		hInv.Invert()
+		image, _ := phi.Apply(h)
+		b := Multiply(Multiply(hInv, clone(a)), image)
+		found, ok, err := SearchTwistedConjugator(a, b, phi, 5)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		isTwistedConjugator(t, a, b, found, phi)
+
+		ternary, _ := NewTreePairAlpha("012")
+		_, _, err = SearchTwistedConjugator(a, ternary, phi, 5)
+		assert.NotNil(t, err)
+	})
+}
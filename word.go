@@ -0,0 +1,186 @@
+package treepair
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/loeksnokes/prefcode"
+)
+
+// EvaluateWord multiplies out a word in the standard generators, e.g. "x0 x1^-2 x0" for F or
+// "A B C^-1 pi0" for T and V.  Letters are separated by white space and may carry an integer
+// exponent after `^`.  Recognised letters are x0, x1, x2, ... and A, B, C, pi0 (or π0).  The
+// product is taken left to right, as in Multiply.  The generators live over the binary alphabet,
+// so alpha must be "01".  The empty word evaluates to the identity.
+func EvaluateWord(alpha string, word string) (TreePair, error) {
+	if "01" != string(prefcode.MakeAlphabet(alpha)) {
+		return nil, errors.New("EvaluateWord(): generators are only defined over the alphabet 01, not " + alpha)
+	}
+
+	answer := identityOver(prefcode.MakeAlphabet(alpha))
+	for _, token := range strings.Fields(word) {
+		letter, pow, err := parseWordToken(token)
+		if nil != err {
+			return nil, err
+		}
+		gen, err := generatorByName(letter)
+		if nil != err {
+			return nil, err
+		}
+		answer = Multiply(answer, Power(gen, pow))
+	}
+	return answer, nil
+}
+
+// parseWordToken splits a token like "x1^-2" into its letter and exponent.
+func parseWordToken(token string) (letter string, pow int, err error) {
+	parts := strings.Split(token, "^")
+	switch len(parts) {
+	case 1:
+		return parts[0], 1, nil
+	case 2:
+		pow, err = strconv.Atoi(parts[1])
+		if nil != err || "" == parts[0] {
+			return "", 0, errors.New("parseWordToken(): bad exponent in " + token)
+		}
+		return parts[0], pow, nil
+	}
+	return "", 0, errors.New("parseWordToken(): too many `^` in " + token)
+}
+
+// generatorByName returns a fresh copy of the named standard generator.
+func generatorByName(letter string) (*treePair, error) {
+	switch letter {
+	case "A":
+		return GeneratorA()
+	case "B":
+		return GeneratorB()
+	case "C":
+		return GeneratorC()
+	case "pi0", "π0":
+		return GeneratorPi0()
+	}
+	if strings.HasPrefix(letter, "x") {
+		n, err := strconv.Atoi(strings.TrimPrefix(letter, "x"))
+		if nil == err {
+			return GeneratorX(n)
+		}
+	}
+	return nil, errors.New("generatorByName(): unknown generator " + letter)
+}
+
+// WordInF returns the normal form of an element of F as a word in x0, x1, x2, ... which
+// EvaluateWord turns back into the element.  Writing the minimised element as the pair of
+// binary trees (D, R), each tree T with leaf exponents e_0, ..., e_n determines the positive
+// element x_n^e_n ... x_1^e_1 x_0^e_0 carrying the right vine to T, and the element is
+//
+//	x_0^-d_0 x_1^-d_1 ... x_n^-d_n  x_n^r_n ... x_1^r_1 x_0^r_0
+//
+// where d_k and r_k are the leaf exponents of D and R (see NormalFormF).  This is the p q^-1
+// normal form of Cannon, Floyd and Parry read with Multiply's left to right product.  The
+// identity gives "".
+func WordInF(tp TreePair) (string, error) {
+	nf, err := NormalFormF(tp)
+	if nil != err {
+		return "", err
+	}
+	return nf.String(), nil
+}
+
+// WordInFOverX0X1 returns the normal form of WordInF rewritten over x0 and x1 alone, using
+// x_n = x0^(n-1) x1 x0^-(n-1) and cancelling adjacent powers of x0.
+func WordInFOverX0X1(tp TreePair) (string, error) {
+	nf, err := NormalFormF(tp)
+	if nil != err {
+		return "", err
+	}
+
+	// build the word as a sequence of (letter, power) with letter 0 or 1, merging x0 powers.
+	type syllable struct{ letter, pow int }
+	var word []syllable
+	push := func(letter, pow int) {
+		if 0 == pow {
+			return
+		}
+		if last := len(word) - 1; last >= 0 && word[last].letter == letter && 0 == letter {
+			word[last].pow += pow
+			if 0 == word[last].pow {
+				word = word[:last]
+			}
+			return
+		}
+		word = append(word, syllable{letter, pow})
+	}
+	pushXn := func(n, pow int) {
+		if 0 == pow {
+			return
+		}
+		if n < 2 {
+			push(n, pow)
+			return
+		}
+		push(0, n-1)
+		push(1, pow)
+		push(0, 1-n)
+	}
+
+	for k := 0; k < len(nf.Negative); k++ {
+		pushXn(k, -nf.Negative[k])
+	}
+	for k := len(nf.Positive) - 1; k >= 0; k-- {
+		pushXn(k, nf.Positive[k])
+	}
+
+	var letters []string
+	for _, v := range word {
+		letters = appendPower(letters, "x"+strconv.Itoa(v.letter), v.pow)
+	}
+	return strings.Join(letters, " "), nil
+}
+
+// appendPower appends letter^pow to letters in the format EvaluateWord reads, skipping pow == 0.
+func appendPower(letters []string, letter string, pow int) []string {
+	switch pow {
+	case 0:
+		return letters
+	case 1:
+		return append(letters, letter)
+	}
+	return append(letters, letter+"^"+strconv.Itoa(pow))
+}
+
+// leafExponentsF minimises a copy of tp, checks it is an element of F over "01" and returns the
+// leaf exponents of its domain and range trees.
+func leafExponentsF(tp TreePair) (domExp, ranExp []int, err error) {
+	if "01" != string(tp.Alphabet()) {
+		return nil, nil, errors.New("leafExponentsF(): element is not over the alphabet 01")
+	}
+	min := clone(tp)
+	min.Minimise()
+	if !min.InF() {
+		return nil, nil, errors.New("leafExponentsF(): element is not in F")
+	}
+	return leafExponents(min.CodeDomain()), leafExponents(min.CodeRange()), nil
+}
+
+// leafExponents lists, for the leaves of a binary prefix code in dictionary order, the length
+// of the maximal run of left edges above the leaf which does not reach the right side of the
+// tree.
+func leafExponents(pc prefcode.PrefCode) []int {
+	leaves := sortedLeaves(pc)
+	exps := make([]int, len(leaves))
+	for k, leaf := range leaves {
+		if prefcode.EmptyString == leaf {
+			continue
+		}
+		stem := strings.TrimRight(leaf, "0")
+		run := len(leaf) - len(stem)
+		if run > 0 && "" == strings.Trim(stem, "1") {
+			// the top left edge hangs off the right side of the tree.
+			run--
+		}
+		exps[k] = run
+	}
+	return exps
+}
@@ -0,0 +1,94 @@
+package treepair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWord(t *testing.T) {
+
+	assertCorrectMessage := func(t *testing.T, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+
+	t.Run("EvaluateWord F test", func(t *testing.T) {
+		got, err := EvaluateWord("01", "x0 x1 x0^-1")
+		assert.Nil(t, err)
+		x2, _ := GeneratorX(2)
+		assertCorrectMessage(t, got.FullString(), x2.FullString())
+
+		got, err = EvaluateWord("01", "x1^2 x1^-2")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got.FullString(), "{D: [𝛆 0] || R: [𝛆 0]}")
+
+		got, err = EvaluateWord("01", "")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got.FullString(), "{D: [𝛆 0] || R: [𝛆 0]}")
+	})
+
+	t.Run("EvaluateWord T and V test", func(t *testing.T) {
+		got, err := EvaluateWord("01", "B C A^-1 B")
+		assert.Nil(t, err)
+		c, _ := GeneratorC()
+		assertCorrectMessage(t, got.FullString(), c.FullString())
+
+		got, err = EvaluateWord("01", "pi0 π0")
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got.FullString(), "{D: [𝛆 0] || R: [𝛆 0]}")
+	})
+
+	t.Run("EvaluateWord errors test", func(t *testing.T) {
+		_, err := EvaluateWord("012", "x0")
+		assert.NotNil(t, err, "ternary alphabet should be rejected.")
+		_, err = EvaluateWord("01", "y0")
+		assert.NotNil(t, err, "unknown letter should be rejected.")
+		_, err = EvaluateWord("01", "x0^a")
+		assert.NotNil(t, err, "bad exponent should be rejected.")
+		_, err = EvaluateWord("01", "x0^1^2")
+		assert.NotNil(t, err, "double exponent should be rejected.")
+	})
+
+	t.Run("WordInF test", func(t *testing.T) {
+		g, _ := EvaluateWord("01", "x0 x1")
+		got, err := WordInF(g)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got, "x2 x0")
+
+		g, _ = EvaluateWord("01", "x1^-1 x0^2 x3 x0^-1")
+		got, err = WordInF(g)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got, "x1^-1 x5 x0")
+
+		g, _ = EvaluateWord("01", "")
+		got, err = WordInF(g)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got, "")
+
+		c, _ := GeneratorC()
+		_, err = WordInF(c)
+		assert.NotNil(t, err, "C is not in F.")
+	})
+
+	t.Run("WordInF round trip test", func(t *testing.T) {
+		for _, w := range []string{"x0 x1 x2^-1", "x3^2 x0^-3 x1", "x1^-1 x0^-1 x1 x0", "x5 x2^-2 x4^-1 x0^3"} {
+			g, _ := EvaluateWord("01", w)
+			for _, wordOf := range []func(TreePair) (string, error){WordInF, WordInFOverX0X1} {
+				nf, err := wordOf(g)
+				assert.Nil(t, err)
+				back, _ := EvaluateWord("01", nf)
+				assertCorrectMessage(t, back.FullString(), g.FullString())
+			}
+		}
+	})
+
+	t.Run("WordInFOverX0X1 test", func(t *testing.T) {
+		g, _ := EvaluateWord("01", "x2 x0")
+		got, err := WordInFOverX0X1(g)
+		assert.Nil(t, err)
+		assertCorrectMessage(t, got, "x0 x1")
+	})
+}